@@ -0,0 +1,281 @@
+// Command jackfruitctl queries the serving API for operators. Responses are printed as
+// indented JSON; verify exits non-zero when any checked entry is flagged.
+//
+// Usage:
+//
+//	jackfruitctl [-url URL] env -lat LAT -lon LON -timestamp RFC3339 -variables a,b [-partial]
+//	jackfruitctl [-url URL] catalog list [-variable V] [-dataset D] [-from T] [-to T] [-limit N]
+//	jackfruitctl [-url URL] catalog get|quality ID
+//	jackfruitctl [-url URL] run ID
+//	jackfruitctl [-url URL] coverage [-variables a,b]
+//	jackfruitctl [-url URL] verify [-parallel 4] [catalog list flags]
+//	jackfruitctl migrate [up|status]
+//	jackfruitctl retention [plan|run]
+//
+// The URL defaults to JACKFRUIT_URL, then http://localhost:8080. migrate and retention
+// don't use it: like cmd/migrate and cmd/retention, they connect to ClickHouse from the
+// CLICKHOUSE_* configuration.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/sync/errgroup"
+
+	"github.com/kacper-wojtaszczyk/jackfruit/serving-go/internal/api"
+	"github.com/kacper-wojtaszczyk/jackfruit/serving-go/internal/apiclient"
+	"github.com/kacper-wojtaszczyk/jackfruit/serving-go/internal/domain"
+	"github.com/kacper-wojtaszczyk/jackfruit/serving-go/internal/ops"
+)
+
+// errFlagged makes verify exit non-zero after it has printed its findings.
+var errFlagged = errors.New("flagged entries found")
+
+func run(ctx context.Context, client *apiclient.Client, args []string) error {
+	if len(args) == 0 {
+		return errors.New("missing command (expected env, catalog, run, coverage, verify, migrate or retention)")
+	}
+	command, args := args[0], args[1:]
+
+	switch command {
+	case "env":
+		return env(ctx, client, args)
+	case "catalog":
+		return catalog(ctx, client, args)
+	case "run":
+		id, err := parseID(args)
+		if err != nil {
+			return err
+		}
+		return printJSON(client.GetRun(ctx, id))
+	case "coverage":
+		flags := flag.NewFlagSet("coverage", flag.ContinueOnError)
+		variables := flags.String("variables", "", "comma-separated variables (default all)")
+		if err := flags.Parse(args); err != nil {
+			return err
+		}
+		return printJSON(client.Coverage(ctx, splitList(*variables)...))
+	case "verify":
+		return verify(ctx, client, args)
+	case "migrate":
+		return ops.Migrate(ctx, subcommand(args, "up"), os.Stdout)
+	case "retention":
+		return ops.Retention(ctx, subcommand(args, "plan"), os.Stdout, os.Stderr)
+	default:
+		return fmt.Errorf("unknown command %q (expected env, catalog, run, coverage, verify, migrate or retention)", command)
+	}
+}
+
+func env(ctx context.Context, client *apiclient.Client, args []string) error {
+	flags := flag.NewFlagSet("env", flag.ContinueOnError)
+	lat := flags.Float64("lat", 0, "latitude")
+	lon := flags.Float64("lon", 0, "longitude")
+	timestamp := flags.String("timestamp", "", "requested timestamp (RFC 3339, default now)")
+	variables := flags.String("variables", "", "comma-separated variables")
+	partial := flags.Bool("partial", false, "return found variables instead of failing on missing ones")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	ts := time.Now()
+	if *timestamp != "" {
+		var err error
+		if ts, err = time.Parse(time.RFC3339, *timestamp); err != nil {
+			return fmt.Errorf("parse timestamp: %w", err)
+		}
+	}
+	return printJSON(client.Environmental(ctx, api.EnvironmentalRequest{
+		Lat:       float32(*lat),
+		Lon:       float32(*lon),
+		Timestamp: ts,
+		Variables: splitList(*variables),
+		Partial:   *partial,
+	}))
+}
+
+func catalog(ctx context.Context, client *apiclient.Client, args []string) error {
+	if len(args) == 0 {
+		return errors.New("missing catalog command (expected list, get or quality)")
+	}
+	command, args := args[0], args[1:]
+
+	switch command {
+	case "list":
+		filter, err := parseFilter("catalog list", args)
+		if err != nil {
+			return err
+		}
+		return printJSON(client.ListCatalog(ctx, *filter))
+	case "get":
+		id, err := parseID(args)
+		if err != nil {
+			return err
+		}
+		return printJSON(client.GetCatalogEntry(ctx, id))
+	case "quality":
+		id, err := parseID(args)
+		if err != nil {
+			return err
+		}
+		return printJSON(client.GetQuality(ctx, id))
+	default:
+		return fmt.Errorf("unknown catalog command %q (expected list, get or quality)", command)
+	}
+}
+
+// subcommand returns the only argument, or fallback without one.
+func subcommand(args []string, fallback string) string {
+	if len(args) == 0 {
+		return fallback
+	}
+	return args[0]
+}
+
+// verify checks the quality of every listed entry, at most -parallel at a time, and prints
+// those with anomaly flags in listing order. The listing's limit bounds the requests made.
+func verify(ctx context.Context, client *apiclient.Client, args []string) error {
+	filter, parallel, err := parseVerify(args)
+	if err != nil {
+		return err
+	}
+	entries, err := client.ListCatalog(ctx, *filter)
+	if err != nil {
+		return err
+	}
+
+	flags := make([][]string, len(entries))
+	g, ctx := errgroup.WithContext(ctx)
+	g.SetLimit(parallel)
+	for i, entry := range entries {
+		g.Go(func() error {
+			quality, err := client.GetQuality(ctx, entry.ID)
+			if err != nil {
+				return fmt.Errorf("quality of %s: %w", entry.ID, err)
+			}
+			flags[i] = quality.Flags
+			return nil
+		})
+	}
+	if err := g.Wait(); err != nil {
+		return err
+	}
+
+	flagged := 0
+	for i, entry := range entries {
+		if len(flags[i]) == 0 {
+			continue
+		}
+		flagged++
+		fmt.Printf("%s\t%s\t%s\t%s\n", entry.ID, entry.Variable, entry.Timestamp.Format(time.RFC3339), strings.Join(flags[i], ","))
+	}
+	fmt.Fprintf(os.Stderr, "checked %d entries, %d flagged\n", len(entries), flagged)
+	if flagged > 0 {
+		return errFlagged
+	}
+	return nil
+}
+
+// verifyParallel is how many quality requests verify has in flight by default.
+const verifyParallel = 4
+
+func parseVerify(args []string) (*domain.CatalogFilter, int, error) {
+	flags := flag.NewFlagSet("verify", flag.ContinueOnError)
+	parallel := flags.Int("parallel", verifyParallel, "quality requests in flight at once")
+	filter, err := parseFilterFlags(flags, args)
+	if err != nil {
+		return nil, 0, err
+	}
+	if *parallel < 1 {
+		return nil, 0, fmt.Errorf("parallel must be at least 1, %d given", *parallel)
+	}
+	return filter, *parallel, nil
+}
+
+func parseFilter(name string, args []string) (*domain.CatalogFilter, error) {
+	return parseFilterFlags(flag.NewFlagSet(name, flag.ContinueOnError), args)
+}
+
+// parseFilterFlags adds the catalog list flags to flags and parses args.
+func parseFilterFlags(flags *flag.FlagSet, args []string) (*domain.CatalogFilter, error) {
+	variable := flags.String("variable", "", "variable name")
+	dataset := flags.String("dataset", "", "lineage dataset")
+	from := flags.String("from", "", "earliest timestamp (RFC 3339)")
+	to := flags.String("to", "", "latest timestamp (RFC 3339)")
+	limit := flags.Int("limit", 0, "maximum entries (server default 100)")
+	if err := flags.Parse(args); err != nil {
+		return nil, err
+	}
+
+	filter := &domain.CatalogFilter{Variable: *variable, Dataset: *dataset, Limit: *limit}
+	var err error
+	if *from != "" {
+		if filter.From, err = time.Parse(time.RFC3339, *from); err != nil {
+			return nil, fmt.Errorf("parse from: %w", err)
+		}
+	}
+	if *to != "" {
+		if filter.To, err = time.Parse(time.RFC3339, *to); err != nil {
+			return nil, fmt.Errorf("parse to: %w", err)
+		}
+	}
+	return filter, nil
+}
+
+func parseID(args []string) (uuid.UUID, error) {
+	if len(args) != 1 {
+		return uuid.Nil, errors.New("expected exactly one id")
+	}
+	id, err := uuid.Parse(args[0])
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("parse id: %w", err)
+	}
+	return id, nil
+}
+
+func splitList(list string) []string {
+	var items []string
+	for item := range strings.SplitSeq(list, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+func printJSON[T any](v T, err error) error {
+	if err != nil {
+		return err
+	}
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(v)
+}
+
+func main() {
+	baseURL := os.Getenv("JACKFRUIT_URL")
+	if baseURL == "" {
+		baseURL = "http://localhost:8080"
+	}
+	flag.StringVar(&baseURL, "url", baseURL, "serving API base URL")
+	flag.Parse()
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	if err := run(ctx, apiclient.New(baseURL), flag.Args()); err != nil {
+		if !errors.Is(err, errFlagged) && !errors.Is(err, flag.ErrHelp) {
+			fmt.Fprintln(os.Stderr, "jackfruitctl:", err)
+		}
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,137 @@
+// Command loadtest offers a weighted mix of point, batch and series queries to the serving API
+// at a target rate and prints latency percentiles per scenario.
+//
+// Usage:
+//
+//	loadtest [-url URL] [-rps 50] [-duration 30s] [-mix point=8,batch=2,series=0]
+//	         [-variables pm2p5,pm10,no2,o3] [-bbox minLat,minLon,maxLat,maxLon]
+//	         [-from RFC3339] [-to RFC3339] [-max-points 500] [-max-in-flight 256]
+//	         [-timeout 10s] [-seed 1]
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"maps"
+	"net/http"
+	"os"
+	"os/signal"
+	"slices"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/kacper-wojtaszczyk/jackfruit/serving-go/internal/apiclient"
+	"github.com/kacper-wojtaszczyk/jackfruit/serving-go/internal/domain"
+	"github.com/kacper-wojtaszczyk/jackfruit/serving-go/internal/loadtest"
+)
+
+func parseConfig(args []string) (*loadtest.Config, error) {
+	baseURL := os.Getenv("JACKFRUIT_URL")
+	if baseURL == "" {
+		baseURL = "http://localhost:8080"
+	}
+	flags := flag.NewFlagSet("loadtest", flag.ContinueOnError)
+	flags.StringVar(&baseURL, "url", baseURL, "serving API base URL")
+	rps := flags.Float64("rps", 50, "target requests per second")
+	duration := flags.Duration("duration", 30*time.Second, "how long to offer load")
+	mix := flags.String("mix", "point=8,batch=2", "scenario weights (point, batch, series)")
+	variables := flags.String("variables", "pm2p5,pm10,no2,o3", "comma-separated variables to query")
+	bbox := flags.String("bbox", "35,-10,70,40", "query extent as minLat,minLon,maxLat,maxLon")
+	from := flags.String("from", "", "earliest requested timestamp (RFC 3339, default 24h ago)")
+	to := flags.String("to", "", "latest requested timestamp (RFC 3339, default now)")
+	maxPoints := flags.Int("max-points", 500, "max_points of series requests; 0 returns every sample")
+	maxInFlight := flags.Int("max-in-flight", 256, "concurrent request cap; requests beyond it are dropped")
+	timeout := flags.Duration("timeout", 10*time.Second, "per-request timeout")
+	seed := flags.Uint64("seed", 1, "random seed")
+	if err := flags.Parse(args); err != nil {
+		return nil, err
+	}
+
+	target := loadtest.Target{To: time.Now().UTC()}
+	target.From = target.To.Add(-24 * time.Hour)
+	var err error
+	if *from != "" {
+		if target.From, err = time.Parse(time.RFC3339, *from); err != nil {
+			return nil, fmt.Errorf("parse from: %w", err)
+		}
+	}
+	if *to != "" {
+		if target.To, err = time.Parse(time.RFC3339, *to); err != nil {
+			return nil, fmt.Errorf("parse to: %w", err)
+		}
+	}
+	if target.Extent, err = parseBoundingBox(*bbox); err != nil {
+		return nil, err
+	}
+	for variable := range strings.SplitSeq(*variables, ",") {
+		if variable = strings.TrimSpace(variable); variable != "" {
+			target.Variables = append(target.Variables, variable)
+		}
+	}
+	if len(target.Variables) == 0 {
+		return nil, fmt.Errorf("no variables provided")
+	}
+
+	weights, err := loadtest.ParseMix(*mix)
+	if err != nil {
+		return nil, err
+	}
+	// The default transport keeps only two idle connections per host, which would make
+	// most requests at any real rate pay for a new connection.
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.MaxIdleConnsPerHost = *maxInFlight
+	client := apiclient.New(baseURL, apiclient.WithHTTPClient(&http.Client{Transport: transport}))
+	cfg := &loadtest.Config{RPS: *rps, Duration: *duration, MaxInFlight: *maxInFlight, Timeout: *timeout, Seed: *seed}
+	for _, name := range slices.Sorted(maps.Keys(weights)) {
+		weight := weights[name]
+		switch name {
+		case "point":
+			cfg.Scenarios = append(cfg.Scenarios, loadtest.PointScenario(client, target, weight))
+		case "batch":
+			cfg.Scenarios = append(cfg.Scenarios, loadtest.BatchScenario(client, target, weight))
+		case "series":
+			cfg.Scenarios = append(cfg.Scenarios, loadtest.SeriesScenario(client, target, *maxPoints, weight))
+		default:
+			return nil, fmt.Errorf("mix: unknown scenario %q (expected point, batch or series)", name)
+		}
+	}
+
+	return cfg, nil
+}
+
+func parseBoundingBox(s string) (domain.BoundingBox, error) {
+	parts := strings.Split(s, ",")
+	if len(parts) != 4 {
+		return domain.BoundingBox{}, fmt.Errorf("bbox: expected minLat,minLon,maxLat,maxLon, %q given", s)
+	}
+	var coords [4]float32
+	for i, part := range parts {
+		f, err := strconv.ParseFloat(strings.TrimSpace(part), 32)
+		if err != nil {
+			return domain.BoundingBox{}, fmt.Errorf("bbox: %w", err)
+		}
+		coords[i] = float32(f)
+	}
+	return domain.BoundingBox{MinLat: coords[0], MinLon: coords[1], MaxLat: coords[2], MaxLon: coords[3]}, nil
+}
+
+func main() {
+	cfg, err := parseConfig(os.Args[1:])
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "loadtest:", err)
+		os.Exit(2)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	report, err := loadtest.Run(ctx, *cfg)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "loadtest:", err)
+		os.Exit(1)
+	}
+	report.Write(os.Stdout)
+}
@@ -0,0 +1,31 @@
+// Command migrate applies or lists the embedded ClickHouse schema migrations.
+//
+// Usage:
+//
+//	migrate [up|status]
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/kacper-wojtaszczyk/jackfruit/serving-go/internal/ops"
+)
+
+func main() {
+	command := "up"
+	if len(os.Args) > 1 {
+		command = os.Args[1]
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	if err := ops.Migrate(ctx, command, os.Stdout); err != nil {
+		fmt.Fprintln(os.Stderr, "migrate:", err)
+		os.Exit(1)
+	}
+}
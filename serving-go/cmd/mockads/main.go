@@ -0,0 +1,71 @@
+// Command mockads serves a fixture GRIB in place of the Copernicus ADS retrieve API, so the
+// Dagster ingestion job can run end to end without credentials or network access. Point
+// ADS_BASE_URL at it and set ADS_API_KEY to any UID:KEY.
+//
+// Usage:
+//
+//	mockads -grib path/to/fixture.grib [-addr :8090] [-prefix /api]
+//	        [-datasets cams-europe-air-quality-forecasts]
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/kacper-wojtaszczyk/jackfruit/serving-go/internal/mockads"
+)
+
+func main() {
+	flags := flag.NewFlagSet("mockads", flag.ContinueOnError)
+	addr := flags.String("addr", ":8090", "listen address")
+	prefix := flags.String("prefix", "/api", "path of the client's ADS_BASE_URL")
+	gribPath := flags.String("grib", "", "fixture GRIB served for every request")
+	datasets := flags.String("datasets", "cams-europe-air-quality-forecasts", "comma-separated datasets to serve")
+	if err := flags.Parse(os.Args[1:]); err != nil {
+		os.Exit(2)
+	}
+	if *gribPath == "" {
+		fmt.Fprintln(os.Stderr, "mockads: -grib is required")
+		os.Exit(2)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	if err := run(ctx, *addr, *prefix, *gribPath, strings.Split(*datasets, ",")); err != nil {
+		fmt.Fprintln(os.Stderr, "mockads:", err)
+		os.Exit(1)
+	}
+}
+
+func run(ctx context.Context, addr, prefix, gribPath string, datasets []string) error {
+	grib, err := os.ReadFile(gribPath)
+	if err != nil {
+		return err
+	}
+	logger := slog.New(slog.NewJSONHandler(os.Stdout, nil))
+	mux := http.NewServeMux()
+	mockads.New(prefix, grib, datasets, logger).RegisterRoutes(mux)
+	server := &http.Server{Addr: addr, Handler: mux, ReadHeaderTimeout: 5 * time.Second}
+
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_ = server.Shutdown(shutdownCtx)
+	}()
+	logger.Info("serving fixture", "addr", addr, "grib", gribPath, "bytes", len(grib), "datasets", datasets)
+	if err := server.ListenAndServe(); !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
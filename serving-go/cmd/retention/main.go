@@ -0,0 +1,32 @@
+// Command retention ages out old grid data and compacts recent partitions according to
+// the RETENTION_* configuration. plan prints the actions without executing them.
+//
+// Usage:
+//
+//	retention [plan|run]
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/kacper-wojtaszczyk/jackfruit/serving-go/internal/ops"
+)
+
+func main() {
+	command := "plan"
+	if len(os.Args) > 1 {
+		command = os.Args[1]
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	if err := ops.Retention(ctx, command, os.Stdout, os.Stderr); err != nil {
+		fmt.Fprintln(os.Stderr, "retention:", err)
+		os.Exit(1)
+	}
+}
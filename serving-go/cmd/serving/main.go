@@ -15,12 +15,22 @@ import (
 
 	"github.com/ClickHouse/clickhouse-go/v2"
 	_ "github.com/lib/pq"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 
 	"github.com/kacper-wojtaszczyk/jackfruit/serving-go/internal/api"
+	"github.com/kacper-wojtaszczyk/jackfruit/serving-go/internal/audit"
 	"github.com/kacper-wojtaszczyk/jackfruit/serving-go/internal/config"
 	"github.com/kacper-wojtaszczyk/jackfruit/serving-go/internal/domain"
+	"github.com/kacper-wojtaszczyk/jackfruit/serving-go/internal/featureflag"
 	"github.com/kacper-wojtaszczyk/jackfruit/serving-go/internal/grid"
+	"github.com/kacper-wojtaszczyk/jackfruit/serving-go/internal/gridcache"
+	"github.com/kacper-wojtaszczyk/jackfruit/serving-go/internal/gridhedge"
+	"github.com/kacper-wojtaszczyk/jackfruit/serving-go/internal/gridtelemetry"
 	"github.com/kacper-wojtaszczyk/jackfruit/serving-go/internal/lineage"
+	"github.com/kacper-wojtaszczyk/jackfruit/serving-go/internal/logging"
+	"github.com/kacper-wojtaszczyk/jackfruit/serving-go/internal/migrate"
 )
 
 type app struct {
@@ -35,21 +45,24 @@ func newApp() (*app, error) {
 		Level: slog.LevelInfo,
 	}))
 	slog.SetDefault(logger)
-	cfg := config.Load()
-
-	chConn, err := clickhouse.Open(&clickhouse.Options{
-		Addr: []string{fmt.Sprintf("%s:%s", cfg.ClickHouseHost, cfg.ClickHousePort)},
-		Auth: clickhouse.Auth{
-			Database: cfg.ClickHouseDatabase,
-			Username: cfg.ClickHouseUser,
-			Password: cfg.ClickHousePassword,
-		},
-		Logger: logger.With("component", "clickhouse"),
-		Settings: clickhouse.Settings{
-			"max_execution_time": 15,
-		},
-	})
+	cfg, err := config.Load()
+	if err != nil {
+		return nil, fmt.Errorf("load config: %w", err)
+	}
+	logLevels, err := logging.New(slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelDebug}),
+		cfg.LogLevel, cfg.LogLevels)
+	if err != nil {
+		return nil, fmt.Errorf("LOG_LEVELS: %w", err)
+	}
+	logger = logLevels.Logger(logging.ComponentServer)
+	slog.SetDefault(logger)
+	chLogger := logLevels.Logger(logging.ComponentClickHouse)
 
+	chOptions, err := grid.Options(cfg, chLogger)
+	if err != nil {
+		return nil, fmt.Errorf("clickhouse options: %w", err)
+	}
+	chConn, err := clickhouse.Open(chOptions)
 	if err != nil {
 		return nil, fmt.Errorf("open clickhouse: %w", err)
 	}
@@ -59,7 +72,50 @@ func newApp() (*app, error) {
 	if err := chConn.Ping(chPingCtx); err != nil {
 		return nil, fmt.Errorf("ping clickhouse: %w", err)
 	}
-	chFinder := grid.NewFinder(chConn)
+	if cfg.ClickHouseMigrateOnStart {
+		migrator, err := migrate.NewMigrator(chConn)
+		if err != nil {
+			return nil, fmt.Errorf("load clickhouse migrations: %w", err)
+		}
+		migrateCtx, migrateCancel := context.WithTimeout(context.Background(), time.Minute)
+		defer migrateCancel()
+		applied, err := migrator.Up(migrateCtx)
+		if err != nil {
+			return nil, fmt.Errorf("migrate clickhouse: %w", err)
+		}
+		logger.Info("clickhouse migrations applied", "count", len(applied))
+	}
+
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(collectors.NewGoCollector(), collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}))
+
+	finderOptions := []grid.FinderOption{
+		grid.WithRetryPolicy(grid.RetryPolicy{
+			MaxAttempts:    cfg.ClickHouseRetry.MaxAttempts,
+			InitialBackoff: cfg.ClickHouseRetry.InitialBackoff,
+			MaxBackoff:     cfg.ClickHouseRetry.MaxBackoff,
+		}),
+		grid.WithMetrics(grid.NewMetrics(registry)),
+		grid.WithQueryLog(chLogger),
+	}
+	if cfg.ClickHouseSlowQueryThreshold > 0 {
+		finderOptions = append(finderOptions, grid.WithSlowQueryLog(chLogger, cfg.ClickHouseSlowQueryThreshold))
+	}
+	if cfg.ClickHouseLatestFastPath {
+		finderOptions = append(finderOptions, grid.WithLatestFastPath())
+	}
+	if cfg.ClickHouseGeohashPrefilter {
+		finderOptions = append(finderOptions, grid.WithGeohashPrefilter())
+	}
+	if !cfg.Demo.Enabled && cfg.AuditLogFile == "" {
+		finderOptions = append(finderOptions, grid.WithAuditLogSchema())
+	}
+	chFinder := grid.NewFinder(chConn, finderOptions...)
+	schemaCtx, schemaCancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer schemaCancel()
+	if err := chFinder.CheckSchema(schemaCtx); err != nil {
+		return nil, fmt.Errorf("%w (apply migrations with `migrate up` or CLICKHOUSE_MIGRATE_ON_START=true)", err)
+	}
 
 	dsn := fmt.Sprintf(
 		"host=%s port=%s user=%s password=%s dbname=%s",
@@ -76,20 +132,124 @@ func newApp() (*app, error) {
 	}
 
 	lineageFinder := lineage.NewFinder(pgDB)
+	if err := lineageFinder.CheckSchema(schemaCtx); err != nil {
+		return nil, fmt.Errorf("%w (apply pipeline-python/migrations/postgres)", err)
+	}
 
-	service := domain.NewService(chFinder, lineageFinder)
+	telemetry := gridtelemetry.WithMetrics(gridtelemetry.NewMetrics(registry))
+	var gridRetriever domain.GridRetriever = gridtelemetry.New("clickhouse", chFinder, telemetry)
+	if cfg.HedgeDelay > 0 {
+		gridRetriever = gridhedge.New(gridRetriever, cfg.HedgeDelay)
+	}
+	if cfg.GridCache.DefaultTTL > 0 || len(cfg.GridCache.TTLs) > 0 {
+		policy := gridcache.Policy{
+			DefaultTTL: cfg.GridCache.DefaultTTL,
+			TTLs:       cfg.GridCache.TTLs,
+			MaxEntries: cfg.GridCache.MaxEntries,
+		}
+		gridRetriever = gridcache.New(gridRetriever, policy,
+			gridcache.WithMetrics(gridcache.NewMetrics(registry)),
+			gridcache.WithLogger(logLevels.Logger(logging.ComponentCache)),
+		)
+		gridRetriever = gridtelemetry.New("cache", gridRetriever, telemetry)
+	}
+
+	flags, err := loadFeatureFlags(cfg)
+	if err != nil {
+		return nil, err
+	}
+	serviceOptions := []domain.ServiceOption{
+		domain.WithFeatureFlags(flags),
+		domain.WithLogger(logLevels.Logger(logging.ComponentDomain)),
+		domain.WithMaxConcurrency(cfg.MaxConcurrentLookups),
+		domain.WithInterpolation(cfg.InterpolationMaxGaps),
+		domain.WithMaxAge(cfg.MaxDataAges),
+		domain.WithContracts(domain.DefaultContracts),
+		domain.WithSourcePrecedence(domain.SourcePrecedence{
+			Datasets: cfg.SourcePrecedence,
+			Lookback: cfg.SourcePrecedenceLookback,
+		}),
+	}
+	closers := []io.Closer{pgDB, chConn}
+	handlerOptions := []api.HandlerOption{
+		api.WithReadinessCheck("clickhouse", chFinder),
+		api.WithStatus(domain.NewStatusReporter(chFinder, lineageFinder, cfg.MaxLoadLags, cfg.DatasetCadences)),
+	}
+	if cfg.Demo.Enabled {
+		// The sandbox serves anonymous point and series lookups only: no catalog, audit or admin routes.
+		logger.Info("demo mode enabled", "max_history", cfg.Demo.MaxHistory, "requests_per_minute", cfg.Demo.RequestsPerMinute)
+		serviceOptions = append(serviceOptions,
+			domain.WithExtent(domain.BoundingBox{
+				MinLat: cfg.Demo.MinLat,
+				MinLon: cfg.Demo.MinLon,
+				MaxLat: cfg.Demo.MaxLat,
+				MaxLon: cfg.Demo.MaxLon,
+			}),
+			domain.WithMaxHistory(cfg.Demo.MaxHistory),
+		)
+	} else {
+		// Admin changes go where cmd/retention writes: the audit_log table, or AUDIT_LOG_FILE,
+		// which GET /v1/audit can't read and so leaves unregistered.
+		var auditRecorder audit.Recorder = audit.NewStore(chConn)
+		if cfg.AuditLogFile != "" {
+			sink, err := audit.OpenFile(cfg.AuditLogFile)
+			if err != nil {
+				return nil, err
+			}
+			closers = append(closers, sink)
+			auditRecorder = sink
+		}
+		handlerOptions = append(handlerOptions,
+			api.WithCatalog(domain.NewCatalogService(lineageFinder, chFinder)),
+			api.WithAudit(auditRecorder),
+			api.WithAdmin(cfg.AdminToken, flags),
+			api.WithLogLevels(logLevels),
+		)
+		if len(cfg.DatasetCadences) > 0 {
+			handlerOptions = append(handlerOptions, api.WithReconciliation(domain.NewReconciler(lineageFinder, cfg.DatasetCadences)))
+		}
+	}
+	service := domain.NewService(gridRetriever, lineageFinder, serviceOptions...)
+	handlerOptions = append(handlerOptions, api.WithSeries(service))
 
 	mux := http.NewServeMux()
-	api.NewHandler(service, logger.With("component", "api")).RegisterRoutes(mux)
+	api.NewHandler(service, logLevels.Logger(logging.ComponentAPI), handlerOptions...).RegisterRoutes(mux)
+	mux.Handle("GET /metrics", promhttp.HandlerFor(registry, promhttp.HandlerOpts{}))
+	var handler http.Handler = mux
+	if cfg.Demo.Enabled {
+		handler = api.WithRateLimit(handler, cfg.Demo.RequestsPerMinute, cfg.Demo.Burst)
+	}
 
 	server := &http.Server{
 		Addr:         ":" + cfg.Port,
-		Handler:      mux,
+		Handler:      api.WithRequestID(handler),
 		ReadTimeout:  5 * time.Second,
 		WriteTimeout: 20 * time.Second,
 		IdleTimeout:  60 * time.Second,
 	}
-	return &app{cfg: cfg, logger: logger, server: server, closers: []io.Closer{pgDB, chConn}}, nil
+	return &app{cfg: cfg, logger: logger, server: server, closers: closers}, nil
+}
+
+// loadFeatureFlags registers the flags the service consults, all on by default, and
+// applies FEATURE_FLAGS_FILE, then FEATURE_FLAGS.
+func loadFeatureFlags(cfg *config.Config) (*featureflag.Set, error) {
+	flags := featureflag.New(map[string]bool{
+		domain.FlagInterpolation:    true,
+		domain.FlagSourcePrecedence: true,
+	})
+	if cfg.FeatureFlagsFile != "" {
+		values, err := featureflag.LoadFile(cfg.FeatureFlagsFile)
+		if err != nil {
+			return nil, err
+		}
+		if err := flags.Configure(values); err != nil {
+			return nil, fmt.Errorf("FEATURE_FLAGS_FILE: %w", err)
+		}
+	}
+	if err := flags.Configure(cfg.FeatureFlags); err != nil {
+		return nil, fmt.Errorf("FEATURE_FLAGS: %w", err)
+	}
+	return flags, nil
 }
 
 func (a *app) run() {
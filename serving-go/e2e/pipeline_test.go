@@ -0,0 +1,220 @@
+package e2e_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kacper-wojtaszczyk/jackfruit/serving-go/internal/api"
+)
+
+// The fixture mockads serves: a CAMS Europe forecast run of 2026-02-21 00Z with pm2p5 and
+// pm10 at lead times 0, 4, 8 and 12h on a 700x420 grid of 0.1°.
+const (
+	pipelineJob       = "cams_daily_job"
+	pipelinePartition = "2026-02-21"
+	pipelineDataset   = "cams-europe-air-quality-forecast"
+	fixtureCells      = 700 * 420
+	fixtureMessages   = 8
+)
+
+var (
+	// fixtureLat and fixtureLon are a cell centre of the fixture grid; fixturePM2p5 is its
+	// pm2p5 at 00Z, decoded from the GRIB and converted to µg/m³ as the loader does.
+	fixtureLat, fixtureLon = float32(52.55), float32(13.45)
+	fixturePM2p5           = 16.6585
+	fixtureTimestamp       = time.Date(2026, 2, 21, 0, 0, 0, 0, time.UTC)
+)
+
+// dagsterClient launches and polls runs through the Dagster GraphQL API.
+type dagsterClient struct {
+	url string
+}
+
+func newDagsterClient(t *testing.T) *dagsterClient {
+	t.Helper()
+	baseURL := os.Getenv("JACKFRUIT_E2E_DAGSTER_URL")
+	if testing.Short() || baseURL == "" {
+		t.Skip("skipping pipeline e2e test, set JACKFRUIT_E2E_DAGSTER_URL to Dagster of the docker-compose.e2e.yml stack")
+	}
+	return &dagsterClient{url: strings.TrimSuffix(baseURL, "/") + "/graphql"}
+}
+
+func (c *dagsterClient) query(t *testing.T, query string, variables map[string]any, v any) {
+	t.Helper()
+	body, err := json.Marshal(map[string]any{"query": query, "variables": variables})
+	if err != nil {
+		t.Fatal(err)
+	}
+	req, err := http.NewRequestWithContext(t.Context(), "POST", c.url, bytes.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("dagster graphql: %v", err)
+	}
+	defer resp.Body.Close()
+
+	var result struct {
+		Data   json.RawMessage `json:"data"`
+		Errors []struct {
+			Message string `json:"message"`
+		} `json:"errors"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		t.Fatalf("decode dagster response (status %d): %v", resp.StatusCode, err)
+	}
+	if len(result.Errors) > 0 {
+		t.Fatalf("dagster graphql errors: %+v", result.Errors)
+	}
+	if err := json.Unmarshal(result.Data, v); err != nil {
+		t.Fatalf("decode dagster data: %v", err)
+	}
+}
+
+// launch starts job for partition in whichever code location defines it and returns the run id.
+func (c *dagsterClient) launch(t *testing.T, job, partition string) string {
+	t.Helper()
+	var repositories struct {
+		RepositoriesOrError struct {
+			Nodes []struct {
+				Name     string `json:"name"`
+				Location struct {
+					Name string `json:"name"`
+				} `json:"location"`
+				Jobs []struct {
+					Name string `json:"name"`
+				} `json:"jobs"`
+			} `json:"nodes"`
+		} `json:"repositoriesOrError"`
+	}
+	c.query(t, `{
+		repositoriesOrError {
+			... on RepositoryConnection { nodes { name location { name } jobs { name } } }
+		}
+	}`, nil, &repositories)
+
+	var selector map[string]string
+	for _, repository := range repositories.RepositoriesOrError.Nodes {
+		for _, j := range repository.Jobs {
+			if j.Name == job {
+				selector = map[string]string{
+					"repositoryLocationName": repository.Location.Name,
+					"repositoryName":         repository.Name,
+					"jobName":                job,
+				}
+			}
+		}
+	}
+	if selector == nil {
+		t.Fatalf("no dagster code location defines %s", job)
+	}
+
+	var launched struct {
+		LaunchRun struct {
+			Typename string `json:"__typename"`
+			Message  string `json:"message"`
+			Run      struct {
+				RunID string `json:"runId"`
+			} `json:"run"`
+		} `json:"launchRun"`
+	}
+	c.query(t, `mutation($params: ExecutionParams!) {
+		launchRun(executionParams: $params) {
+			__typename
+			... on LaunchRunSuccess { run { runId } }
+			... on Error { message }
+		}
+	}`, map[string]any{"params": map[string]any{
+		"selector": selector,
+		"executionMetadata": map[string]any{
+			"tags": []map[string]string{{"key": "dagster/partition", "value": partition}},
+		},
+	}}, &launched)
+	if launched.LaunchRun.Typename != "LaunchRunSuccess" {
+		t.Fatalf("launch %s: %s %s", job, launched.LaunchRun.Typename, launched.LaunchRun.Message)
+	}
+	return launched.LaunchRun.Run.RunID
+}
+
+// wait polls the run until it finishes and returns its final status.
+func (c *dagsterClient) wait(t *testing.T, runID string, timeout time.Duration) string {
+	t.Helper()
+	deadline := time.Now().Add(timeout)
+	for {
+		var run struct {
+			RunOrError struct {
+				Status string `json:"status"`
+			} `json:"runOrError"`
+		}
+		c.query(t, `query($runId: ID!) { runOrError(runId: $runId) { ... on Run { status } } }`,
+			map[string]any{"runId": runID}, &run)
+		switch status := run.RunOrError.Status; status {
+		case "SUCCESS", "FAILURE", "CANCELED":
+			return status
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("dagster run %s not finished after %s (status %q)", runID, timeout, run.RunOrError.Status)
+		}
+		time.Sleep(5 * time.Second)
+	}
+}
+
+// TestPipeline_CAMSFixtureRoundTrip runs the CAMS job against mockads and reads the
+// fixture back through the API: ADS download, the MinIO upload, the loader's read of it,
+// the ClickHouse and catalog writes and the serving lookups all have to agree.
+func TestPipeline_CAMSFixtureRoundTrip(t *testing.T) {
+	dagster := newDagsterClient(t)
+	client := newClient(t)
+	started := time.Now().UTC()
+
+	runID := dagster.launch(t, pipelineJob, pipelinePartition)
+	if status := dagster.wait(t, runID, 10*time.Minute); status != "SUCCESS" {
+		t.Fatalf("dagster run %s finished with %s, see its logs in Dagster", runID, status)
+	}
+
+	resp, err := client.Environmental(t.Context(), api.EnvironmentalRequest{
+		Lat:       fixtureLat,
+		Lon:       fixtureLon,
+		Timestamp: fixtureTimestamp,
+		Variables: []string{"pm2p5"},
+	})
+	if err != nil {
+		t.Fatalf("environmental lookup: %v", err)
+	}
+	if len(resp.Variables) != 1 {
+		t.Fatalf("expected one variable, got %+v", resp.Variables)
+	}
+	got := resp.Variables[0]
+	if math.Abs(got.Value-fixturePM2p5) > 1e-3 || got.Unit != "µg/m³" || !got.RefTimestamp.Equal(fixtureTimestamp) {
+		t.Errorf("expected pm2p5 %v µg/m³ at %s, got %+v", fixturePM2p5, fixtureTimestamp, got)
+	}
+	if math.Abs(float64(got.ActualLat-fixtureLat)) > 1e-3 || math.Abs(float64(got.ActualLon-fixtureLon)) > 1e-3 {
+		t.Errorf("expected the cell at (%v, %v), got (%v, %v)", fixtureLat, fixtureLon, got.ActualLat, got.ActualLon)
+	}
+	if got.Lineage.Source != "ads" || got.Lineage.Dataset != pipelineDataset {
+		t.Errorf("expected ads/%s lineage, got %+v", pipelineDataset, got.Lineage)
+	}
+
+	run, err := client.GetRun(t.Context(), got.Lineage.RawFileID)
+	if err != nil {
+		t.Fatalf("run lookup: %v", err)
+	}
+	raw := run.RawFile
+	wantKey := fmt.Sprintf("ads/%s/%s/%s.grib", pipelineDataset, pipelinePartition, raw.ID)
+	if raw.S3Key != wantKey || raw.Date != pipelinePartition || raw.CreatedAt.Before(started.Add(-time.Minute)) {
+		t.Errorf("expected a raw file at %s ingested by this run, got %+v", wantKey, raw)
+	}
+	if len(run.Entries) != fixtureMessages || run.Rows != fixtureMessages*fixtureCells {
+		t.Errorf("expected %d entries with %d rows, got %d entries with %d rows",
+			fixtureMessages, fixtureMessages*fixtureCells, len(run.Entries), run.Rows)
+	}
+}
@@ -0,0 +1,89 @@
+// Package e2e_test runs tests against a deployed stack. The smoke test is serving-only: it
+// seeds a fixture straight into ClickHouse and Postgres, standing in for the pipeline-python
+// loader, and reads it back through the serving API over HTTP. The pipeline test runs the
+// Dagster CAMS job of the docker-compose.e2e.yml stack against mockads, so ingestion, MinIO
+// and the loader are exercised too.
+package e2e_test
+
+import (
+	"math"
+	"net/http"
+	"os"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/kacper-wojtaszczyk/jackfruit/serving-go/internal/api"
+	"github.com/kacper-wojtaszczyk/jackfruit/serving-go/internal/apiclient"
+	"github.com/kacper-wojtaszczyk/jackfruit/serving-go/internal/testutil"
+)
+
+// fixtureVariable is not a real variable, so the fixture can't collide with loaded data.
+const fixtureVariable = "e2e_smoke"
+
+func newClient(t *testing.T) *apiclient.Client {
+	t.Helper()
+
+	baseURL := os.Getenv("JACKFRUIT_E2E_URL")
+	if testing.Short() || baseURL == "" {
+		t.Skip("skipping e2e test, set JACKFRUIT_E2E_URL to a running serving instance")
+	}
+
+	deadline := time.Now().Add(30 * time.Second)
+	for {
+		resp, err := http.Get(baseURL + "/ready")
+		if err == nil {
+			_ = resp.Body.Close()
+			if resp.StatusCode == http.StatusNoContent {
+				break
+			}
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("serving at %s not ready after 30s (last error: %v)", baseURL, err)
+		}
+		time.Sleep(time.Second)
+	}
+
+	return apiclient.New(baseURL)
+}
+
+func TestServingSmoke_FixtureRoundTrip(t *testing.T) {
+	client := newClient(t)
+	chConn := testutil.NewRawConn(t)
+	pgDB := testutil.NewPostgresDB(t)
+
+	ts := time.Now().UTC().Truncate(time.Hour)
+	lat, lon := float32(47.25), float32(8.5)
+	value := float32(42.125)
+	catalogID := testutil.InsertGridRow(t, chConn, fixtureVariable, value, "µg/m³", ts, lat, lon)
+	rawFileID := testutil.SeedLineage(t, pgDB, catalogID, "ads", "cams-europe-air-quality-forecast", fixtureVariable, "µg/m³")
+
+	resp, err := client.Environmental(t.Context(), api.EnvironmentalRequest{
+		Lat:       lat + 0.01,
+		Lon:       lon - 0.01,
+		Timestamp: ts.Add(10 * time.Minute),
+		Variables: []string{fixtureVariable},
+	})
+	if err != nil {
+		t.Fatalf("environmental lookup: %v", err)
+	}
+	if len(resp.Variables) != 1 {
+		t.Fatalf("expected one variable, got %+v", resp.Variables)
+	}
+	got := resp.Variables[0]
+	if math.Abs(got.Value-float64(value)) > 1e-6 || got.ActualLat != lat || got.ActualLon != lon || !got.RefTimestamp.Equal(ts) {
+		t.Errorf("expected %v at (%v, %v) %s, got %+v", value, lat, lon, ts, got)
+	}
+	if got.Lineage.RawFileID != rawFileID {
+		t.Errorf("expected raw file %s, got %s", rawFileID, got.Lineage.RawFileID)
+	}
+
+	run, err := client.GetRun(t.Context(), rawFileID)
+	if err != nil {
+		t.Fatalf("run lookup: %v", err)
+	}
+	i := slices.IndexFunc(run.Entries, func(e api.CatalogEntryResponse) bool { return e.ID == catalogID })
+	if i < 0 || run.Entries[i].Rows != 1 {
+		t.Errorf("expected run to list catalog entry %s with one row, got %+v", catalogID, run.Entries)
+	}
+}
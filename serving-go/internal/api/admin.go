@@ -0,0 +1,206 @@
+package api
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/kacper-wojtaszczyk/jackfruit/serving-go/internal/featureflag"
+	"github.com/kacper-wojtaszczyk/jackfruit/serving-go/internal/logging"
+)
+
+// adminActor is the audit actor of admin requests; the shared token doesn't identify a person.
+// Requests without a valid token are recorded as anonymousActor.
+const (
+	adminActor     = "admin"
+	anonymousActor = "anonymous"
+)
+
+type flagSet interface {
+	List() []featureflag.State
+	Override(name string, percent int) (featureflag.State, error)
+	Clear(name string) (featureflag.State, error)
+}
+
+type logLevelSet interface {
+	List() []logging.State
+	Set(component string, level slog.Level) (logging.State, error)
+	Reset(component string) (logging.State, error)
+}
+
+// WithAdmin serves the /v1/admin endpoints to callers presenting token as a bearer token,
+// managing flags. An empty token leaves the endpoints unregistered.
+func WithAdmin(token string, flags flagSet) HandlerOption {
+	return func(h *Handler) {
+		h.adminToken = token
+		h.flags = flags
+	}
+}
+
+// WithLogLevels adds per-component log level endpoints to /v1/admin; see WithAdmin.
+func WithLogLevels(levels logLevelSet) HandlerOption {
+	return func(h *Handler) {
+		h.logLevels = levels
+	}
+}
+
+func (h *Handler) registerAdminRoutes(mux *http.ServeMux) {
+	if h.auditQuerier != nil {
+		mux.HandleFunc("GET /v1/audit", h.requireAdmin(h.handleListAudit))
+	}
+	if h.flags != nil {
+		mux.HandleFunc("GET /v1/admin/flags", h.requireAdmin(h.handleListFlags))
+		mux.HandleFunc("PUT /v1/admin/flags/{name}", h.requireAdmin(h.handleOverrideFlag))
+		mux.HandleFunc("DELETE /v1/admin/flags/{name}", h.requireAdmin(h.handleClearFlag))
+	}
+	if h.logLevels != nil {
+		mux.HandleFunc("GET /v1/admin/log-levels", h.requireAdmin(h.handleListLogLevels))
+		mux.HandleFunc("PUT /v1/admin/log-levels/{component}", h.requireAdmin(h.handleSetLogLevel))
+		mux.HandleFunc("DELETE /v1/admin/log-levels/{component}", h.requireAdmin(h.handleResetLogLevel))
+	}
+}
+
+func (h *Handler) requireAdmin(next http.HandlerFunc) http.HandlerFunc {
+	want := []byte("Bearer " + h.adminToken)
+	return func(w http.ResponseWriter, r *http.Request) {
+		if subtle.ConstantTimeCompare([]byte(r.Header.Get("Authorization")), want) != 1 {
+			h.recordAuditAs(r, anonymousActor, "admin.unauthorized", r.URL.Path, map[string]string{"method": r.Method})
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			writeError(w, http.StatusUnauthorized, "unauthorized")
+			return
+		}
+		next(w, r)
+	}
+}
+
+func (h *Handler) handleListFlags(w http.ResponseWriter, _ *http.Request) {
+	states := h.flags.List()
+	response := FlagListResponse{Flags: make([]FlagResponse, len(states))}
+	for i, state := range states {
+		response.Flags[i] = newFlagResponse(state)
+	}
+	writeJSON(w, http.StatusOK, response)
+}
+
+func (h *Handler) handleOverrideFlag(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		Enabled *bool `json:"enabled"`
+		Percent *int  `json:"percent"`
+	}
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 1<<10)).Decode(&body); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("could not parse body: %v", err))
+		return
+	}
+	var percent int
+	switch {
+	case body.Enabled != nil && body.Percent != nil, body.Enabled == nil && body.Percent == nil:
+		writeError(w, http.StatusBadRequest, `body must set exactly one of "enabled" and "percent"`)
+		return
+	case body.Percent != nil:
+		percent = *body.Percent
+	case *body.Enabled:
+		percent = featureflag.On
+	default:
+		percent = featureflag.Off
+	}
+
+	name := r.PathValue("name")
+	state, err := h.flags.Override(name, percent)
+	if errors.Is(err, featureflag.ErrUnknownFlag) {
+		writeError(w, http.StatusNotFound, err.Error())
+		return
+	}
+	if errors.Is(err, featureflag.ErrInvalidPercent) {
+		writeError(w, http.StatusUnprocessableEntity, err.Error())
+		return
+	}
+	if err != nil {
+		h.writeCatalogError(w, r, r.Context(), "flags.Override", err)
+		return
+	}
+	h.recordAudit(r, "flags.override", name, map[string]string{"percent": strconv.Itoa(state.Percent)})
+	writeJSON(w, http.StatusOK, newFlagResponse(state))
+}
+
+func (h *Handler) handleClearFlag(w http.ResponseWriter, r *http.Request) {
+	name := r.PathValue("name")
+	state, err := h.flags.Clear(name)
+	if errors.Is(err, featureflag.ErrUnknownFlag) {
+		writeError(w, http.StatusNotFound, err.Error())
+		return
+	}
+	if err != nil {
+		h.writeCatalogError(w, r, r.Context(), "flags.Clear", err)
+		return
+	}
+	h.recordAudit(r, "flags.clear", name, map[string]string{"percent": strconv.Itoa(state.Percent)})
+	writeJSON(w, http.StatusOK, newFlagResponse(state))
+}
+
+func newFlagResponse(state featureflag.State) FlagResponse {
+	return FlagResponse{Name: state.Name, Percent: state.Percent, Default: state.Default, Overridden: state.Overridden}
+}
+
+func (h *Handler) handleListLogLevels(w http.ResponseWriter, _ *http.Request) {
+	states := h.logLevels.List()
+	response := LogLevelListResponse{Components: make([]LogLevelResponse, len(states))}
+	for i, state := range states {
+		response.Components[i] = newLogLevelResponse(state)
+	}
+	writeJSON(w, http.StatusOK, response)
+}
+
+func (h *Handler) handleSetLogLevel(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		Level *string `json:"level"`
+	}
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 1<<10)).Decode(&body); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("could not parse body: %v", err))
+		return
+	}
+	if body.Level == nil {
+		writeError(w, http.StatusBadRequest, `body must set "level"`)
+		return
+	}
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(*body.Level)); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("could not parse level: %v", err))
+		return
+	}
+
+	component := r.PathValue("component")
+	state, err := h.logLevels.Set(component, level)
+	if errors.Is(err, logging.ErrUnknownComponent) {
+		writeError(w, http.StatusNotFound, err.Error())
+		return
+	}
+	if err != nil {
+		h.writeCatalogError(w, r, r.Context(), "logLevels.Set", err)
+		return
+	}
+	h.recordAudit(r, "log_levels.set", component, map[string]string{"level": state.Level.String()})
+	writeJSON(w, http.StatusOK, newLogLevelResponse(state))
+}
+
+func (h *Handler) handleResetLogLevel(w http.ResponseWriter, r *http.Request) {
+	component := r.PathValue("component")
+	state, err := h.logLevels.Reset(component)
+	if errors.Is(err, logging.ErrUnknownComponent) {
+		writeError(w, http.StatusNotFound, err.Error())
+		return
+	}
+	if err != nil {
+		h.writeCatalogError(w, r, r.Context(), "logLevels.Reset", err)
+		return
+	}
+	h.recordAudit(r, "log_levels.reset", component, map[string]string{"level": state.Level.String()})
+	writeJSON(w, http.StatusOK, newLogLevelResponse(state))
+}
+
+func newLogLevelResponse(state logging.State) LogLevelResponse {
+	return LogLevelResponse{Component: state.Component, Level: state.Level.String(), Default: state.Default.String()}
+}
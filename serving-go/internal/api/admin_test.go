@@ -0,0 +1,195 @@
+package api_test
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/kacper-wojtaszczyk/jackfruit/serving-go/internal/api"
+	"github.com/kacper-wojtaszczyk/jackfruit/serving-go/internal/featureflag"
+	"github.com/kacper-wojtaszczyk/jackfruit/serving-go/internal/logging"
+)
+
+func newAdminMux(t *testing.T, auditLog *mockAuditLog) (*http.ServeMux, *featureflag.Set) {
+	t.Helper()
+	flags := featureflag.New(map[string]bool{"interpolation": true})
+	mux := http.NewServeMux()
+	api.NewHandler(&mockVariableProvider{}, slog.New(slog.DiscardHandler),
+		api.WithAdmin("secret", flags), api.WithAudit(auditLog)).RegisterRoutes(mux)
+	return mux, flags
+}
+
+func adminRequest(method, target, body string) *http.Request {
+	r := httptest.NewRequest(method, target, strings.NewReader(body))
+	r.Header.Set("Authorization", "Bearer secret")
+	return r
+}
+
+func TestAdminFlags(t *testing.T) {
+	auditLog := &mockAuditLog{}
+	mux, flags := newAdminMux(t, auditLog)
+
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, adminRequest("PUT", "/v1/admin/flags/interpolation", `{"enabled": false}`))
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var flag api.FlagResponse
+	if err := json.NewDecoder(w.Body).Decode(&flag); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if flag.Percent != 0 || flag.Default != 100 || !flag.Overridden || flags.Enabled("interpolation", "req-1") {
+		t.Errorf("expected interpolation overridden off, got %+v", flag)
+	}
+	if len(auditLog.recorded) != 1 || auditLog.recorded[0].Action != "flags.override" ||
+		auditLog.recorded[0].Target != "interpolation" || auditLog.recorded[0].Details["percent"] != "0" {
+		t.Errorf("expected one flags.override audit event, got %+v", auditLog.recorded)
+	}
+
+	w = httptest.NewRecorder()
+	mux.ServeHTTP(w, adminRequest("GET", "/v1/admin/flags", ""))
+	var list api.FlagListResponse
+	if err := json.NewDecoder(w.Body).Decode(&list); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if len(list.Flags) != 1 || list.Flags[0].Name != "interpolation" || list.Flags[0].Percent != 0 {
+		t.Errorf("unexpected flag list %+v", list)
+	}
+
+	w = httptest.NewRecorder()
+	mux.ServeHTTP(w, adminRequest("PUT", "/v1/admin/flags/interpolation", `{"percent": 25}`))
+	if err := json.NewDecoder(w.Body).Decode(&flag); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if w.Code != http.StatusOK || flag.Percent != 25 || auditLog.recorded[1].Details["percent"] != "25" {
+		t.Errorf("expected interpolation rolled out to 25%%, got %d: %+v", w.Code, flag)
+	}
+
+	w = httptest.NewRecorder()
+	mux.ServeHTTP(w, adminRequest("DELETE", "/v1/admin/flags/interpolation", ""))
+	if w.Code != http.StatusOK || !flags.Enabled("interpolation", "") || len(auditLog.recorded) != 3 ||
+		auditLog.recorded[2].Details["percent"] != "100" {
+		t.Errorf("expected clearing to restore the default and be audited, got %d: %s", w.Code, w.Body.String())
+	}
+}
+
+func TestAdminFlags_AuditsAfterDisconnect(t *testing.T) {
+	auditLog := &mockAuditLog{}
+	mux, _ := newAdminMux(t, auditLog)
+
+	ctx, cancel := context.WithCancel(t.Context())
+	cancel()
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, adminRequest("PUT", "/v1/admin/flags/interpolation", `{"enabled": false}`).WithContext(ctx))
+	if w.Code != http.StatusOK || len(auditLog.recorded) != 1 {
+		t.Errorf("expected the override to be audited after the client left, got %d and %+v", w.Code, auditLog.recorded)
+	}
+}
+
+func TestAdminRoutes_AuditUnauthorized(t *testing.T) {
+	auditLog := &mockAuditLog{}
+	mux, _ := newAdminMux(t, auditLog)
+
+	r := httptest.NewRequest("PUT", "/v1/admin/flags/interpolation", strings.NewReader(`{"enabled": false}`))
+	r.Header.Set("Authorization", "Bearer guess")
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, r)
+	if w.Code != http.StatusUnauthorized {
+		t.Fatalf("expected status 401, got %d", w.Code)
+	}
+	if len(auditLog.recorded) != 1 {
+		t.Fatalf("expected one audit event, got %+v", auditLog.recorded)
+	}
+	event := auditLog.recorded[0]
+	if event.Actor != "anonymous" || event.Action != "admin.unauthorized" || event.Target != "/v1/admin/flags/interpolation" ||
+		event.Details["method"] != "PUT" || event.Details["remote_addr"] == "" {
+		t.Errorf("unexpected audit event %+v", event)
+	}
+}
+
+func TestAdminFlags_Errors(t *testing.T) {
+	mux, _ := newAdminMux(t, &mockAuditLog{})
+	tests := []struct {
+		name    string
+		request *http.Request
+		want    int
+	}{
+		{name: "no token", request: httptest.NewRequest("GET", "/v1/admin/flags", nil), want: http.StatusUnauthorized},
+		{name: "unknown flag", request: adminRequest("PUT", "/v1/admin/flags/typo", `{"enabled": true}`), want: http.StatusNotFound},
+		{name: "missing value", request: adminRequest("PUT", "/v1/admin/flags/interpolation", `{}`), want: http.StatusBadRequest},
+		{name: "enabled and percent", request: adminRequest("PUT", "/v1/admin/flags/interpolation", `{"enabled": true, "percent": 50}`), want: http.StatusBadRequest},
+		{name: "percent out of range", request: adminRequest("PUT", "/v1/admin/flags/interpolation", `{"percent": 150}`), want: http.StatusUnprocessableEntity},
+		{name: "malformed body", request: adminRequest("PUT", "/v1/admin/flags/interpolation", `on`), want: http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			mux.ServeHTTP(w, tt.request)
+			if w.Code != tt.want {
+				t.Errorf("expected status %d, got %d: %s", tt.want, w.Code, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestAdminRoutes_RequireToken(t *testing.T) {
+	mux := http.NewServeMux()
+	api.NewHandler(&mockVariableProvider{}, slog.New(slog.DiscardHandler),
+		api.WithAdmin("", featureflag.New(nil))).RegisterRoutes(mux)
+
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, httptest.NewRequest("GET", "/v1/admin/flags", nil))
+	if w.Code != http.StatusNotFound {
+		t.Errorf("expected admin routes unregistered without a token, got %d", w.Code)
+	}
+}
+
+func TestAdminLogLevels(t *testing.T) {
+	levels, err := logging.New(slog.DiscardHandler, slog.LevelInfo, nil)
+	if err != nil {
+		t.Fatalf("logging.New returned error: %v", err)
+	}
+	auditLog := &mockAuditLog{}
+	mux := http.NewServeMux()
+	api.NewHandler(&mockVariableProvider{}, slog.New(slog.DiscardHandler),
+		api.WithAdmin("secret", featureflag.New(nil)), api.WithLogLevels(levels), api.WithAudit(auditLog)).RegisterRoutes(mux)
+
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, adminRequest("PUT", "/v1/admin/log-levels/clickhouse", `{"level": "debug"}`))
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var level api.LogLevelResponse
+	if err := json.NewDecoder(w.Body).Decode(&level); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if level != (api.LogLevelResponse{Component: "clickhouse", Level: "DEBUG", Default: "INFO"}) {
+		t.Errorf("unexpected response %+v", level)
+	}
+	if len(auditLog.recorded) != 1 || auditLog.recorded[0].Action != "log_levels.set" || auditLog.recorded[0].Details["level"] != "DEBUG" {
+		t.Errorf("expected one log_levels.set audit event, got %+v", auditLog.recorded)
+	}
+
+	w = httptest.NewRecorder()
+	mux.ServeHTTP(w, adminRequest("DELETE", "/v1/admin/log-levels/clickhouse", ""))
+	if w.Code != http.StatusOK || levels.List()[2].Level != slog.LevelInfo {
+		t.Errorf("expected reset to restore info, got %d: %s", w.Code, w.Body.String())
+	}
+
+	for body, want := range map[string]int{`{"level": "loud"}`: http.StatusBadRequest, `{}`: http.StatusBadRequest} {
+		w = httptest.NewRecorder()
+		mux.ServeHTTP(w, adminRequest("PUT", "/v1/admin/log-levels/api", body))
+		if w.Code != want {
+			t.Errorf("%s: expected status %d, got %d", body, want, w.Code)
+		}
+	}
+	w = httptest.NewRecorder()
+	mux.ServeHTTP(w, adminRequest("PUT", "/v1/admin/log-levels/postgres", `{"level": "debug"}`))
+	if w.Code != http.StatusNotFound {
+		t.Errorf("expected unknown component to be 404, got %d", w.Code)
+	}
+}
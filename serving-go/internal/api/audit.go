@@ -0,0 +1,96 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/kacper-wojtaszczyk/jackfruit/serving-go/internal/audit"
+	"github.com/kacper-wojtaszczyk/jackfruit/serving-go/internal/requestid"
+)
+
+type auditQuerier interface {
+	Query(ctx context.Context, filter audit.Filter) ([]audit.Event, error)
+}
+
+// WithAudit records admin operations to r. When r can also be queried, as audit.Store can,
+// admins can read it back from GET /v1/audit; a write-only sink such as audit.FileSink
+// leaves the endpoint unregistered rather than serving an empty list.
+func WithAudit(r audit.Recorder) HandlerOption {
+	return func(h *Handler) {
+		h.auditRecorder = r
+		h.auditQuerier, _ = r.(auditQuerier)
+	}
+}
+
+// auditTimeout bounds recording one admin operation.
+const auditTimeout = 5 * time.Second
+
+// recordAudit records an admin operation, if an audit log is configured. Failures are
+// logged, not returned: the operation already happened. For the same reason the record
+// isn't cancelled with the request when the client disconnects.
+func (h *Handler) recordAudit(r *http.Request, action, target string, details map[string]string) {
+	h.recordAuditAs(r, adminActor, action, target, details)
+}
+
+func (h *Handler) recordAuditAs(r *http.Request, actor, action, target string, details map[string]string) {
+	if h.auditRecorder == nil {
+		return
+	}
+	details["remote_addr"] = r.RemoteAddr
+	if id := requestid.FromContext(r.Context()); id != "" {
+		details["request_id"] = id
+	}
+	event := audit.Event{Time: time.Now().UTC(), Actor: actor, Action: action, Target: target, Details: details}
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(r.Context()), auditTimeout)
+	defer cancel()
+	if err := h.auditRecorder.Record(ctx, event); err != nil {
+		h.logger.Error("recording audit event failed", "error", err, "action", action, "request_id", requestid.FromContext(r.Context()))
+	}
+}
+
+func (h *Handler) handleListAudit(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	filter := audit.Filter{Actor: query.Get("actor"), Action: query.Get("action")}
+	var err error
+	if from := query.Get("from"); from != "" {
+		if filter.From, err = parseTime(from); err != nil {
+			writeError(w, http.StatusBadRequest, fmt.Sprintf("could not parse from: %v", err))
+			return
+		}
+	}
+	if to := query.Get("to"); to != "" {
+		if filter.To, err = parseTime(to); err != nil {
+			writeError(w, http.StatusBadRequest, fmt.Sprintf("could not parse to: %v", err))
+			return
+		}
+	}
+	if limit := query.Get("limit"); limit != "" {
+		if filter.Limit, err = strconv.Atoi(limit); err != nil {
+			writeError(w, http.StatusBadRequest, fmt.Sprintf("could not parse limit: %v", err))
+			return
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 18*time.Second)
+	defer cancel()
+	events, err := h.auditQuerier.Query(ctx, filter)
+	if err != nil {
+		h.writeCatalogError(w, r, ctx, "auditQuerier.Query", err)
+		return
+	}
+
+	response := AuditListResponse{Events: make([]AuditEventResponse, len(events))}
+	for i, event := range events {
+		response.Events[i] = AuditEventResponse{
+			Time:    event.Time,
+			Actor:   event.Actor,
+			Action:  event.Action,
+			Target:  event.Target,
+			Details: event.Details,
+		}
+	}
+	writeJSON(w, http.StatusOK, response)
+}
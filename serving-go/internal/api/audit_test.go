@@ -0,0 +1,119 @@
+package api_test
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/kacper-wojtaszczyk/jackfruit/serving-go/internal/api"
+	"github.com/kacper-wojtaszczyk/jackfruit/serving-go/internal/audit"
+	"github.com/kacper-wojtaszczyk/jackfruit/serving-go/internal/domain"
+	"github.com/kacper-wojtaszczyk/jackfruit/serving-go/internal/featureflag"
+)
+
+type mockAuditLog struct {
+	events   []audit.Event
+	err      error
+	filter   audit.Filter
+	recorded []audit.Event
+}
+
+func (m *mockAuditLog) Record(ctx context.Context, event audit.Event) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	m.recorded = append(m.recorded, event)
+	return nil
+}
+
+func (m *mockAuditLog) Query(_ context.Context, filter audit.Filter) ([]audit.Event, error) {
+	m.filter = filter
+	return m.events, m.err
+}
+
+func TestHandleListAudit(t *testing.T) {
+	auditLog := &mockAuditLog{events: []audit.Event{{Actor: "ops", Action: "retention.delete", Target: "grid_data"}}}
+	mux, _ := newAdminMux(t, auditLog)
+
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, adminRequest("GET", "/v1/audit?action=retention.delete&from=2025-03-01T00:00:00Z&limit=10", ""))
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	want := audit.Filter{Action: "retention.delete", From: time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC), Limit: 10}
+	if auditLog.filter.Action != want.Action || !auditLog.filter.From.Equal(want.From) || auditLog.filter.Limit != want.Limit {
+		t.Errorf("expected filter %+v, got %+v", want, auditLog.filter)
+	}
+	var response api.AuditListResponse
+	if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+		t.Fatal(err)
+	}
+	if len(response.Events) != 1 || response.Events[0].Actor != "ops" {
+		t.Errorf("unexpected response %+v", response)
+	}
+
+	w = httptest.NewRecorder()
+	mux.ServeHTTP(w, adminRequest("GET", "/v1/audit?to=yesterday", ""))
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected status 400 for unparsable to, got %d", w.Code)
+	}
+
+	auditLog.err = &domain.ErrInvalidRequest{Field: "limit", Message: "must be between 1 and 1000, 5000 given"}
+	w = httptest.NewRecorder()
+	mux.ServeHTTP(w, adminRequest("GET", "/v1/audit?limit=5000", ""))
+	if w.Code != http.StatusUnprocessableEntity {
+		t.Errorf("expected status 422 for out-of-range limit, got %d", w.Code)
+	}
+}
+
+type recordOnlyAuditLog struct {
+	recorded []audit.Event
+}
+
+func (r *recordOnlyAuditLog) Record(_ context.Context, event audit.Event) error {
+	r.recorded = append(r.recorded, event)
+	return nil
+}
+
+func TestHandleListAudit_Access(t *testing.T) {
+	tests := []struct {
+		name    string
+		options []api.HandlerOption
+		request *http.Request
+		want    int
+	}{
+		{
+			name:    "no token",
+			options: []api.HandlerOption{api.WithAdmin("secret", featureflag.New(nil)), api.WithAudit(&mockAuditLog{})},
+			request: httptest.NewRequest("GET", "/v1/audit", nil),
+			want:    http.StatusUnauthorized,
+		},
+		{
+			name:    "admin disabled",
+			options: []api.HandlerOption{api.WithAdmin("", featureflag.New(nil)), api.WithAudit(&mockAuditLog{})},
+			request: adminRequest("GET", "/v1/audit", ""),
+			want:    http.StatusNotFound,
+		},
+		{
+			name:    "write-only sink",
+			options: []api.HandlerOption{api.WithAdmin("secret", featureflag.New(nil)), api.WithAudit(&recordOnlyAuditLog{})},
+			request: adminRequest("GET", "/v1/audit", ""),
+			want:    http.StatusNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mux := http.NewServeMux()
+			api.NewHandler(&mockVariableProvider{}, slog.New(slog.DiscardHandler), tt.options...).RegisterRoutes(mux)
+			w := httptest.NewRecorder()
+			mux.ServeHTTP(w, tt.request)
+			if w.Code != tt.want {
+				t.Errorf("expected status %d, got %d", tt.want, w.Code)
+			}
+		})
+	}
+}
@@ -0,0 +1,249 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/kacper-wojtaszczyk/jackfruit/serving-go/internal/domain"
+	"github.com/kacper-wojtaszczyk/jackfruit/serving-go/internal/requestid"
+)
+
+type catalogProvider interface {
+	GetEntry(ctx context.Context, id uuid.UUID) (*domain.CatalogEntry, error)
+	ListEntries(ctx context.Context, filter domain.CatalogFilter) ([]domain.CatalogEntry, error)
+	GetCoverage(ctx context.Context, variables ...string) ([]domain.Coverage, error)
+	GetQuality(ctx context.Context, id uuid.UUID) (*domain.Quality, error)
+	GetRun(ctx context.Context, runID uuid.UUID) (*domain.Run, error)
+}
+
+// WithCatalog serves the catalog and coverage endpoints from p.
+func WithCatalog(p catalogProvider) HandlerOption {
+	return func(h *Handler) {
+		h.catalogProvider = p
+	}
+}
+
+func (h *Handler) registerCatalogRoutes(mux *http.ServeMux) {
+	mux.HandleFunc("GET /v1/catalog", h.handleListCatalog)
+	mux.HandleFunc("GET /v1/catalog/{id}", h.handleGetCatalogEntry)
+	mux.HandleFunc("GET /v1/catalog/{id}/quality", h.handleCatalogQuality)
+	mux.HandleFunc("GET /v1/coverage", h.handleCoverage)
+	mux.HandleFunc("GET /v1/runs/{id}", h.handleGetRun)
+}
+
+func (h *Handler) handleGetCatalogEntry(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("could not parse catalog id: %v", err))
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 18*time.Second)
+	defer cancel()
+	entry, err := h.catalogProvider.GetEntry(ctx, id)
+	if errors.Is(err, domain.ErrCatalogEntryNotFound) {
+		writeError(w, http.StatusNotFound, err.Error())
+		return
+	}
+	if err != nil {
+		h.writeCatalogError(w, r, ctx, "catalogProvider.GetEntry", err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, newCatalogEntryResponse(*entry))
+}
+
+func (h *Handler) handleCatalogQuality(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("could not parse catalog id: %v", err))
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 18*time.Second)
+	defer cancel()
+	quality, err := h.catalogProvider.GetQuality(ctx, id)
+	if errors.Is(err, domain.ErrCatalogEntryNotFound) {
+		writeError(w, http.StatusNotFound, err.Error())
+		return
+	}
+	if err != nil {
+		h.writeCatalogError(w, r, ctx, "catalogProvider.GetQuality", err)
+		return
+	}
+
+	units := quality.Units
+	if units == nil {
+		units = []string{}
+	}
+	writeJSON(w, http.StatusOK, QualityResponse{
+		CatalogID:    id,
+		Rows:         quality.Rows,
+		ExpectedRows: quality.ExpectedRows,
+		NaNFraction:  quality.NaNFraction(),
+		ZeroRows:     quality.ZeroRows,
+		Min:          quality.Min,
+		Max:          quality.Max,
+		Mean:         quality.Mean,
+		Units:        units,
+		LatStep:      quality.LatStep,
+		LonStep:      quality.LonStep,
+		Flags:        quality.Flags,
+	})
+}
+
+func (h *Handler) handleGetRun(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("could not parse run id: %v", err))
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 18*time.Second)
+	defer cancel()
+	run, err := h.catalogProvider.GetRun(ctx, id)
+	if errors.Is(err, domain.ErrRawFileNotFound) {
+		writeError(w, http.StatusNotFound, err.Error())
+		return
+	}
+	if err != nil {
+		h.writeCatalogError(w, r, ctx, "catalogProvider.GetRun", err)
+		return
+	}
+
+	response := RunResponse{
+		RawFile: newRawFileResponse(run.RawFile),
+		Entries: make([]CatalogEntryResponse, len(run.Entries)),
+	}
+	for i, entry := range run.Entries {
+		response.Entries[i] = newCatalogEntryResponse(entry)
+		response.Rows += entry.Rows
+	}
+	writeJSON(w, http.StatusOK, response)
+}
+
+func (h *Handler) handleListCatalog(w http.ResponseWriter, r *http.Request) {
+	filter, err := parseCatalogFilter(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 18*time.Second)
+	defer cancel()
+	entries, err := h.catalogProvider.ListEntries(ctx, *filter)
+	if err != nil {
+		h.writeCatalogError(w, r, ctx, "catalogProvider.ListEntries", err)
+		return
+	}
+
+	response := CatalogListResponse{Entries: make([]CatalogEntryResponse, len(entries))}
+	for i, entry := range entries {
+		response.Entries[i] = newCatalogEntryResponse(entry)
+	}
+	writeJSON(w, http.StatusOK, response)
+}
+
+func (h *Handler) handleCoverage(w http.ResponseWriter, r *http.Request) {
+	var variables []string
+	if list := r.URL.Query().Get("variables"); list != "" {
+		var err error
+		if variables, err = parseStringList(list); err != nil {
+			writeError(w, http.StatusBadRequest, fmt.Sprintf("could not parse variables: %v", err))
+			return
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 18*time.Second)
+	defer cancel()
+	coverage, err := h.catalogProvider.GetCoverage(ctx, variables...)
+	if err != nil {
+		h.writeCatalogError(w, r, ctx, "catalogProvider.GetCoverage", err)
+		return
+	}
+
+	response := CoverageResponse{Variables: make([]VariableCoverageResponse, len(coverage))}
+	for i, c := range coverage {
+		response.Variables[i] = VariableCoverageResponse{
+			Name:           c.Variable,
+			FirstTimestamp: c.FirstTimestamp,
+			LastTimestamp:  c.LastTimestamp,
+			Extent: BoundingBoxResponse{
+				MinLat: c.Extent.MinLat,
+				MinLon: c.Extent.MinLon,
+				MaxLat: c.Extent.MaxLat,
+				MaxLon: c.Extent.MaxLon,
+			},
+			Rows: c.Rows,
+		}
+	}
+	writeJSON(w, http.StatusOK, response)
+}
+
+func (h *Handler) writeCatalogError(w http.ResponseWriter, r *http.Request, ctx context.Context, operation string, err error) {
+	if invalid, ok := errors.AsType[*domain.ErrInvalidRequest](err); ok {
+		writeError(w, http.StatusUnprocessableEntity, invalid.Error())
+	} else if ctx.Err() != nil {
+		h.logger.Error(operation+" timed out", "error", err, "request_id", requestid.FromContext(r.Context()))
+		writeError(w, http.StatusGatewayTimeout, "query timed out")
+	} else {
+		h.logger.Error(operation+" failed", "error", err, "request_id", requestid.FromContext(r.Context()))
+		writeError(w, http.StatusInternalServerError, "internal server error")
+	}
+}
+
+func parseCatalogFilter(r *http.Request) (*domain.CatalogFilter, error) {
+	query := r.URL.Query()
+	filter := &domain.CatalogFilter{
+		Variable: query.Get("variable"),
+		Dataset:  query.Get("dataset"),
+	}
+	var err error
+	if from := query.Get("from"); from != "" {
+		if filter.From, err = parseTime(from); err != nil {
+			return nil, fmt.Errorf("could not parse from: %v", err)
+		}
+	}
+	if to := query.Get("to"); to != "" {
+		if filter.To, err = parseTime(to); err != nil {
+			return nil, fmt.Errorf("could not parse to: %v", err)
+		}
+	}
+	if limit := query.Get("limit"); limit != "" {
+		if filter.Limit, err = strconv.Atoi(limit); err != nil {
+			return nil, fmt.Errorf("could not parse limit: %v", err)
+		}
+	}
+
+	return filter, nil
+}
+
+func newCatalogEntryResponse(entry domain.CatalogEntry) CatalogEntryResponse {
+	return CatalogEntryResponse{
+		ID:        entry.ID,
+		Variable:  entry.Variable,
+		Unit:      entry.Unit,
+		Timestamp: entry.Timestamp,
+		CreatedAt: entry.CreatedAt,
+		RawFile:   newRawFileResponse(entry.RawFile),
+		Loaded:    entry.Rows > 0,
+		Rows:      entry.Rows,
+	}
+}
+
+func newRawFileResponse(rawFile domain.RawFile) RawFileResponse {
+	return RawFileResponse{
+		ID:        rawFile.ID,
+		Source:    rawFile.Source,
+		Dataset:   rawFile.Dataset,
+		Date:      rawFile.Date.Format(time.DateOnly),
+		S3Key:     rawFile.S3Key,
+		CreatedAt: rawFile.CreatedAt,
+	}
+}
@@ -0,0 +1,239 @@
+package api_test
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/kacper-wojtaszczyk/jackfruit/serving-go/internal/api"
+	"github.com/kacper-wojtaszczyk/jackfruit/serving-go/internal/domain"
+)
+
+type mockCatalogProvider struct {
+	entries   []domain.CatalogEntry
+	run       *domain.Run
+	quality   *domain.Quality
+	coverage  []domain.Coverage
+	err       error
+	filter    domain.CatalogFilter
+	variables []string
+}
+
+func (m *mockCatalogProvider) GetEntry(_ context.Context, id uuid.UUID) (*domain.CatalogEntry, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	for _, entry := range m.entries {
+		if entry.ID == id {
+			return &entry, nil
+		}
+	}
+	return nil, domain.ErrCatalogEntryNotFound
+}
+
+func (m *mockCatalogProvider) ListEntries(_ context.Context, filter domain.CatalogFilter) ([]domain.CatalogEntry, error) {
+	m.filter = filter
+	return m.entries, m.err
+}
+
+func (m *mockCatalogProvider) GetCoverage(_ context.Context, variables ...string) ([]domain.Coverage, error) {
+	m.variables = variables
+	return m.coverage, m.err
+}
+
+func (m *mockCatalogProvider) GetQuality(ctx context.Context, id uuid.UUID) (*domain.Quality, error) {
+	if _, err := m.GetEntry(ctx, id); err != nil {
+		return nil, err
+	}
+	return m.quality, nil
+}
+
+func (m *mockCatalogProvider) GetRun(_ context.Context, runID uuid.UUID) (*domain.Run, error) {
+	if m.run == nil || m.run.RawFile.ID != runID {
+		return nil, domain.ErrRawFileNotFound
+	}
+	return m.run, nil
+}
+
+func newCatalogMux(catalog *mockCatalogProvider) *http.ServeMux {
+	mux := http.NewServeMux()
+	api.NewHandler(&mockVariableProvider{}, slog.New(slog.DiscardHandler), api.WithCatalog(catalog)).RegisterRoutes(mux)
+	return mux
+}
+
+func TestHandleGetCatalogEntry(t *testing.T) {
+	id := uuid.New()
+	catalog := &mockCatalogProvider{entries: []domain.CatalogEntry{{
+		ID:       id,
+		Variable: "pm2p5",
+		RawFile:  domain.RawFile{Dataset: "cams-europe-air-quality-forecast", Date: time.Date(2025, 3, 11, 0, 0, 0, 0, time.UTC)},
+		Rows:     42,
+	}}}
+	mux := newCatalogMux(catalog)
+
+	tests := []struct {
+		name   string
+		path   string
+		status int
+	}{
+		{name: "found", path: "/v1/catalog/" + id.String(), status: http.StatusOK},
+		{name: "unknown", path: "/v1/catalog/" + uuid.NewString(), status: http.StatusNotFound},
+		{name: "malformed", path: "/v1/catalog/not-a-uuid", status: http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			mux.ServeHTTP(w, httptest.NewRequest("GET", tt.path, nil))
+			if w.Code != tt.status {
+				t.Fatalf("expected status %d, got %d: %s", tt.status, w.Code, w.Body.String())
+			}
+			if tt.status != http.StatusOK {
+				return
+			}
+			var response api.CatalogEntryResponse
+			if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+				t.Fatal(err)
+			}
+			if response.ID != id || !response.Loaded || response.Rows != 42 || response.RawFile.Date != "2025-03-11" {
+				t.Errorf("unexpected response %+v", response)
+			}
+		})
+	}
+}
+
+func TestHandleListCatalog(t *testing.T) {
+	catalog := &mockCatalogProvider{entries: []domain.CatalogEntry{{ID: uuid.New()}}}
+	mux := newCatalogMux(catalog)
+
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, httptest.NewRequest("GET", "/v1/catalog?variable=pm2p5&from=2025-03-01T00:00:00Z&limit=5", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	want := domain.CatalogFilter{Variable: "pm2p5", From: time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC), Limit: 5}
+	if catalog.filter != want {
+		t.Errorf("expected filter %+v, got %+v", want, catalog.filter)
+	}
+	var response api.CatalogListResponse
+	if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+		t.Fatal(err)
+	}
+	if len(response.Entries) != 1 || response.Entries[0].Loaded {
+		t.Errorf("expected one unloaded entry, got %+v", response.Entries)
+	}
+
+	w = httptest.NewRecorder()
+	mux.ServeHTTP(w, httptest.NewRequest("GET", "/v1/catalog?limit=ten", nil))
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected status 400 for unparsable limit, got %d", w.Code)
+	}
+
+	catalog.err = &domain.ErrInvalidRequest{Field: "limit", Message: "must be between 1 and 1000, 5000 given"}
+	w = httptest.NewRecorder()
+	mux.ServeHTTP(w, httptest.NewRequest("GET", "/v1/catalog?limit=5000", nil))
+	if w.Code != http.StatusUnprocessableEntity {
+		t.Errorf("expected status 422 for out-of-range limit, got %d", w.Code)
+	}
+}
+
+func TestHandleCoverage(t *testing.T) {
+	catalog := &mockCatalogProvider{coverage: []domain.Coverage{{
+		Variable: "pm2p5",
+		Extent:   domain.BoundingBox{MinLat: 30, MinLon: -25, MaxLat: 72, MaxLon: 45},
+		Rows:     1000,
+	}}}
+	mux := newCatalogMux(catalog)
+
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, httptest.NewRequest("GET", "/v1/coverage?variables=pm2p5,pm10", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	if !slices.Equal(catalog.variables, []string{"pm2p5", "pm10"}) {
+		t.Errorf("expected variables passed through, got %v", catalog.variables)
+	}
+	var response api.CoverageResponse
+	if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+		t.Fatal(err)
+	}
+	if len(response.Variables) != 1 || response.Variables[0].Extent.MaxLat != 72 || response.Variables[0].Rows != 1000 {
+		t.Errorf("unexpected coverage response %+v", response)
+	}
+}
+
+func TestCatalogRoutes_RequireProvider(t *testing.T) {
+	mux := http.NewServeMux()
+	api.NewHandler(&mockVariableProvider{}, slog.New(slog.DiscardHandler)).RegisterRoutes(mux)
+
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, httptest.NewRequest("GET", "/v1/coverage", nil))
+	if w.Code != http.StatusNotFound {
+		t.Errorf("expected catalog routes to be absent without a provider, got %d", w.Code)
+	}
+}
+
+func TestHandleCatalogQuality(t *testing.T) {
+	id := uuid.New()
+	catalog := &mockCatalogProvider{
+		entries: []domain.CatalogEntry{{ID: id}},
+		quality: &domain.Quality{
+			QualityStats: domain.QualityStats{Rows: 100, ExpectedRows: 120, NaNRows: 25, Min: 1, Max: 9, Mean: 4},
+			Flags:        []string{domain.QualityIncompleteGrid},
+		},
+	}
+	mux := newCatalogMux(catalog)
+
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, httptest.NewRequest("GET", "/v1/catalog/"+id.String()+"/quality", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var response api.QualityResponse
+	if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+		t.Fatal(err)
+	}
+	if response.NaNFraction != 0.25 || !slices.Equal(response.Flags, []string{"incomplete_grid"}) {
+		t.Errorf("unexpected quality response %+v", response)
+	}
+
+	w = httptest.NewRecorder()
+	mux.ServeHTTP(w, httptest.NewRequest("GET", "/v1/catalog/"+uuid.NewString()+"/quality", nil))
+	if w.Code != http.StatusNotFound {
+		t.Errorf("expected status 404 for unknown entry, got %d", w.Code)
+	}
+}
+
+func TestHandleGetRun(t *testing.T) {
+	runID := uuid.New()
+	catalog := &mockCatalogProvider{run: &domain.Run{
+		RawFile: domain.RawFile{ID: runID, S3Key: "ads/cams-europe-air-quality-forecast/2025-03-11/" + runID.String() + ".grib"},
+		Entries: []domain.CatalogEntry{{ID: uuid.New(), Rows: 40}, {ID: uuid.New(), Rows: 2}, {ID: uuid.New()}},
+	}}
+	mux := newCatalogMux(catalog)
+
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, httptest.NewRequest("GET", "/v1/runs/"+runID.String(), nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var response api.RunResponse
+	if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+		t.Fatal(err)
+	}
+	if response.RawFile.S3Key != catalog.run.RawFile.S3Key || len(response.Entries) != 3 || response.Rows != 42 {
+		t.Errorf("unexpected run response %+v", response)
+	}
+
+	w = httptest.NewRecorder()
+	mux.ServeHTTP(w, httptest.NewRequest("GET", "/v1/runs/"+uuid.NewString(), nil))
+	if w.Code != http.StatusNotFound {
+		t.Errorf("expected status 404 for unknown run, got %d", w.Code)
+	}
+}
@@ -7,25 +7,76 @@ import (
 	"net/http"
 	"time"
 
+	"github.com/kacper-wojtaszczyk/jackfruit/serving-go/internal/audit"
 	"github.com/kacper-wojtaszczyk/jackfruit/serving-go/internal/domain"
+	"github.com/kacper-wojtaszczyk/jackfruit/serving-go/internal/requestid"
 )
 
 type Handler struct {
 	variableProvider variableProvider
 	logger           *slog.Logger
+	readinessChecks  []readinessCheck
+	catalogProvider  catalogProvider
+	auditRecorder    audit.Recorder
+	auditQuerier     auditQuerier
+	reconciler       reconciler
+	statusProvider   statusProvider
+	adminToken       string
+	flags            flagSet
+	logLevels        logLevelSet
+	seriesProvider   seriesProvider
 }
 
 type variableProvider interface {
 	GetVariables(ctx context.Context, ts time.Time, lat float32, lon float32, vars []string) ([]domain.VariableResult, error)
+	GetAvailableVariables(ctx context.Context, ts time.Time, lat float32, lon float32, vars []string) ([]domain.VariableResult, []string, error)
 }
 
-func NewHandler(variableProvider variableProvider, logger *slog.Logger) *Handler {
-	return &Handler{variableProvider: variableProvider, logger: logger}
+type pinger interface {
+	Ping(ctx context.Context) error
+}
+
+type readinessCheck struct {
+	name   string
+	pinger pinger
+}
+
+type HandlerOption func(*Handler)
+
+// WithReadinessCheck adds a dependency that must answer Ping for GET /ready to succeed.
+func WithReadinessCheck(name string, p pinger) HandlerOption {
+	return func(h *Handler) {
+		h.readinessChecks = append(h.readinessChecks, readinessCheck{name: name, pinger: p})
+	}
+}
+
+func NewHandler(variableProvider variableProvider, logger *slog.Logger, opts ...HandlerOption) *Handler {
+	h := &Handler{variableProvider: variableProvider, logger: logger}
+	for _, opt := range opts {
+		opt(h)
+	}
+	return h
 }
 
 func (h *Handler) RegisterRoutes(mux *http.ServeMux) {
 	mux.HandleFunc("GET /health", h.handleHealth)
+	mux.HandleFunc("GET /ready", h.handleReady)
 	mux.HandleFunc("GET /v1/environmental", h.handleEnvironmental)
+	if h.catalogProvider != nil {
+		h.registerCatalogRoutes(mux)
+	}
+	if h.reconciler != nil {
+		mux.HandleFunc("GET /v1/reconciliation", h.handleReconciliation)
+	}
+	if h.seriesProvider != nil {
+		mux.HandleFunc("GET /v1/series", h.handleSeries)
+	}
+	if h.statusProvider != nil {
+		mux.HandleFunc("GET /v1/status", h.handleStatus)
+	}
+	if h.adminToken != "" {
+		h.registerAdminRoutes(mux)
+	}
 }
 
 func (h *Handler) handleEnvironmental(w http.ResponseWriter, r *http.Request) {
@@ -38,21 +89,37 @@ func (h *Handler) handleEnvironmental(w http.ResponseWriter, r *http.Request) {
 	ctx, cancel := context.WithTimeout(r.Context(), 18*time.Second)
 	defer cancel()
 
-	varResults, err := h.variableProvider.GetVariables(
-		ctx,
-		envReq.Timestamp,
-		envReq.Lat,
-		envReq.Lon,
-		envReq.Variables,
-	)
+	var varResults []domain.VariableResult
+	var missing []string
+	if envReq.Partial {
+		varResults, missing, err = h.variableProvider.GetAvailableVariables(
+			ctx,
+			envReq.Timestamp,
+			envReq.Lat,
+			envReq.Lon,
+			envReq.Variables,
+		)
+	} else {
+		varResults, err = h.variableProvider.GetVariables(
+			ctx,
+			envReq.Timestamp,
+			envReq.Lat,
+			envReq.Lon,
+			envReq.Variables,
+		)
+	}
 	if err != nil {
 		if notFound, ok := errors.AsType[*domain.ErrVariableNotFound](err); ok {
 			writeError(w, http.StatusNotFound, notFound.Error())
+		} else if stale, ok := errors.AsType[*domain.ErrStaleData](err); ok {
+			writeError(w, http.StatusNotFound, stale.Error())
+		} else if invalid, ok := errors.AsType[*domain.ErrInvalidRequest](err); ok {
+			writeError(w, http.StatusUnprocessableEntity, invalid.Error())
 		} else if ctx.Err() != nil {
-			h.logger.Error("variableProvider.GetVariables timed out", "error", err)
+			h.logger.Error("variableProvider.GetVariables timed out", "error", err, "request_id", requestid.FromContext(r.Context()))
 			writeError(w, http.StatusGatewayTimeout, "query timed out")
 		} else {
-			h.logger.Error("variableProvider.GetVariables failed", "error", err)
+			h.logger.Error("variableProvider.GetVariables failed", "error", err, "request_id", requestid.FromContext(r.Context()))
 			writeError(w, http.StatusInternalServerError, "internal server error")
 		}
 		return
@@ -63,6 +130,7 @@ func (h *Handler) handleEnvironmental(w http.ResponseWriter, r *http.Request) {
 		Lon:                envReq.Lon,
 		RequestedTimestamp: envReq.Timestamp,
 		Variables:          make([]VariableResponse, len(varResults)),
+		Missing:            missing,
 	}
 	for i, varResult := range varResults {
 		response.Variables[i] = VariableResponse{
@@ -77,6 +145,9 @@ func (h *Handler) handleEnvironmental(w http.ResponseWriter, r *http.Request) {
 				Dataset:   varResult.Lineage.Dataset,
 				RawFileID: varResult.Lineage.RawFileID,
 			},
+			DerivedFrom:    varResult.DerivedFrom,
+			InterpolatedTo: varResult.InterpolatedTo,
+			Stale:          varResult.Stale,
 		}
 	}
 
@@ -86,3 +157,16 @@ func (h *Handler) handleEnvironmental(w http.ResponseWriter, r *http.Request) {
 func (h *Handler) handleHealth(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusNoContent)
 }
+
+// handleReady reports 503 naming the first failing dependency, so orchestrators stop routing
+// traffic while e.g. ClickHouse is down; /health stays a pure liveness check.
+func (h *Handler) handleReady(w http.ResponseWriter, r *http.Request) {
+	for _, check := range h.readinessChecks {
+		if err := check.pinger.Ping(r.Context()); err != nil {
+			h.logger.Warn("readiness check failed", "check", check.name, "error", err)
+			writeError(w, http.StatusServiceUnavailable, check.name+" unavailable")
+			return
+		}
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -2,10 +2,12 @@ package api_test
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"log/slog"
 	"net/http"
 	"net/http/httptest"
+	"slices"
 	"strings"
 	"testing"
 	"time"
@@ -15,11 +17,17 @@ import (
 )
 
 type mockVariableProvider struct {
-	err error
+	results []domain.VariableResult
+	missing []string
+	err     error
 }
 
 func (m *mockVariableProvider) GetVariables(_ context.Context, _ time.Time, _ float32, _ float32, _ []string) ([]domain.VariableResult, error) {
-	return nil, m.err
+	return m.results, m.err
+}
+
+func (m *mockVariableProvider) GetAvailableVariables(_ context.Context, _ time.Time, _ float32, _ float32, _ []string) ([]domain.VariableResult, []string, error) {
+	return m.results, m.missing, m.err
 }
 
 func TestHandleEnvironmental_InternalError(t *testing.T) {
@@ -45,3 +53,124 @@ func TestHandleEnvironmental_InternalError(t *testing.T) {
 		t.Errorf("response body must contain 'internal server error', got: %s", body)
 	}
 }
+
+type mockPinger struct {
+	err error
+}
+
+func (m *mockPinger) Ping(_ context.Context) error {
+	return m.err
+}
+
+func TestHandleReady(t *testing.T) {
+	tests := []struct {
+		name       string
+		pingErr    error
+		wantStatus int
+	}{
+		{name: "dependencies up", wantStatus: http.StatusNoContent},
+		{name: "clickhouse down", pingErr: errors.New("dial tcp: connection refused"), wantStatus: http.StatusServiceUnavailable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mux := http.NewServeMux()
+			api.NewHandler(&mockVariableProvider{}, slog.New(slog.DiscardHandler),
+				api.WithReadinessCheck("clickhouse", &mockPinger{err: tt.pingErr}),
+			).RegisterRoutes(mux)
+
+			w := httptest.NewRecorder()
+			mux.ServeHTTP(w, httptest.NewRequest("GET", "/ready", nil))
+
+			if w.Code != tt.wantStatus {
+				t.Errorf("expected status %d, got %d", tt.wantStatus, w.Code)
+			}
+			if tt.pingErr != nil && strings.Contains(w.Body.String(), "refused") {
+				t.Errorf("response should not leak ping error details, got: %s", w.Body.String())
+			}
+		})
+	}
+}
+
+func TestHandleEnvironmental_Partial(t *testing.T) {
+	mock := &mockVariableProvider{
+		results: []domain.VariableResult{{Name: "pm2p5", Value: 12.5, Unit: "µg/m³"}},
+		missing: []string{"no2"},
+	}
+
+	mux := http.NewServeMux()
+	api.NewHandler(mock, slog.New(slog.DiscardHandler)).RegisterRoutes(mux)
+
+	req := httptest.NewRequest("GET", "/v1/environmental?lat=52.5&lon=13.4&timestamp=2025-03-11T00:00:00Z&variables=pm2p5,no2&partial=true", nil)
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var response api.EnvironmentalResponse
+	if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+		t.Fatal(err)
+	}
+	if len(response.Variables) != 1 || response.Variables[0].Name != "pm2p5" {
+		t.Errorf("expected pm2p5 in variables, got %+v", response.Variables)
+	}
+	if !slices.Equal(response.Missing, []string{"no2"}) {
+		t.Errorf("expected missing [no2], got %v", response.Missing)
+	}
+}
+
+func TestHandleEnvironmental_OmitsMissingWhenNotPartial(t *testing.T) {
+	mock := &mockVariableProvider{results: []domain.VariableResult{{Name: "pm2p5"}}, missing: []string{"no2"}}
+
+	mux := http.NewServeMux()
+	api.NewHandler(mock, slog.New(slog.DiscardHandler)).RegisterRoutes(mux)
+
+	req := httptest.NewRequest("GET", "/v1/environmental?lat=52.5&lon=13.4&timestamp=2025-03-11T00:00:00Z&variables=pm2p5", nil)
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+
+	if strings.Contains(w.Body.String(), "missing") {
+		t.Errorf("expected no missing field outside partial mode, got: %s", w.Body.String())
+	}
+}
+
+func TestHandleEnvironmental_InvalidRequest(t *testing.T) {
+	mock := &mockVariableProvider{err: &domain.ErrInvalidRequest{Field: "lat", Message: "must be between -90 and 90, 999 given"}}
+
+	mux := http.NewServeMux()
+	api.NewHandler(mock, slog.New(slog.DiscardHandler)).RegisterRoutes(mux)
+
+	req := httptest.NewRequest("GET", "/v1/environmental?lat=999&lon=13.4&timestamp=2025-03-11T00:00:00Z&variables=pm2p5", nil)
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+
+	if w.Code != http.StatusUnprocessableEntity {
+		t.Errorf("expected status 422, got %d", w.Code)
+	}
+	if !strings.Contains(w.Body.String(), "invalid lat") {
+		t.Errorf("expected validation message in body, got: %s", w.Body.String())
+	}
+}
+
+func TestHandleEnvironmental_StaleData(t *testing.T) {
+	mock := &mockVariableProvider{err: &domain.ErrStaleData{
+		Variable:     "pm2p5",
+		RefTimestamp: time.Date(2025, 3, 4, 0, 0, 0, 0, time.UTC),
+		MaxAge:       6 * time.Hour,
+	}}
+
+	mux := http.NewServeMux()
+	api.NewHandler(mock, slog.New(slog.DiscardHandler)).RegisterRoutes(mux)
+
+	req := httptest.NewRequest("GET", "/v1/environmental?lat=52.5&lon=13.4&timestamp=2025-03-11T00:00:00Z&variables=pm2p5", nil)
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+
+	if w.Code != http.StatusNotFound {
+		t.Errorf("expected status 404, got %d", w.Code)
+	}
+	if !strings.Contains(w.Body.String(), "stale") {
+		t.Errorf("expected stale message in body, got: %s", w.Body.String())
+	}
+}
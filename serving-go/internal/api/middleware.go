@@ -0,0 +1,112 @@
+package api
+
+import (
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/kacper-wojtaszczyk/jackfruit/serving-go/internal/requestid"
+)
+
+const requestIDHeader = "X-Request-ID"
+
+// maxRequestIDLength bounds client-supplied ids so they can't bloat logs.
+const maxRequestIDLength = 128
+
+// WithRequestID propagates the caller's X-Request-ID (or a generated one) through the
+// request context and echoes it in the response.
+func WithRequestID(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := r.Header.Get(requestIDHeader)
+		if id == "" || len(id) > maxRequestIDLength {
+			id = uuid.NewString()
+		}
+		w.Header().Set(requestIDHeader, id)
+		next.ServeHTTP(w, r.WithContext(requestid.WithID(r.Context(), id)))
+	})
+}
+
+// WithRateLimit allows each client address requestsPerMinute requests to /v1/ endpoints,
+// with bursts of up to burst, and answers 429 with Retry-After beyond that. Health, readiness
+// and metrics stay unlimited for probes and scrapers. Clients are keyed by the connection's
+// remote address, so behind a proxy the proxy itself must limit per client.
+func WithRateLimit(next http.Handler, requestsPerMinute, burst int) http.Handler {
+	limiter := &rateLimiter{
+		rate:    float64(requestsPerMinute) / 60,
+		burst:   float64(max(burst, 1)),
+		clients: make(map[string]*tokenBucket),
+		now:     time.Now,
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasPrefix(r.URL.Path, "/v1/") {
+			next.ServeHTTP(w, r)
+			return
+		}
+		client, _, err := net.SplitHostPort(r.RemoteAddr)
+		if err != nil {
+			client = r.RemoteAddr
+		}
+		if wait, ok := limiter.allow(client); !ok {
+			w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(wait.Seconds()))))
+			writeError(w, http.StatusTooManyRequests, "rate limit exceeded")
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// rateLimiter keeps a token bucket per client, dropping buckets that have refilled.
+type rateLimiter struct {
+	mu        sync.Mutex
+	rate      float64 // tokens per second
+	burst     float64
+	clients   map[string]*tokenBucket
+	lastSweep time.Time
+	now       func() time.Time
+}
+
+type tokenBucket struct {
+	tokens  float64
+	updated time.Time
+}
+
+// allow takes a token from client's bucket, or reports how long until one is available.
+func (l *rateLimiter) allow(client string) (time.Duration, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	now := l.now()
+	if now.Sub(l.lastSweep) > time.Minute {
+		for key, bucket := range l.clients {
+			if bucket.refill(now, l.rate, l.burst) == l.burst {
+				delete(l.clients, key)
+			}
+		}
+		l.lastSweep = now
+	}
+
+	bucket, ok := l.clients[client]
+	if !ok {
+		bucket = &tokenBucket{tokens: l.burst, updated: now}
+		l.clients[client] = bucket
+	}
+	if bucket.refill(now, l.rate, l.burst) < 1 {
+		if l.rate == 0 {
+			return time.Minute, false
+		}
+		return time.Duration((1 - bucket.tokens) / l.rate * float64(time.Second)), false
+	}
+	bucket.tokens--
+	return 0, true
+}
+
+func (b *tokenBucket) refill(now time.Time, rate, burst float64) float64 {
+	b.tokens = min(burst, b.tokens+now.Sub(b.updated).Seconds()*rate)
+	b.updated = now
+	return b.tokens
+}
@@ -0,0 +1,81 @@
+package api_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/kacper-wojtaszczyk/jackfruit/serving-go/internal/api"
+	"github.com/kacper-wojtaszczyk/jackfruit/serving-go/internal/requestid"
+)
+
+func TestWithRequestID(t *testing.T) {
+	tests := []struct {
+		name      string
+		header    string
+		wantReuse bool
+	}{
+		{name: "propagates caller id", header: "abc-123", wantReuse: true},
+		{name: "generates when missing", header: ""},
+		{name: "replaces oversized id", header: strings.Repeat("x", 200)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var seen string
+			handler := api.WithRequestID(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				seen = requestid.FromContext(r.Context())
+			}))
+
+			req := httptest.NewRequest("GET", "/health", nil)
+			if tt.header != "" {
+				req.Header.Set("X-Request-ID", tt.header)
+			}
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, req)
+
+			if seen == "" {
+				t.Fatal("expected request id in context")
+			}
+			if got := w.Header().Get("X-Request-ID"); got != seen {
+				t.Errorf("response header %q does not match context id %q", got, seen)
+			}
+			if (seen == tt.header) != tt.wantReuse {
+				t.Errorf("id %q, caller sent %q, want reuse %v", seen, tt.header, tt.wantReuse)
+			}
+		})
+	}
+}
+
+func TestWithRateLimit(t *testing.T) {
+	handler := api.WithRateLimit(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}), 1, 2)
+	get := func(path, remoteAddr string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("GET", path, nil)
+		req.RemoteAddr = remoteAddr
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+		return w
+	}
+
+	for range 2 {
+		if w := get("/v1/environmental", "192.0.2.1:1234"); w.Code != http.StatusNoContent {
+			t.Fatalf("expected burst to pass, got %d", w.Code)
+		}
+	}
+	w := get("/v1/environmental", "192.0.2.1:5678")
+	if w.Code != http.StatusTooManyRequests {
+		t.Fatalf("expected 429 after the burst, got %d", w.Code)
+	}
+	if w.Header().Get("Retry-After") == "" {
+		t.Error("expected Retry-After header")
+	}
+	if w := get("/v1/environmental", "192.0.2.2:1234"); w.Code != http.StatusNoContent {
+		t.Errorf("expected other clients to pass, got %d", w.Code)
+	}
+	if w := get("/health", "192.0.2.1:1234"); w.Code != http.StatusNoContent {
+		t.Errorf("expected /health to be unlimited, got %d", w.Code)
+	}
+}
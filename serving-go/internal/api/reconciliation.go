@@ -0,0 +1,85 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/kacper-wojtaszczyk/jackfruit/serving-go/internal/domain"
+)
+
+type reconciler interface {
+	Datasets() []string
+	Reconcile(ctx context.Context, dataset string, from, to time.Time) (*domain.Reconciliation, error)
+}
+
+// WithReconciliation serves the expected-vs-ingested reconciliation endpoint from r.
+func WithReconciliation(r reconciler) HandlerOption {
+	return func(h *Handler) {
+		h.reconciler = r
+	}
+}
+
+func (h *Handler) handleReconciliation(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	datasets := h.reconciler.Datasets()
+	var err error
+	if list := query.Get("datasets"); list != "" {
+		if datasets, err = parseStringList(list); err != nil {
+			writeError(w, http.StatusBadRequest, fmt.Sprintf("could not parse datasets: %v", err))
+			return
+		}
+	}
+	var from, to time.Time
+	if s := query.Get("from"); s != "" {
+		if from, err = time.Parse(time.DateOnly, s); err != nil {
+			writeError(w, http.StatusBadRequest, fmt.Sprintf("could not parse from: %v", err))
+			return
+		}
+	}
+	if s := query.Get("to"); s != "" {
+		if to, err = time.Parse(time.DateOnly, s); err != nil {
+			writeError(w, http.StatusBadRequest, fmt.Sprintf("could not parse to: %v", err))
+			return
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 18*time.Second)
+	defer cancel()
+	response := ReconciliationResponse{Datasets: make([]DatasetReconciliationResponse, 0, len(datasets))}
+	for _, dataset := range datasets {
+		reconciliation, err := h.reconciler.Reconcile(ctx, dataset, from, to)
+		if err != nil {
+			h.writeCatalogError(w, r, ctx, "reconciler.Reconcile", err)
+			return
+		}
+		response.Datasets = append(response.Datasets, newDatasetReconciliationResponse(*reconciliation))
+	}
+	writeJSON(w, http.StatusOK, response)
+}
+
+func newDatasetReconciliationResponse(reconciliation domain.Reconciliation) DatasetReconciliationResponse {
+	response := DatasetReconciliationResponse{
+		Dataset:    reconciliation.Dataset,
+		Cadence:    reconciliation.Cadence.String(),
+		Expected:   len(reconciliation.Partitions),
+		Missing:    []string{},
+		Partitions: make([]PartitionResponse, len(reconciliation.Partitions)),
+	}
+	for i, partition := range reconciliation.Partitions {
+		date := partition.Date.Format(time.DateOnly)
+		status := partition.Status()
+		if status != domain.PartitionIngested {
+			response.Missing = append(response.Missing, date)
+		}
+		response.Partitions[i] = PartitionResponse{Date: date, Status: string(status), RunIDs: []uuid.UUID{}}
+		for _, delivery := range partition.Deliveries {
+			response.Partitions[i].RunIDs = append(response.Partitions[i].RunIDs, delivery.RawFile.ID)
+			response.Partitions[i].Entries += delivery.Entries
+		}
+	}
+	return response
+}
@@ -0,0 +1,81 @@
+package api_test
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/kacper-wojtaszczyk/jackfruit/serving-go/internal/api"
+	"github.com/kacper-wojtaszczyk/jackfruit/serving-go/internal/domain"
+)
+
+type mockReconciler struct {
+	reconciliations map[string]*domain.Reconciliation
+	from, to        time.Time
+}
+
+func (m *mockReconciler) Datasets() []string {
+	return []string{"cams"}
+}
+
+func (m *mockReconciler) Reconcile(_ context.Context, dataset string, from, to time.Time) (*domain.Reconciliation, error) {
+	m.from, m.to = from, to
+	if reconciliation, ok := m.reconciliations[dataset]; ok {
+		return reconciliation, nil
+	}
+	return nil, &domain.ErrInvalidRequest{Field: "dataset", Message: "no expected cadence"}
+}
+
+func TestHandleReconciliation(t *testing.T) {
+	runID := uuid.New()
+	date := func(d int) time.Time { return time.Date(2026, 10, d, 0, 0, 0, 0, time.UTC) }
+	reconciler := &mockReconciler{reconciliations: map[string]*domain.Reconciliation{"cams": {
+		Dataset: "cams",
+		Cadence: 24 * time.Hour,
+		Partitions: []domain.ExpectedPartition{
+			{Date: date(11)},
+			{Date: date(12), Deliveries: []domain.Delivery{{RawFile: domain.RawFile{ID: runID}, Entries: 96}}},
+		},
+	}}}
+	mux := http.NewServeMux()
+	api.NewHandler(&mockVariableProvider{}, slog.New(slog.DiscardHandler), api.WithReconciliation(reconciler)).RegisterRoutes(mux)
+
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, httptest.NewRequest("GET", "/v1/reconciliation?from=2026-10-11&to=2026-10-12", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	if !reconciler.from.Equal(date(11)) || !reconciler.to.Equal(date(12)) {
+		t.Errorf("expected range 11..12 Oct, got %s..%s", reconciler.from, reconciler.to)
+	}
+	var response api.ReconciliationResponse
+	if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	got := response.Datasets[0]
+	if got.Cadence != "24h0m0s" || got.Expected != 2 || !slices.Equal(got.Missing, []string{"2026-10-11"}) {
+		t.Errorf("unexpected reconciliation %+v", got)
+	}
+	if p := got.Partitions[1]; p.Status != "ingested" || !slices.Equal(p.RunIDs, []uuid.UUID{runID}) || p.Entries != 96 {
+		t.Errorf("unexpected partition %+v", p)
+	}
+
+	w = httptest.NewRecorder()
+	mux.ServeHTTP(w, httptest.NewRequest("GET", "/v1/reconciliation?datasets=other", nil))
+	if w.Code != http.StatusUnprocessableEntity {
+		t.Errorf("expected status 422 for a dataset without cadence, got %d", w.Code)
+	}
+
+	w = httptest.NewRecorder()
+	mux.ServeHTTP(w, httptest.NewRequest("GET", "/v1/reconciliation?from=2026-10-11T00:00:00Z", nil))
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected status 400 for a timestamp instead of a date, got %d", w.Code)
+	}
+}
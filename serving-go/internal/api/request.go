@@ -13,6 +13,8 @@ type EnvironmentalRequest struct {
 	Lon       float32
 	Timestamp time.Time
 	Variables []string
+	// Partial returns the variables that have data instead of failing on the first missing one.
+	Partial bool
 }
 
 func ParseEnvironmentalRequest(r *http.Request) (*EnvironmentalRequest, error) {
@@ -21,16 +23,10 @@ func ParseEnvironmentalRequest(r *http.Request) (*EnvironmentalRequest, error) {
 	if err != nil {
 		return nil, fmt.Errorf("could not parse latitude: %v", err)
 	}
-	if lat < -90 || lat > 90 {
-		return nil, fmt.Errorf("latitude must be between [-90, 90], %f given", lat)
-	}
 	lon, err := parseFloat32(query.Get("lon"))
 	if err != nil {
 		return nil, fmt.Errorf("could not parse longitude: %v", err)
 	}
-	if lon < -180 || lon > 180 {
-		return nil, fmt.Errorf("longitude must be between [-180, 180], %f given", lon)
-	}
 	timestamp, err := parseTime(query.Get("timestamp"))
 	if err != nil {
 		return nil, fmt.Errorf("could not parse timestamp: %v", err)
@@ -42,12 +38,68 @@ func ParseEnvironmentalRequest(r *http.Request) (*EnvironmentalRequest, error) {
 	if len(variables) == 0 {
 		return nil, fmt.Errorf("no variables provided")
 	}
+	partial := false
+	if partialString := query.Get("partial"); partialString != "" {
+		if partial, err = strconv.ParseBool(partialString); err != nil {
+			return nil, fmt.Errorf("could not parse partial: %v", err)
+		}
+	}
 
 	return &EnvironmentalRequest{
 		Lat:       lat,
 		Lon:       lon,
 		Timestamp: timestamp,
 		Variables: variables,
+		Partial:   partial,
+	}, nil
+}
+
+type SeriesRequest struct {
+	Lat      float32
+	Lon      float32
+	Variable string
+	From     time.Time
+	To       time.Time
+	// MaxPoints downsamples longer series to this many points; zero returns every sample.
+	MaxPoints int
+}
+
+func ParseSeriesRequest(r *http.Request) (*SeriesRequest, error) {
+	query := r.URL.Query()
+	lat, err := parseFloat32(query.Get("lat"))
+	if err != nil {
+		return nil, fmt.Errorf("could not parse latitude: %v", err)
+	}
+	lon, err := parseFloat32(query.Get("lon"))
+	if err != nil {
+		return nil, fmt.Errorf("could not parse longitude: %v", err)
+	}
+	variable := strings.TrimSpace(query.Get("variable"))
+	if variable == "" {
+		return nil, fmt.Errorf("no variable provided")
+	}
+	from, err := parseTime(query.Get("from"))
+	if err != nil {
+		return nil, fmt.Errorf("could not parse from: %v", err)
+	}
+	to, err := parseTime(query.Get("to"))
+	if err != nil {
+		return nil, fmt.Errorf("could not parse to: %v", err)
+	}
+	maxPoints := 0
+	if maxPointsString := query.Get("max_points"); maxPointsString != "" {
+		if maxPoints, err = strconv.Atoi(maxPointsString); err != nil {
+			return nil, fmt.Errorf("could not parse max_points: %v", err)
+		}
+	}
+
+	return &SeriesRequest{
+		Lat:       lat,
+		Lon:       lon,
+		Variable:  variable,
+		From:      from,
+		To:        to,
+		MaxPoints: maxPoints,
 	}, nil
 }
 
@@ -55,46 +55,60 @@ func TestParseEnvironmentalRequest(t *testing.T) {
 			wantErr: true,
 		},
 		{
-			name: "lat out of range",
+			name: "lat out of range is left to the domain",
 			params: url.Values{
 				"lat":       {"999"},
 				"lon":       {"13.4"},
 				"timestamp": {validTime},
 				"variables": {"pm2p5"},
 			},
-			wantErr: true,
+			wantErr:      false,
+			wantVarCount: 1,
 		},
 		{
-			name: "lon out of range",
+			name: "invalid timestamp format",
 			params: url.Values{
 				"lat":       {"52.5"},
-				"lon":       {"-999"},
-				"timestamp": {validTime},
+				"lon":       {"13.4"},
+				"timestamp": {"2025-03-11"},
 				"variables": {"pm2p5"},
 			},
 			wantErr: true,
 		},
 		{
-			name: "invalid timestamp format",
+			name: "multiple vars",
 			params: url.Values{
 				"lat":       {"52.5"},
 				"lon":       {"13.4"},
-				"timestamp": {"2025-03-11"},
-				"variables": {"pm2p5"},
+				"timestamp": {validTime},
+				"variables": {"pm2p5,pm10"},
 			},
-			wantErr: true,
+			wantErr:      false,
+			wantVarCount: 2,
 		},
 		{
-			name: "multiple vars",
+			name: "partial mode",
 			params: url.Values{
 				"lat":       {"52.5"},
 				"lon":       {"13.4"},
 				"timestamp": {validTime},
 				"variables": {"pm2p5,pm10"},
+				"partial":   {"true"},
 			},
 			wantErr:      false,
 			wantVarCount: 2,
 		},
+		{
+			name: "invalid partial flag",
+			params: url.Values{
+				"lat":       {"52.5"},
+				"lon":       {"13.4"},
+				"timestamp": {validTime},
+				"variables": {"pm2p5"},
+				"partial":   {"maybe"},
+			},
+			wantErr: true,
+		},
 		{
 			name: "empty var in middle",
 			params: url.Values{
@@ -17,6 +17,8 @@ type EnvironmentalResponse struct {
 	Lon                float32            `json:"lon"`
 	RequestedTimestamp time.Time          `json:"requested_timestamp"`
 	Variables          []VariableResponse `json:"variables"`
+	// Missing lists requested variables without data; only set for partial requests.
+	Missing []string `json:"missing,omitempty"`
 }
 
 type VariableResponse struct {
@@ -27,6 +29,32 @@ type VariableResponse struct {
 	ActualLat    float32         `json:"actual_lat"`
 	ActualLon    float32         `json:"actual_lon"`
 	Lineage      LineageResponse `json:"lineage"`
+	DerivedFrom  []string        `json:"derived_from,omitempty"`
+	// InterpolatedTo is set when value was interpolated between ref_timestamp and this timestamp.
+	InterpolatedTo time.Time `json:"interpolated_to,omitzero"`
+	// Stale is set on partial requests when the value is older than the variable's max age.
+	Stale bool `json:"stale,omitempty"`
+}
+
+type SeriesResponse struct {
+	Variable  string    `json:"variable"`
+	Lat       float32   `json:"lat"`
+	Lon       float32   `json:"lon"`
+	From      time.Time `json:"from"`
+	To        time.Time `json:"to"`
+	Unit      string    `json:"unit"`
+	ActualLat float32   `json:"actual_lat"`
+	ActualLon float32   `json:"actual_lon"`
+	// TotalPoints is the number of stored samples in range, before downsampling.
+	TotalPoints int                   `json:"total_points"`
+	Points      []SeriesPointResponse `json:"points"`
+}
+
+type SeriesPointResponse struct {
+	Timestamp time.Time `json:"timestamp"`
+	// Value is null for NaN samples.
+	Value     *float64  `json:"value"`
+	CatalogID uuid.UUID `json:"catalog_id"`
 }
 
 type LineageResponse struct {
@@ -35,6 +63,74 @@ type LineageResponse struct {
 	RawFileID uuid.UUID `json:"raw_file_id"`
 }
 
+type CatalogEntryResponse struct {
+	ID        uuid.UUID       `json:"id"`
+	Variable  string          `json:"variable"`
+	Unit      string          `json:"unit"`
+	Timestamp time.Time       `json:"timestamp"`
+	CreatedAt time.Time       `json:"created_at"`
+	RawFile   RawFileResponse `json:"raw_file"`
+	// Loaded reports whether grid_data holds any rows for the entry.
+	Loaded bool   `json:"loaded"`
+	Rows   uint64 `json:"rows"`
+}
+
+type RawFileResponse struct {
+	ID        uuid.UUID `json:"id"`
+	Source    string    `json:"source"`
+	Dataset   string    `json:"dataset"`
+	Date      string    `json:"date"`
+	S3Key     string    `json:"s3_key"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type QualityResponse struct {
+	CatalogID    uuid.UUID `json:"catalog_id"`
+	Rows         uint64    `json:"rows"`
+	ExpectedRows uint64    `json:"expected_rows"`
+	NaNFraction  float64   `json:"nan_fraction"`
+	ZeroRows     uint64    `json:"zero_rows"`
+	Min          float32   `json:"min"`
+	Max          float32   `json:"max"`
+	Mean         float32   `json:"mean"`
+	// Units are the distinct units stored in grid_data for the entry.
+	Units   []string `json:"units"`
+	LatStep float32  `json:"lat_step"`
+	LonStep float32  `json:"lon_step"`
+	Flags   []string `json:"flags"`
+}
+
+// RunResponse traces one ingestion run: its raw object and the entries loaded from it.
+type RunResponse struct {
+	RawFile RawFileResponse        `json:"raw_file"`
+	Entries []CatalogEntryResponse `json:"entries"`
+	// Rows is the total grid_data row count across Entries.
+	Rows uint64 `json:"rows"`
+}
+
+type CatalogListResponse struct {
+	Entries []CatalogEntryResponse `json:"entries"`
+}
+
+type CoverageResponse struct {
+	Variables []VariableCoverageResponse `json:"variables"`
+}
+
+type VariableCoverageResponse struct {
+	Name           string              `json:"name"`
+	FirstTimestamp time.Time           `json:"first_timestamp"`
+	LastTimestamp  time.Time           `json:"last_timestamp"`
+	Extent         BoundingBoxResponse `json:"extent"`
+	Rows           uint64              `json:"rows"`
+}
+
+type BoundingBoxResponse struct {
+	MinLat float32 `json:"min_lat"`
+	MinLon float32 `json:"min_lon"`
+	MaxLat float32 `json:"max_lat"`
+	MaxLon float32 `json:"max_lon"`
+}
+
 func writeJSON(w http.ResponseWriter, status int, data any) {
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(status)
@@ -44,3 +140,87 @@ func writeJSON(w http.ResponseWriter, status int, data any) {
 func writeError(w http.ResponseWriter, status int, message string) {
 	writeJSON(w, status, ErrorResponse{Error: message})
 }
+
+type AuditListResponse struct {
+	Events []AuditEventResponse `json:"events"`
+}
+
+type AuditEventResponse struct {
+	Time    time.Time         `json:"time"`
+	Actor   string            `json:"actor"`
+	Action  string            `json:"action"`
+	Target  string            `json:"target"`
+	Details map[string]string `json:"details,omitempty"`
+}
+
+type ReconciliationResponse struct {
+	Datasets []DatasetReconciliationResponse `json:"datasets"`
+}
+
+type DatasetReconciliationResponse struct {
+	Dataset  string `json:"dataset"`
+	Cadence  string `json:"cadence"`
+	Expected int    `json:"expected"`
+	// Missing lists the expected dates that aren't ingested, whether missing or empty.
+	Missing    []string            `json:"missing"`
+	Partitions []PartitionResponse `json:"partitions"`
+}
+
+type PartitionResponse struct {
+	Date string `json:"date"`
+	// Status is missing (no raw file), empty (raw files without catalog entries) or ingested.
+	Status  string      `json:"status"`
+	RunIDs  []uuid.UUID `json:"run_ids"`
+	Entries int         `json:"entries"`
+}
+
+type StatusResponse struct {
+	// Status is stale when any variable or dataset is, otherwise ok.
+	Status    string                   `json:"status"`
+	CheckedAt time.Time                `json:"checked_at"`
+	Variables []VariableStatusResponse `json:"variables"`
+	Datasets  []DatasetStatusResponse  `json:"datasets"`
+}
+
+type VariableStatusResponse struct {
+	Name string `json:"name"`
+	// LatestTimestamp and LastLoadedAt are unset for variables without data or recorded loads.
+	LatestTimestamp time.Time `json:"latest_timestamp,omitzero"`
+	LastLoadedAt    time.Time `json:"last_loaded_at,omitzero"`
+	Lag             string    `json:"lag"`
+	MaxLag          string    `json:"max_lag,omitempty"`
+	Stale           bool      `json:"stale"`
+}
+
+type DatasetStatusResponse struct {
+	Name string `json:"name"`
+	// LastLoadedAt is unset for datasets with a cadence but no ingested raw file.
+	LastLoadedAt time.Time `json:"last_loaded_at,omitzero"`
+	Lag          string    `json:"lag"`
+	Cadence      string    `json:"cadence,omitempty"`
+	Stale        bool      `json:"stale"`
+}
+
+type FlagListResponse struct {
+	Flags []FlagResponse `json:"flags"`
+}
+
+type FlagResponse struct {
+	Name string `json:"name"`
+	// Percent is the share of requests the flag is on for, from 0 to 100.
+	Percent int `json:"percent"`
+	// Default is the startup value, which clearing the override restores.
+	Default    int  `json:"default"`
+	Overridden bool `json:"overridden"`
+}
+
+type LogLevelListResponse struct {
+	Components []LogLevelResponse `json:"components"`
+}
+
+type LogLevelResponse struct {
+	Component string `json:"component"`
+	Level     string `json:"level"`
+	// Default is the startup level, which resetting restores.
+	Default string `json:"default"`
+}
@@ -0,0 +1,67 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"math"
+	"net/http"
+	"time"
+
+	"github.com/kacper-wojtaszczyk/jackfruit/serving-go/internal/domain"
+)
+
+type seriesProvider interface {
+	GetSeries(ctx context.Context, variable string, lat, lon float32, from, to time.Time) ([]domain.GridSample, error)
+}
+
+// WithSeries serves the time-series endpoint from p.
+func WithSeries(p seriesProvider) HandlerOption {
+	return func(h *Handler) {
+		h.seriesProvider = p
+	}
+}
+
+func (h *Handler) handleSeries(w http.ResponseWriter, r *http.Request) {
+	req, err := ParseSeriesRequest(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 18*time.Second)
+	defer cancel()
+	series, err := h.seriesProvider.GetSeries(ctx, req.Variable, req.Lat, req.Lon, req.From, req.To)
+	if notFound, ok := errors.AsType[*domain.ErrVariableNotFound](err); ok {
+		writeError(w, http.StatusNotFound, notFound.Error())
+		return
+	}
+	if err != nil {
+		h.writeCatalogError(w, r, ctx, "seriesProvider.GetSeries", err)
+		return
+	}
+	points, err := domain.Downsample(series, req.MaxPoints)
+	if err != nil {
+		h.writeCatalogError(w, r, ctx, "domain.Downsample", err)
+		return
+	}
+
+	response := SeriesResponse{
+		Variable:    domain.CanonicalVariable(req.Variable),
+		Lat:         req.Lat,
+		Lon:         req.Lon,
+		From:        req.From,
+		To:          req.To,
+		Unit:        series[0].Unit,
+		ActualLat:   series[0].Lat,
+		ActualLon:   series[0].Lon,
+		TotalPoints: len(series),
+		Points:      make([]SeriesPointResponse, len(points)),
+	}
+	for i, point := range points {
+		response.Points[i] = SeriesPointResponse{Timestamp: point.Timestamp, CatalogID: point.CatalogID}
+		if value := float64(point.Value); !math.IsNaN(value) {
+			response.Points[i].Value = &value
+		}
+	}
+	writeJSON(w, http.StatusOK, response)
+}
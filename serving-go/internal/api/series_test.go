@@ -0,0 +1,91 @@
+package api_test
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/kacper-wojtaszczyk/jackfruit/serving-go/internal/api"
+	"github.com/kacper-wojtaszczyk/jackfruit/serving-go/internal/domain"
+)
+
+type mockSeriesProvider struct {
+	series []domain.GridSample
+	err    error
+}
+
+func (m *mockSeriesProvider) GetSeries(_ context.Context, _ string, _, _ float32, _, _ time.Time) ([]domain.GridSample, error) {
+	return m.series, m.err
+}
+
+func newSeriesMux(provider *mockSeriesProvider) *http.ServeMux {
+	mux := http.NewServeMux()
+	api.NewHandler(&mockVariableProvider{}, slog.New(slog.DiscardHandler), api.WithSeries(provider)).RegisterRoutes(mux)
+	return mux
+}
+
+func TestHandleSeries(t *testing.T) {
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	series := make([]domain.GridSample, 100)
+	for i := range series {
+		series[i] = domain.GridSample{Timestamp: start.Add(time.Duration(i) * time.Hour), Value: float32(i % 7), Unit: "µg/m³", Lat: 52.5, Lon: 13.4}
+	}
+	series[50].Value = float32(math.NaN())
+	mux := newSeriesMux(&mockSeriesProvider{series: series})
+
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, httptest.NewRequest("GET", "/v1/series?lat=52.52&lon=13.41&variable=pm25&from=2026-01-01T00:00:00Z&to=2026-01-31T00:00:00Z", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var response api.SeriesResponse
+	if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if response.Variable != "pm2p5" || response.Unit != "µg/m³" || response.ActualLat != 52.5 || response.TotalPoints != 100 || len(response.Points) != 100 {
+		t.Errorf("unexpected response %+v", response)
+	}
+	if response.Points[50].Value != nil || response.Points[51].Value == nil || *response.Points[51].Value != 2 {
+		t.Errorf("expected NaN as null and values otherwise, got %v, %v", response.Points[50].Value, response.Points[51].Value)
+	}
+
+	w = httptest.NewRecorder()
+	mux.ServeHTTP(w, httptest.NewRequest("GET", "/v1/series?lat=52.52&lon=13.41&variable=pm2p5&from=2026-01-01T00:00:00Z&to=2026-01-31T00:00:00Z&max_points=10", nil))
+	response = api.SeriesResponse{}
+	if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if response.TotalPoints != 100 || len(response.Points) != 10 || !response.Points[9].Timestamp.Equal(series[99].Timestamp) {
+		t.Errorf("expected 10 downsampled points ending at the last sample, got %d of %d", len(response.Points), response.TotalPoints)
+	}
+}
+
+func TestHandleSeries_Errors(t *testing.T) {
+	const valid = "/v1/series?lat=52.5&lon=13.4&variable=pm2p5&from=2026-01-01T00:00:00Z&to=2026-01-02T00:00:00Z"
+	tests := []struct {
+		name     string
+		provider *mockSeriesProvider
+		target   string
+		want     int
+	}{
+		{name: "missing variable", provider: &mockSeriesProvider{}, target: "/v1/series?lat=52.5&lon=13.4&from=2026-01-01T00:00:00Z&to=2026-01-02T00:00:00Z", want: http.StatusBadRequest},
+		{name: "unparsable max_points", provider: &mockSeriesProvider{}, target: valid + "&max_points=many", want: http.StatusBadRequest},
+		{name: "max_points too small", provider: &mockSeriesProvider{series: make([]domain.GridSample, 5)}, target: valid + "&max_points=1", want: http.StatusUnprocessableEntity},
+		{name: "not found", provider: &mockSeriesProvider{err: &domain.ErrVariableNotFound{Variable: "pm2p5"}}, target: valid, want: http.StatusNotFound},
+		{name: "invalid range", provider: &mockSeriesProvider{err: &domain.ErrInvalidRequest{Field: "from", Message: "must be set and not after to"}}, target: valid, want: http.StatusUnprocessableEntity},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			newSeriesMux(tt.provider).ServeHTTP(w, httptest.NewRequest("GET", tt.target, nil))
+			if w.Code != tt.want {
+				t.Errorf("expected status %d, got %d: %s", tt.want, w.Code, w.Body.String())
+			}
+		})
+	}
+}
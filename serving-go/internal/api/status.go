@@ -0,0 +1,64 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/kacper-wojtaszczyk/jackfruit/serving-go/internal/domain"
+)
+
+type statusProvider interface {
+	GetStatus(ctx context.Context) (*domain.Status, error)
+}
+
+// WithStatus serves the data freshness status endpoint from p.
+func WithStatus(p statusProvider) HandlerOption {
+	return func(h *Handler) {
+		h.statusProvider = p
+	}
+}
+
+func (h *Handler) handleStatus(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 18*time.Second)
+	defer cancel()
+	status, err := h.statusProvider.GetStatus(ctx)
+	if err != nil {
+		h.writeCatalogError(w, r, ctx, "statusProvider.GetStatus", err)
+		return
+	}
+
+	response := StatusResponse{
+		Status:    "ok",
+		CheckedAt: status.CheckedAt,
+		Variables: make([]VariableStatusResponse, len(status.Variables)),
+		Datasets:  make([]DatasetStatusResponse, len(status.Datasets)),
+	}
+	if status.Stale() {
+		response.Status = "stale"
+	}
+	for i, variable := range status.Variables {
+		response.Variables[i] = VariableStatusResponse{
+			Name:            variable.Variable,
+			LatestTimestamp: variable.LatestTimestamp,
+			LastLoadedAt:    variable.LastLoadedAt,
+			Lag:             variable.Lag.String(),
+			Stale:           variable.Stale,
+		}
+		if variable.MaxLag > 0 {
+			response.Variables[i].MaxLag = variable.MaxLag.String()
+		}
+	}
+	for i, dataset := range status.Datasets {
+		response.Datasets[i] = DatasetStatusResponse{
+			Name:         dataset.Dataset,
+			LastLoadedAt: dataset.LastLoadedAt,
+			Lag:          dataset.Lag.String(),
+			Stale:        dataset.Stale,
+		}
+		if dataset.Cadence > 0 {
+			response.Datasets[i].Cadence = dataset.Cadence.String()
+		}
+	}
+	writeJSON(w, http.StatusOK, response)
+}
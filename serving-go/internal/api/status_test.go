@@ -0,0 +1,65 @@
+package api_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/kacper-wojtaszczyk/jackfruit/serving-go/internal/api"
+	"github.com/kacper-wojtaszczyk/jackfruit/serving-go/internal/domain"
+)
+
+type mockStatusProvider struct {
+	status *domain.Status
+	err    error
+}
+
+func (m *mockStatusProvider) GetStatus(_ context.Context) (*domain.Status, error) {
+	return m.status, m.err
+}
+
+func TestHandleStatus(t *testing.T) {
+	now := time.Date(2026, 10, 14, 12, 0, 0, 0, time.UTC)
+	provider := &mockStatusProvider{status: &domain.Status{CheckedAt: now, Variables: []domain.VariableStatus{
+		{Variable: "no2", MaxLag: 6 * time.Hour, Stale: true},
+		{Variable: "pm2p5", LatestTimestamp: now.Add(47 * time.Hour), LastLoadedAt: now.Add(-time.Hour), Lag: time.Hour},
+	}, Datasets: []domain.DatasetStatus{
+		{Dataset: "cams-europe-air-quality-forecast", LastLoadedAt: now.Add(-time.Hour), Lag: time.Hour, Cadence: 24 * time.Hour},
+	}}}
+	mux := http.NewServeMux()
+	api.NewHandler(&mockVariableProvider{}, slog.New(slog.DiscardHandler), api.WithStatus(provider)).RegisterRoutes(mux)
+
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, httptest.NewRequest("GET", "/v1/status", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var response api.StatusResponse
+	if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if response.Status != "stale" || len(response.Variables) != 2 {
+		t.Fatalf("expected a stale status with 2 variables, got %+v", response)
+	}
+	if got := response.Variables[0]; got.MaxLag != "6h0m0s" || !got.LatestTimestamp.IsZero() || !got.Stale {
+		t.Errorf("unexpected no2 status %+v", got)
+	}
+	if got := response.Variables[1]; got.Lag != "1h0m0s" || !got.LastLoadedAt.Equal(now.Add(-time.Hour)) || got.MaxLag != "" || got.Stale {
+		t.Errorf("unexpected pm2p5 status %+v", got)
+	}
+	if len(response.Datasets) != 1 || response.Datasets[0].Cadence != "24h0m0s" || response.Datasets[0].Lag != "1h0m0s" || response.Datasets[0].Stale {
+		t.Errorf("unexpected dataset statuses %+v", response.Datasets)
+	}
+
+	provider.err = errors.New("clickhouse down")
+	w = httptest.NewRecorder()
+	mux.ServeHTTP(w, httptest.NewRequest("GET", "/v1/status", nil))
+	if w.Code != http.StatusInternalServerError {
+		t.Errorf("expected status 500 when ClickHouse fails, got %d", w.Code)
+	}
+}
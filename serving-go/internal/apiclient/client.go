@@ -0,0 +1,178 @@
+// Package apiclient is a Go client for the serving API, used by operator tooling.
+package apiclient
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/kacper-wojtaszczyk/jackfruit/serving-go/internal/api"
+	"github.com/kacper-wojtaszczyk/jackfruit/serving-go/internal/domain"
+)
+
+// Error is a non-2xx API response.
+type Error struct {
+	Status  int
+	Message string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("%d %s: %s", e.Status, http.StatusText(e.Status), e.Message)
+}
+
+type Client struct {
+	baseURL    string
+	httpClient *http.Client
+}
+
+type Option func(*Client)
+
+// WithHTTPClient replaces the default client, which times out after 30s.
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *Client) {
+		c.httpClient = httpClient
+	}
+}
+
+// New returns a client for the API at baseURL, e.g. http://localhost:8080.
+func New(baseURL string, opts ...Option) *Client {
+	c := &Client{
+		baseURL:    strings.TrimSuffix(baseURL, "/"),
+		httpClient: &http.Client{Timeout: 30 * time.Second},
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+func (c *Client) Environmental(ctx context.Context, req api.EnvironmentalRequest) (*api.EnvironmentalResponse, error) {
+	query := url.Values{
+		"lat":       {strconv.FormatFloat(float64(req.Lat), 'f', -1, 32)},
+		"lon":       {strconv.FormatFloat(float64(req.Lon), 'f', -1, 32)},
+		"timestamp": {req.Timestamp.UTC().Format(time.RFC3339)},
+		"variables": {strings.Join(req.Variables, ",")},
+	}
+	if req.Partial {
+		query.Set("partial", "true")
+	}
+	var response api.EnvironmentalResponse
+	if err := c.get(ctx, "/v1/environmental", query, &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+func (c *Client) Series(ctx context.Context, req api.SeriesRequest) (*api.SeriesResponse, error) {
+	query := url.Values{
+		"lat":      {strconv.FormatFloat(float64(req.Lat), 'f', -1, 32)},
+		"lon":      {strconv.FormatFloat(float64(req.Lon), 'f', -1, 32)},
+		"variable": {req.Variable},
+		"from":     {req.From.UTC().Format(time.RFC3339)},
+		"to":       {req.To.UTC().Format(time.RFC3339)},
+	}
+	if req.MaxPoints != 0 {
+		query.Set("max_points", strconv.Itoa(req.MaxPoints))
+	}
+	var response api.SeriesResponse
+	if err := c.get(ctx, "/v1/series", query, &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+func (c *Client) ListCatalog(ctx context.Context, filter domain.CatalogFilter) ([]api.CatalogEntryResponse, error) {
+	query := url.Values{}
+	if filter.Variable != "" {
+		query.Set("variable", filter.Variable)
+	}
+	if filter.Dataset != "" {
+		query.Set("dataset", filter.Dataset)
+	}
+	if !filter.From.IsZero() {
+		query.Set("from", filter.From.UTC().Format(time.RFC3339))
+	}
+	if !filter.To.IsZero() {
+		query.Set("to", filter.To.UTC().Format(time.RFC3339))
+	}
+	if filter.Limit != 0 {
+		query.Set("limit", strconv.Itoa(filter.Limit))
+	}
+	var response api.CatalogListResponse
+	if err := c.get(ctx, "/v1/catalog", query, &response); err != nil {
+		return nil, err
+	}
+	return response.Entries, nil
+}
+
+func (c *Client) GetCatalogEntry(ctx context.Context, id uuid.UUID) (*api.CatalogEntryResponse, error) {
+	var response api.CatalogEntryResponse
+	if err := c.get(ctx, "/v1/catalog/"+id.String(), nil, &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+func (c *Client) GetQuality(ctx context.Context, id uuid.UUID) (*api.QualityResponse, error) {
+	var response api.QualityResponse
+	if err := c.get(ctx, "/v1/catalog/"+id.String()+"/quality", nil, &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+func (c *Client) GetRun(ctx context.Context, id uuid.UUID) (*api.RunResponse, error) {
+	var response api.RunResponse
+	if err := c.get(ctx, "/v1/runs/"+id.String(), nil, &response); err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// Coverage returns coverage for variables, or for all variables when none are given.
+func (c *Client) Coverage(ctx context.Context, variables ...string) ([]api.VariableCoverageResponse, error) {
+	query := url.Values{}
+	if len(variables) > 0 {
+		query.Set("variables", strings.Join(variables, ","))
+	}
+	var response api.CoverageResponse
+	if err := c.get(ctx, "/v1/coverage", query, &response); err != nil {
+		return nil, err
+	}
+	return response.Variables, nil
+}
+
+func (c *Client) get(ctx context.Context, path string, query url.Values, v any) error {
+	target := c.baseURL + path
+	if len(query) > 0 {
+		target += "?" + query.Encode()
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, target, nil)
+	if err != nil {
+		return fmt.Errorf("build request: %w", err)
+	}
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("GET %s: %w", path, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		var errResponse api.ErrorResponse
+		if err := json.NewDecoder(resp.Body).Decode(&errResponse); err != nil || errResponse.Error == "" {
+			errResponse.Error = "unexpected response body"
+		}
+		return &Error{Status: resp.StatusCode, Message: errResponse.Error}
+	}
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return fmt.Errorf("decode %s response: %w", path, err)
+	}
+	return nil
+}
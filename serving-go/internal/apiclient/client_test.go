@@ -0,0 +1,102 @@
+package apiclient
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/kacper-wojtaszczyk/jackfruit/serving-go/internal/api"
+	"github.com/kacper-wojtaszczyk/jackfruit/serving-go/internal/domain"
+)
+
+func TestClient_Environmental(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		want := "lat=52.52&lon=13.4&partial=true&timestamp=2025-03-12T14%3A55%3A00Z&variables=pm2p5%2Cpm10"
+		if r.URL.Path != "/v1/environmental" || r.URL.RawQuery != want {
+			t.Errorf("unexpected request %s?%s", r.URL.Path, r.URL.RawQuery)
+		}
+		_ = json.NewEncoder(w).Encode(api.EnvironmentalResponse{Variables: []api.VariableResponse{{Name: "pm2p5", Value: 12}}})
+	}))
+	defer server.Close()
+
+	response, err := New(server.URL+"/").Environmental(t.Context(), api.EnvironmentalRequest{
+		Lat:       52.52,
+		Lon:       13.4,
+		Timestamp: time.Date(2025, 3, 12, 14, 55, 0, 0, time.UTC),
+		Variables: []string{"pm2p5", "pm10"},
+		Partial:   true,
+	})
+	if err != nil {
+		t.Fatalf("Environmental returned error: %v", err)
+	}
+	if len(response.Variables) != 1 || response.Variables[0].Value != 12 {
+		t.Errorf("unexpected response %+v", response)
+	}
+}
+
+func TestClient_Series(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		want := "from=2025-03-01T00%3A00%3A00Z&lat=52.52&lon=13.4&max_points=200&to=2025-06-01T00%3A00%3A00Z&variable=pm10"
+		if r.URL.Path != "/v1/series" || r.URL.RawQuery != want {
+			t.Errorf("unexpected request %s?%s", r.URL.Path, r.URL.RawQuery)
+		}
+		_ = json.NewEncoder(w).Encode(api.SeriesResponse{Variable: "pm10", TotalPoints: 2208})
+	}))
+	defer server.Close()
+
+	response, err := New(server.URL).Series(t.Context(), api.SeriesRequest{
+		Lat:       52.52,
+		Lon:       13.4,
+		Variable:  "pm10",
+		From:      time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC),
+		To:        time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC),
+		MaxPoints: 200,
+	})
+	if err != nil {
+		t.Fatalf("Series returned error: %v", err)
+	}
+	if response.Variable != "pm10" || response.TotalPoints != 2208 {
+		t.Errorf("unexpected response %+v", response)
+	}
+}
+
+func TestClient_ListCatalog(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.URL.RawQuery; got != "from=2025-03-01T00%3A00%3A00Z&limit=5&variable=pm10" {
+			t.Errorf("unexpected query %q", got)
+		}
+		_ = json.NewEncoder(w).Encode(api.CatalogListResponse{Entries: []api.CatalogEntryResponse{{Variable: "pm10"}}})
+	}))
+	defer server.Close()
+
+	entries, err := New(server.URL).ListCatalog(t.Context(), domain.CatalogFilter{
+		Variable: "pm10",
+		From:     time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC),
+		Limit:    5,
+	})
+	if err != nil {
+		t.Fatalf("ListCatalog returned error: %v", err)
+	}
+	if len(entries) != 1 || entries[0].Variable != "pm10" {
+		t.Errorf("unexpected entries %+v", entries)
+	}
+}
+
+func TestClient_ErrorResponse(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		_ = json.NewEncoder(w).Encode(api.ErrorResponse{Error: "raw file not found"})
+	}))
+	defer server.Close()
+
+	_, err := New(server.URL).GetRun(t.Context(), uuid.New())
+	apiErr, ok := errors.AsType[*Error](err)
+	if !ok || apiErr.Status != http.StatusNotFound || apiErr.Message != "raw file not found" {
+		t.Errorf("expected 404 Error, got %v", err)
+	}
+}
@@ -0,0 +1,169 @@
+// Package audit records security-relevant operations to an append-only sink.
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ClickHouse/clickhouse-go/v2"
+	"github.com/ClickHouse/clickhouse-go/v2/lib/driver"
+
+	"github.com/kacper-wojtaszczyk/jackfruit/serving-go/internal/domain"
+)
+
+// Event is one audited operation. Action is a dotted name such as "retention.delete";
+// Target is what it acted on.
+type Event struct {
+	Time    time.Time         `json:"time"`
+	Actor   string            `json:"actor"`
+	Action  string            `json:"action"`
+	Target  string            `json:"target"`
+	Details map[string]string `json:"details,omitempty"`
+}
+
+type Recorder interface {
+	Record(ctx context.Context, event Event) error
+}
+
+// FileSink appends events to a file as JSON lines.
+type FileSink struct {
+	mu   sync.Mutex
+	file *os.File
+}
+
+// OpenFile opens path for appending, creating it if needed.
+func OpenFile(path string) (*FileSink, error) {
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("open audit log: %w", err)
+	}
+	return &FileSink{file: file}, nil
+}
+
+func (s *FileSink) Record(_ context.Context, event Event) error {
+	line, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("encode audit event: %w", err)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, err := s.file.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("write audit event: %w", err)
+	}
+	return nil
+}
+
+func (s *FileSink) Close() error {
+	return s.file.Close()
+}
+
+// Store records events in the ClickHouse audit_log table (migration 0004) and reads them back.
+type Store struct {
+	conn driver.Conn
+}
+
+func NewStore(conn driver.Conn) *Store {
+	return &Store{conn: conn}
+}
+
+func (s *Store) Record(ctx context.Context, event Event) error {
+	batch, err := s.conn.PrepareBatch(ctx, "INSERT INTO audit_log (time, actor, action, target, details)")
+	if err != nil {
+		return fmt.Errorf("prepare audit insert: %w", err)
+	}
+	defer batch.Close()
+
+	details := event.Details
+	if details == nil {
+		details = map[string]string{}
+	}
+	if err := batch.Append(event.Time, event.Actor, event.Action, event.Target, details); err != nil {
+		return fmt.Errorf("append audit event: %w", err)
+	}
+	if err := batch.Send(); err != nil {
+		return fmt.Errorf("send audit event: %w", err)
+	}
+	return nil
+}
+
+const (
+	DefaultLimit = 100
+	MaxLimit     = 1000
+)
+
+// Filter narrows audit queries. Zero fields don't filter.
+type Filter struct {
+	Actor  string
+	Action string
+	From   time.Time
+	To     time.Time
+	Limit  int
+}
+
+func (f Filter) query() (string, []any) {
+	var (
+		where []string
+		args  []any
+	)
+	if f.Actor != "" {
+		where = append(where, "actor = @actor")
+		args = append(args, clickhouse.Named("actor", f.Actor))
+	}
+	if f.Action != "" {
+		where = append(where, "action = @action")
+		args = append(args, clickhouse.Named("action", f.Action))
+	}
+	if !f.From.IsZero() {
+		where = append(where, "time >= @from")
+		args = append(args, clickhouse.Named("from", f.From))
+	}
+	if !f.To.IsZero() {
+		where = append(where, "time <= @to")
+		args = append(args, clickhouse.Named("to", f.To))
+	}
+
+	query := "SELECT time, actor, action, target, details FROM audit_log"
+	if len(where) > 0 {
+		query += " WHERE " + strings.Join(where, " AND ")
+	}
+	query += fmt.Sprintf(" ORDER BY time DESC LIMIT %d", f.Limit)
+	return query, args
+}
+
+// Query returns matching events, newest first. It fails with *domain.ErrInvalidRequest
+// for limits outside [1, MaxLimit].
+func (s *Store) Query(ctx context.Context, filter Filter) ([]Event, error) {
+	if filter.Limit == 0 {
+		filter.Limit = DefaultLimit
+	}
+	if filter.Limit < 1 || filter.Limit > MaxLimit {
+		return nil, &domain.ErrInvalidRequest{
+			Field:   "limit",
+			Message: fmt.Sprintf("must be between 1 and %d, %d given", MaxLimit, filter.Limit),
+		}
+	}
+	query, args := filter.query()
+	rows, err := s.conn.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("audit query: %w", err)
+	}
+	defer rows.Close()
+
+	var events []Event
+	for rows.Next() {
+		var event Event
+		if err := rows.Scan(&event.Time, &event.Actor, &event.Action, &event.Target, &event.Details); err != nil {
+			return nil, fmt.Errorf("scan audit event: %w", err)
+		}
+		events = append(events, event)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate audit events: %w", err)
+	}
+	return events, nil
+}
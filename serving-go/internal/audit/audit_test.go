@@ -0,0 +1,84 @@
+package audit
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestFileSink_AppendsJSONLines(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.log")
+	events := []Event{
+		{Time: time.Date(2025, 3, 11, 8, 0, 0, 0, time.UTC), Actor: "ops", Action: "retention.delete", Target: "grid_data"},
+		{Time: time.Date(2025, 3, 11, 8, 1, 0, 0, time.UTC), Actor: "ops", Action: "retention.drop_partition", Target: "grid_data",
+			Details: map[string]string{"partition": "20250101"}},
+	}
+
+	// Reopening must append rather than truncate.
+	for _, event := range events {
+		sink, err := OpenFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := sink.Record(t.Context(), event); err != nil {
+			t.Fatalf("Record returned error: %v", err)
+		}
+		if err := sink.Close(); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	var got []Event
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var event Event
+		if err := json.Unmarshal(scanner.Bytes(), &event); err != nil {
+			t.Fatalf("decode line %q: %v", scanner.Text(), err)
+		}
+		got = append(got, event)
+	}
+	if len(got) != 2 || got[0].Action != "retention.delete" || got[1].Details["partition"] != "20250101" {
+		t.Errorf("unexpected events %+v", got)
+	}
+}
+
+func TestFilter_Query(t *testing.T) {
+	tests := []struct {
+		name     string
+		filter   Filter
+		want     string
+		wantArgs int
+	}{
+		{
+			name:   "unfiltered",
+			filter: Filter{Limit: 100},
+			want:   "SELECT time, actor, action, target, details FROM audit_log ORDER BY time DESC LIMIT 100",
+		},
+		{
+			name:   "all filters",
+			filter: Filter{Actor: "ops", Action: "retention.delete", From: time.Unix(1, 0), To: time.Unix(2, 0), Limit: 5},
+			want: "SELECT time, actor, action, target, details FROM audit_log " +
+				"WHERE actor = @actor AND action = @action AND time >= @from AND time <= @to ORDER BY time DESC LIMIT 5",
+			wantArgs: 4,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query, args := tt.filter.query()
+			if query != tt.want {
+				t.Errorf("query mismatch\n got: %s\nwant: %s", query, tt.want)
+			}
+			if len(args) != tt.wantArgs {
+				t.Errorf("expected %d args, got %d", tt.wantArgs, len(args))
+			}
+		})
+	}
+}
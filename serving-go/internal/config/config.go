@@ -1,22 +1,154 @@
 package config
 
 import (
+	"fmt"
+	"log/slog"
 	"os"
+	"strconv"
+	"strings"
+	"time"
 )
 
 // Config holds the application configuration.
 type Config struct {
-	Port               string
+	Port string
+
 	ClickHouseHost     string
 	ClickHousePort     string
 	ClickHouseUser     string
 	ClickHousePassword string
 	ClickHouseDatabase string
-	PostgresHost       string
-	PostgresPort       string
-	PostgresUser       string
-	PostgresPassword   string
-	PostgresDB         string
+	// ClickHouseProtocol is native (default) or http, for networks where only the HTTP
+	// interface is reachable.
+	ClickHouseProtocol string
+	ClickHouseHTTPPort string
+	// ClickHouseAddrs lists host:port replicas from CLICKHOUSE_HOSTS, falling back to
+	// CLICKHOUSE_HOST and the port of the configured protocol.
+	ClickHouseAddrs []string
+	// ClickHouseConnOpenStrategy picks the replica for each new connection: in_order
+	// (failover to the next host), round_robin or random.
+	ClickHouseConnOpenStrategy string
+	// ClickHouseMaxExecutionTime is the connection-wide server-side limit, in whole seconds,
+	// for queries whose context has no deadline; per-call QuerySettings and deadlines
+	// override it. 0 means unlimited.
+	ClickHouseMaxExecutionTime time.Duration
+	ClickHousePool             ClickHousePool
+	ClickHouseRetry            ClickHouseRetry
+	ClickHouseTLS              ClickHouseTLS
+	// ClickHouseMigrateOnStart applies pending embedded migrations before serving.
+	ClickHouseMigrateOnStart bool
+	// ClickHouseLatestFastPath serves current-conditions lookups from the grid_latest view.
+	ClickHouseLatestFastPath bool
+	// ClickHouseGeohashPrefilter narrows nearest-cell searches by the geohash column.
+	ClickHouseGeohashPrefilter bool
+	// ClickHouseSlowQueryThreshold logs queries at least this slow; zero disables the log.
+	ClickHouseSlowQueryThreshold time.Duration
+
+	PostgresHost     string
+	PostgresPort     string
+	PostgresUser     string
+	PostgresPassword string
+	PostgresDB       string
+
+	GridCache GridCache
+	// MaxConcurrentLookups caps in-flight grid and lineage lookups across requests; 0 disables the cap.
+	MaxConcurrentLookups int
+	// InterpolationMaxGaps enables temporal interpolation per variable, bounded by the
+	// largest gap between the two bracketing timestamps.
+	InterpolationMaxGaps map[string]time.Duration
+	// MaxDataAges flags per-variable data older than this, relative to the requested time, as stale.
+	MaxDataAges map[string]time.Duration
+	// MaxLoadLags marks a variable stale in /v1/status when its last load is older than this,
+	// relative to now. It is separate from MaxDataAges: a daily forecast may serve fresh values
+	// for a day after it was loaded.
+	MaxLoadLags map[string]time.Duration
+	// HedgeDelay duplicates grid store calls slower than this; zero disables hedging.
+	HedgeDelay time.Duration
+	// SourcePrecedence ranks lineage datasets, most preferred first; empty keeps newest-wins.
+	SourcePrecedence []string
+	// SourcePrecedenceLookback bounds how far back a preferred dataset may win over a newer one.
+	SourcePrecedenceLookback time.Duration
+
+	Retention Retention
+	// DatasetCadences is how often each dataset is expected to be ingested, in whole days;
+	// it drives the reconciliation endpoint and marks overdue datasets in /v1/status.
+	DatasetCadences map[string]time.Duration
+	// AdminToken is the bearer token for /v1/admin endpoints; empty disables them.
+	AdminToken string
+	// FeatureFlags and FeatureFlagsFile set startup flag values as rollout percents; the
+	// environment wins.
+	FeatureFlags     map[string]int
+	FeatureFlagsFile string
+	// AuditLogFile makes commands and the server write audit events to this JSON-lines file
+	// instead of the ClickHouse audit_log table.
+	AuditLogFile string
+	Demo         Demo
+	// LogLevel is the level of every component without a LogLevels entry.
+	LogLevel  slog.Level
+	LogLevels map[string]slog.Level
+}
+
+// Demo is the public sandbox profile: only anonymous point and series lookups and the status
+// endpoint are served, limited to a small extent, recent data and a per-client request rate.
+type Demo struct {
+	Enabled bool
+	// MinLat, MinLon, MaxLat and MaxLon bound the requestable area, inclusive.
+	MinLat float32
+	MinLon float32
+	MaxLat float32
+	MaxLon float32
+	// MaxHistory bounds how far before now requests may ask.
+	MaxHistory        time.Duration
+	RequestsPerMinute int
+	Burst             int
+}
+
+// Retention configures the grid_data retention and compaction job (cmd/retention).
+// Zero max ages keep data forever.
+type Retention struct {
+	DefaultMaxAge time.Duration
+	MaxAges       map[string]time.Duration
+	CompactWindow time.Duration
+}
+
+// GridCache configures the in-process cache in front of the grid retriever.
+// A zero DefaultTTL with no per-variable TTLs disables it.
+type GridCache struct {
+	DefaultTTL time.Duration
+	TTLs       map[string]time.Duration
+	MaxEntries int
+}
+
+// ClickHousePool holds connection pool and transport tuning for the ClickHouse driver.
+// Zero values leave the driver defaults in place.
+type ClickHousePool struct {
+	MaxOpenConns    int
+	MaxIdleConns    int
+	ConnMaxLifetime time.Duration
+	DialTimeout     time.Duration
+	ReadTimeout     time.Duration
+	// Compression is a driver compression method name: none, lz4, lz4hc, zstd, gzip, deflate, br.
+	Compression string
+}
+
+// ClickHouseRetry controls retries of read queries after dropped connections.
+type ClickHouseRetry struct {
+	MaxAttempts    int
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+}
+
+// ClickHouseTLS configures secure native-protocol connections (e.g. ClickHouse Cloud on port 9440).
+type ClickHouseTLS struct {
+	Enabled bool
+	// CAFile is a PEM bundle used instead of the system roots.
+	CAFile string
+	// CertFile and KeyFile are a PEM client certificate pair for mutual TLS.
+	CertFile   string
+	KeyFile    string
+	ServerName string
+	// InsecureSkipVerify disables certificate verification. Development only.
+	InsecureSkipVerify bool
 }
 
 func getEnv(key, fallback string) string {
@@ -26,19 +158,351 @@ func getEnv(key, fallback string) string {
 	return fallback
 }
 
+// getEnvList splits a comma-separated variable, dropping empty elements.
+func getEnvList(key string) []string {
+	var list []string
+	for item := range strings.SplitSeq(os.Getenv(key), ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			list = append(list, item)
+		}
+	}
+	return list
+}
+
+func getEnvInt(key string, fallback int) (int, error) {
+	v := os.Getenv(key)
+	if v == "" {
+		return fallback, nil
+	}
+	i, err := strconv.Atoi(v)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", key, err)
+	}
+	if i < 0 {
+		return 0, fmt.Errorf("%s: must not be negative, %d given", key, i)
+	}
+	return i, nil
+}
+
+func getEnvBool(key string, fallback bool) (bool, error) {
+	v := os.Getenv(key)
+	if v == "" {
+		return fallback, nil
+	}
+	b, err := strconv.ParseBool(v)
+	if err != nil {
+		return false, fmt.Errorf("%s: %w", key, err)
+	}
+	return b, nil
+}
+
+// getEnvPercentMap parses comma-separated name=value pairs where each value is a bool
+// (0 or 100 percent) or a percent with a % suffix, e.g. "interpolation=false,source_precedence=25%".
+func getEnvPercentMap(key string) (map[string]int, error) {
+	items := getEnvList(key)
+	if len(items) == 0 {
+		return nil, nil
+	}
+	values := make(map[string]int, len(items))
+	for _, item := range items {
+		name, value, ok := strings.Cut(item, "=")
+		name, value = strings.TrimSpace(name), strings.TrimSpace(value)
+		if !ok || name == "" {
+			return nil, fmt.Errorf("%s: expected name=bool or name=percent%%, %q given", key, item)
+		}
+		if digits, ok := strings.CutSuffix(value, "%"); ok {
+			percent, err := strconv.Atoi(digits)
+			if err != nil || percent < 0 || percent > 100 {
+				return nil, fmt.Errorf("%s: %s: expected a percent between 0%% and 100%%, %q given", key, name, value)
+			}
+			values[name] = percent
+			continue
+		}
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %s: %w", key, name, err)
+		}
+		values[name] = 0
+		if b {
+			values[name] = 100
+		}
+	}
+	return values, nil
+}
+
+// getEnvDurationMap parses comma-separated name=duration pairs, e.g. "pm2p5=1h,no2=30m".
+func getEnvDurationMap(key string) (map[string]time.Duration, error) {
+	items := getEnvList(key)
+	if len(items) == 0 {
+		return nil, nil
+	}
+	durations := make(map[string]time.Duration, len(items))
+	for _, item := range items {
+		name, value, ok := strings.Cut(item, "=")
+		if !ok || strings.TrimSpace(name) == "" {
+			return nil, fmt.Errorf("%s: expected name=duration, %q given", key, item)
+		}
+		d, err := time.ParseDuration(strings.TrimSpace(value))
+		if err != nil {
+			return nil, fmt.Errorf("%s: %s: %w", key, name, err)
+		}
+		if d < 0 {
+			return nil, fmt.Errorf("%s: %s: must not be negative, %s given", key, name, d)
+		}
+		durations[strings.TrimSpace(name)] = d
+	}
+	return durations, nil
+}
+
+func getEnvLevel(key string, fallback slog.Level) (slog.Level, error) {
+	v := os.Getenv(key)
+	if v == "" {
+		return fallback, nil
+	}
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(v)); err != nil {
+		return 0, fmt.Errorf("%s: %w", key, err)
+	}
+	return level, nil
+}
+
+// getEnvLevelMap parses comma-separated name=level pairs, e.g. "clickhouse=debug,api=warn".
+func getEnvLevelMap(key string) (map[string]slog.Level, error) {
+	items := getEnvList(key)
+	if len(items) == 0 {
+		return nil, nil
+	}
+	levels := make(map[string]slog.Level, len(items))
+	for _, item := range items {
+		name, value, ok := strings.Cut(item, "=")
+		if !ok || strings.TrimSpace(name) == "" {
+			return nil, fmt.Errorf("%s: expected name=level, %q given", key, item)
+		}
+		var level slog.Level
+		if err := level.UnmarshalText([]byte(strings.TrimSpace(value))); err != nil {
+			return nil, fmt.Errorf("%s: %s: %w", key, name, err)
+		}
+		levels[strings.TrimSpace(name)] = level
+	}
+	return levels, nil
+}
+
+// getEnvBBox parses a "minLat,minLon,maxLat,maxLon" rectangle.
+func getEnvBBox(key, fallback string) ([4]float32, error) {
+	var bbox [4]float32
+	parts := strings.Split(getEnv(key, fallback), ",")
+	if len(parts) != 4 {
+		return bbox, fmt.Errorf("%s: expected minLat,minLon,maxLat,maxLon, %d values given", key, len(parts))
+	}
+	for i, part := range parts {
+		f, err := strconv.ParseFloat(strings.TrimSpace(part), 32)
+		if err != nil {
+			return bbox, fmt.Errorf("%s: %w", key, err)
+		}
+		bbox[i] = float32(f)
+	}
+	if bbox[0] > bbox[2] || bbox[1] > bbox[3] {
+		return bbox, fmt.Errorf("%s: minimum must not exceed maximum", key)
+	}
+	return bbox, nil
+}
+
+func getEnvDuration(key string, fallback time.Duration) (time.Duration, error) {
+	v := os.Getenv(key)
+	if v == "" {
+		return fallback, nil
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", key, err)
+	}
+	if d < 0 {
+		return 0, fmt.Errorf("%s: must not be negative, %s given", key, d)
+	}
+	return d, nil
+}
+
 // Load reads configuration from environment variables.
-func Load() *Config {
-	return &Config{
+func Load() (*Config, error) {
+	cfg := &Config{
 		Port:               getEnv("PORT", "8080"),
 		ClickHouseHost:     getEnv("CLICKHOUSE_HOST", "localhost"),
 		ClickHousePort:     getEnv("CLICKHOUSE_NATIVE_PORT", "9097"),
 		ClickHouseUser:     getEnv("CLICKHOUSE_USER", "jackfruit"),
 		ClickHousePassword: getEnv("CLICKHOUSE_PASSWORD", "jackfruit"),
 		ClickHouseDatabase: getEnv("CLICKHOUSE_DATABASE", "jackfruit"),
+		ClickHouseProtocol: getEnv("CLICKHOUSE_PROTOCOL", "native"),
+		ClickHouseHTTPPort: getEnv("CLICKHOUSE_HTTP_PORT", "8123"),
 		PostgresHost:       getEnv("POSTGRES_HOST", "localhost"),
 		PostgresPort:       getEnv("POSTGRES_PORT", "5432"),
 		PostgresUser:       getEnv("POSTGRES_USER", "jackfruit"),
 		PostgresPassword:   getEnv("POSTGRES_PASSWORD", "jackfruit"),
 		PostgresDB:         getEnv("POSTGRES_DB", "jackfruit"),
+		AuditLogFile:       getEnv("AUDIT_LOG_FILE", ""),
+		AdminToken:         getEnv("ADMIN_TOKEN", ""),
+		FeatureFlagsFile:   getEnv("FEATURE_FLAGS_FILE", ""),
+	}
+
+	port := cfg.ClickHousePort
+	switch cfg.ClickHouseProtocol {
+	case "native":
+	case "http":
+		port = cfg.ClickHouseHTTPPort
+	default:
+		return nil, fmt.Errorf("CLICKHOUSE_PROTOCOL: unknown protocol %q (expected native or http)", cfg.ClickHouseProtocol)
+	}
+	cfg.ClickHouseAddrs = getEnvList("CLICKHOUSE_HOSTS")
+	if len(cfg.ClickHouseAddrs) == 0 {
+		cfg.ClickHouseAddrs = []string{cfg.ClickHouseHost + ":" + port}
+	}
+	cfg.ClickHouseConnOpenStrategy = getEnv("CLICKHOUSE_CONN_OPEN_STRATEGY", "in_order")
+	switch cfg.ClickHouseConnOpenStrategy {
+	case "in_order", "round_robin", "random":
+	default:
+		return nil, fmt.Errorf("CLICKHOUSE_CONN_OPEN_STRATEGY: unknown strategy %q (expected in_order, round_robin or random)",
+			cfg.ClickHouseConnOpenStrategy)
+	}
+
+	var err error
+	pool := &cfg.ClickHousePool
+	if pool.MaxOpenConns, err = getEnvInt("CLICKHOUSE_MAX_OPEN_CONNS", 0); err != nil {
+		return nil, err
+	}
+	if pool.MaxIdleConns, err = getEnvInt("CLICKHOUSE_MAX_IDLE_CONNS", 0); err != nil {
+		return nil, err
+	}
+	if pool.ConnMaxLifetime, err = getEnvDuration("CLICKHOUSE_CONN_MAX_LIFETIME", 0); err != nil {
+		return nil, err
+	}
+	if pool.DialTimeout, err = getEnvDuration("CLICKHOUSE_DIAL_TIMEOUT", 0); err != nil {
+		return nil, err
+	}
+	if pool.ReadTimeout, err = getEnvDuration("CLICKHOUSE_READ_TIMEOUT", 0); err != nil {
+		return nil, err
+	}
+	pool.Compression = getEnv("CLICKHOUSE_COMPRESSION", "")
+	retry := &cfg.ClickHouseRetry
+	if retry.MaxAttempts, err = getEnvInt("CLICKHOUSE_RETRY_MAX_ATTEMPTS", 3); err != nil {
+		return nil, err
+	}
+	if retry.InitialBackoff, err = getEnvDuration("CLICKHOUSE_RETRY_INITIAL_BACKOFF", 100*time.Millisecond); err != nil {
+		return nil, err
+	}
+	if retry.MaxBackoff, err = getEnvDuration("CLICKHOUSE_RETRY_MAX_BACKOFF", time.Second); err != nil {
+		return nil, err
+	}
+	if cfg.ClickHouseMigrateOnStart, err = getEnvBool("CLICKHOUSE_MIGRATE_ON_START", false); err != nil {
+		return nil, err
+	}
+	if cfg.ClickHouseLatestFastPath, err = getEnvBool("CLICKHOUSE_LATEST_FAST_PATH", false); err != nil {
+		return nil, err
+	}
+	if cfg.ClickHouseMaxExecutionTime, err = getEnvDuration("CLICKHOUSE_MAX_EXECUTION_TIME", 15*time.Second); err != nil {
+		return nil, err
+	}
+	// The setting is sent in whole seconds, and a sub-second value would truncate to 0, which
+	// ClickHouse reads as unlimited.
+	if cfg.ClickHouseMaxExecutionTime%time.Second != 0 {
+		return nil, fmt.Errorf("CLICKHOUSE_MAX_EXECUTION_TIME: must be 0 or a whole number of seconds, %s given", cfg.ClickHouseMaxExecutionTime)
+	}
+	if cfg.ClickHouseGeohashPrefilter, err = getEnvBool("CLICKHOUSE_GEOHASH_PREFILTER", false); err != nil {
+		return nil, err
+	}
+	if cfg.ClickHouseSlowQueryThreshold, err = getEnvDuration("CLICKHOUSE_SLOW_QUERY_THRESHOLD", time.Second); err != nil {
+		return nil, err
+	}
+	tls := &cfg.ClickHouseTLS
+	if tls.Enabled, err = getEnvBool("CLICKHOUSE_TLS", false); err != nil {
+		return nil, err
+	}
+	if tls.InsecureSkipVerify, err = getEnvBool("CLICKHOUSE_TLS_SKIP_VERIFY", false); err != nil {
+		return nil, err
 	}
+	tls.CAFile = getEnv("CLICKHOUSE_TLS_CA_FILE", "")
+	tls.CertFile = getEnv("CLICKHOUSE_TLS_CERT_FILE", "")
+	tls.KeyFile = getEnv("CLICKHOUSE_TLS_KEY_FILE", "")
+	tls.ServerName = getEnv("CLICKHOUSE_TLS_SERVER_NAME", "")
+	if (tls.CertFile == "") != (tls.KeyFile == "") {
+		return nil, fmt.Errorf("CLICKHOUSE_TLS_CERT_FILE and CLICKHOUSE_TLS_KEY_FILE must be set together")
+	}
+	if cfg.MaxConcurrentLookups, err = getEnvInt("MAX_CONCURRENT_LOOKUPS", 32); err != nil {
+		return nil, err
+	}
+	if cfg.InterpolationMaxGaps, err = getEnvDurationMap("INTERPOLATION_MAX_GAPS"); err != nil {
+		return nil, err
+	}
+	if cfg.MaxDataAges, err = getEnvDurationMap("MAX_DATA_AGES"); err != nil {
+		return nil, err
+	}
+	if cfg.MaxLoadLags, err = getEnvDurationMap("MAX_LOAD_LAGS"); err != nil {
+		return nil, err
+	}
+	if cfg.HedgeDelay, err = getEnvDuration("CLICKHOUSE_HEDGE_DELAY", 0); err != nil {
+		return nil, err
+	}
+	cfg.SourcePrecedence = getEnvList("SOURCE_PRECEDENCE")
+	if cfg.SourcePrecedenceLookback, err = getEnvDuration("SOURCE_PRECEDENCE_LOOKBACK", 6*time.Hour); err != nil {
+		return nil, err
+	}
+	retention := &cfg.Retention
+	if retention.DefaultMaxAge, err = getEnvDuration("RETENTION_MAX_AGE", 0); err != nil {
+		return nil, err
+	}
+	if retention.MaxAges, err = getEnvDurationMap("RETENTION_VARIABLE_MAX_AGES"); err != nil {
+		return nil, err
+	}
+	if retention.CompactWindow, err = getEnvDuration("RETENTION_COMPACT_WINDOW", 48*time.Hour); err != nil {
+		return nil, err
+	}
+	if cfg.DatasetCadences, err = getEnvDurationMap("DATASET_CADENCES"); err != nil {
+		return nil, err
+	}
+	for dataset, cadence := range cfg.DatasetCadences {
+		if cadence <= 0 || cadence%(24*time.Hour) != 0 {
+			return nil, fmt.Errorf("DATASET_CADENCES: %s: must be a positive whole number of days, %s given", dataset, cadence)
+		}
+	}
+	if cfg.FeatureFlags, err = getEnvPercentMap("FEATURE_FLAGS"); err != nil {
+		return nil, err
+	}
+	if cfg.LogLevel, err = getEnvLevel("LOG_LEVEL", slog.LevelInfo); err != nil {
+		return nil, err
+	}
+	if cfg.LogLevels, err = getEnvLevelMap("LOG_LEVELS"); err != nil {
+		return nil, err
+	}
+	demo := &cfg.Demo
+	if demo.Enabled, err = getEnvBool("DEMO_MODE", false); err != nil {
+		return nil, err
+	}
+	bbox, err := getEnvBBox("DEMO_BBOX", "52,13,53,14")
+	if err != nil {
+		return nil, err
+	}
+	demo.MinLat, demo.MinLon, demo.MaxLat, demo.MaxLon = bbox[0], bbox[1], bbox[2], bbox[3]
+	if demo.MaxHistory, err = getEnvDuration("DEMO_MAX_HISTORY", 72*time.Hour); err != nil {
+		return nil, err
+	}
+	if demo.RequestsPerMinute, err = getEnvInt("DEMO_RATE_LIMIT", 30); err != nil {
+		return nil, err
+	}
+	if demo.Burst, err = getEnvInt("DEMO_RATE_BURST", 10); err != nil {
+		return nil, err
+	}
+	gridCache := &cfg.GridCache
+	if gridCache.DefaultTTL, err = getEnvDuration("GRID_CACHE_TTL", 0); err != nil {
+		return nil, err
+	}
+	if gridCache.TTLs, err = getEnvDurationMap("GRID_CACHE_VARIABLE_TTLS"); err != nil {
+		return nil, err
+	}
+	if gridCache.MaxEntries, err = getEnvInt("GRID_CACHE_MAX_ENTRIES", 100_000); err != nil {
+		return nil, err
+	}
+	if pool.MaxOpenConns != 0 && pool.MaxIdleConns > pool.MaxOpenConns {
+		return nil, fmt.Errorf("CLICKHOUSE_MAX_IDLE_CONNS (%d) must not exceed CLICKHOUSE_MAX_OPEN_CONNS (%d)",
+			pool.MaxIdleConns, pool.MaxOpenConns)
+	}
+
+	return cfg, nil
 }
@@ -0,0 +1,291 @@
+package config
+
+import (
+	"log/slog"
+	"maps"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestLoad_ClickHousePoolDefaults(t *testing.T) {
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load returned error: %v", err)
+	}
+	if cfg.ClickHousePool != (ClickHousePool{}) {
+		t.Errorf("expected zero-value pool settings (driver defaults), got %+v", cfg.ClickHousePool)
+	}
+}
+
+func TestLoad_ClickHousePool(t *testing.T) {
+	t.Setenv("CLICKHOUSE_MAX_OPEN_CONNS", "20")
+	t.Setenv("CLICKHOUSE_MAX_IDLE_CONNS", "10")
+	t.Setenv("CLICKHOUSE_CONN_MAX_LIFETIME", "30m")
+	t.Setenv("CLICKHOUSE_DIAL_TIMEOUT", "2s")
+	t.Setenv("CLICKHOUSE_READ_TIMEOUT", "10s")
+	t.Setenv("CLICKHOUSE_COMPRESSION", "lz4")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load returned error: %v", err)
+	}
+
+	want := ClickHousePool{
+		MaxOpenConns:    20,
+		MaxIdleConns:    10,
+		ConnMaxLifetime: 30 * time.Minute,
+		DialTimeout:     2 * time.Second,
+		ReadTimeout:     10 * time.Second,
+		Compression:     "lz4",
+	}
+	if cfg.ClickHousePool != want {
+		t.Errorf("expected %+v, got %+v", want, cfg.ClickHousePool)
+	}
+}
+
+func TestLoad_ClickHouseRetryDefaults(t *testing.T) {
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load returned error: %v", err)
+	}
+
+	want := ClickHouseRetry{MaxAttempts: 3, InitialBackoff: 100 * time.Millisecond, MaxBackoff: time.Second}
+	if cfg.ClickHouseRetry != want {
+		t.Errorf("expected %+v, got %+v", want, cfg.ClickHouseRetry)
+	}
+}
+
+func TestLoad_Invalid(t *testing.T) {
+	tests := []struct {
+		name  string
+		key   string
+		value string
+	}{
+		{name: "non-numeric max open conns", key: "CLICKHOUSE_MAX_OPEN_CONNS", value: "many"},
+		{name: "negative max idle conns", key: "CLICKHOUSE_MAX_IDLE_CONNS", value: "-1"},
+		{name: "unitless lifetime", key: "CLICKHOUSE_CONN_MAX_LIFETIME", value: "30"},
+		{name: "negative dial timeout", key: "CLICKHOUSE_DIAL_TIMEOUT", value: "-1s"},
+		{name: "sub-second max execution time", key: "CLICKHOUSE_MAX_EXECUTION_TIME", value: "500ms"},
+		{name: "fractional max execution time", key: "CLICKHOUSE_MAX_EXECUTION_TIME", value: "1.5s"},
+		{name: "non-boolean migrate on start", key: "CLICKHOUSE_MIGRATE_ON_START", value: "sometimes"},
+		{name: "non-boolean tls", key: "CLICKHOUSE_TLS", value: "yes please"},
+		{name: "client cert without key", key: "CLICKHOUSE_TLS_CERT_FILE", value: "/etc/ssl/client.pem"},
+		{name: "non-numeric retry attempts", key: "CLICKHOUSE_RETRY_MAX_ATTEMPTS", value: "three"},
+		{name: "variable ttl without name", key: "GRID_CACHE_VARIABLE_TTLS", value: "=1h"},
+		{name: "variable ttl without unit", key: "GRID_CACHE_VARIABLE_TTLS", value: "pm2p5=60"},
+		{name: "interpolation gap without unit", key: "INTERPOLATION_MAX_GAPS", value: "pm2p5=1"},
+		{name: "max data age without name", key: "MAX_DATA_AGES", value: "=6h"},
+		{name: "max load lag without unit", key: "MAX_LOAD_LAGS", value: "pm2p5=26"},
+		{name: "hedge delay without unit", key: "CLICKHOUSE_HEDGE_DELAY", value: "50"},
+		{name: "negative precedence lookback", key: "SOURCE_PRECEDENCE_LOOKBACK", value: "-1h"},
+		{name: "retention age without unit", key: "RETENTION_VARIABLE_MAX_AGES", value: "pm10=30"},
+		{name: "cadence of part of a day", key: "DATASET_CADENCES", value: "cams=12h"},
+		{name: "zero cadence", key: "DATASET_CADENCES", value: "cams=0s"},
+		{name: "feature flag without value", key: "FEATURE_FLAGS", value: "interpolation"},
+		{name: "feature flag not a bool", key: "FEATURE_FLAGS", value: "interpolation=maybe"},
+		{name: "feature flag percent over 100", key: "FEATURE_FLAGS", value: "interpolation=101%"},
+		{name: "feature flag fractional percent", key: "FEATURE_FLAGS", value: "interpolation=2.5%"},
+		{name: "demo bbox with three values", key: "DEMO_BBOX", value: "52,13,53"},
+		{name: "demo bbox inverted", key: "DEMO_BBOX", value: "53,13,52,14"},
+		{name: "negative demo rate limit", key: "DEMO_RATE_LIMIT", value: "-1"},
+		{name: "unknown log level", key: "LOG_LEVEL", value: "verbose"},
+		{name: "component log level without value", key: "LOG_LEVELS", value: "clickhouse"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv(tt.key, tt.value)
+			if _, err := Load(); err == nil {
+				t.Errorf("expected error for %s=%q", tt.key, tt.value)
+			}
+		})
+	}
+}
+
+func TestLoad_IdleExceedsOpen(t *testing.T) {
+	t.Setenv("CLICKHOUSE_MAX_OPEN_CONNS", "5")
+	t.Setenv("CLICKHOUSE_MAX_IDLE_CONNS", "10")
+
+	if _, err := Load(); err == nil {
+		t.Error("expected error when idle conns exceed open conns")
+	}
+}
+
+func TestLoad_ClickHouseAddrs(t *testing.T) {
+	t.Run("single host fallback", func(t *testing.T) {
+		t.Setenv("CLICKHOUSE_HOST", "ch")
+		t.Setenv("CLICKHOUSE_NATIVE_PORT", "9000")
+
+		cfg, err := Load()
+		if err != nil {
+			t.Fatalf("Load returned error: %v", err)
+		}
+		if !slices.Equal(cfg.ClickHouseAddrs, []string{"ch:9000"}) {
+			t.Errorf("expected [ch:9000], got %v", cfg.ClickHouseAddrs)
+		}
+		if cfg.ClickHouseConnOpenStrategy != "in_order" {
+			t.Errorf("expected in_order strategy by default, got %q", cfg.ClickHouseConnOpenStrategy)
+		}
+	})
+
+	t.Run("host list", func(t *testing.T) {
+		t.Setenv("CLICKHOUSE_HOSTS", "ch-1:9000, ch-2:9000,,")
+		t.Setenv("CLICKHOUSE_CONN_OPEN_STRATEGY", "round_robin")
+
+		cfg, err := Load()
+		if err != nil {
+			t.Fatalf("Load returned error: %v", err)
+		}
+		if !slices.Equal(cfg.ClickHouseAddrs, []string{"ch-1:9000", "ch-2:9000"}) {
+			t.Errorf("expected [ch-1:9000 ch-2:9000], got %v", cfg.ClickHouseAddrs)
+		}
+		if cfg.ClickHouseConnOpenStrategy != "round_robin" {
+			t.Errorf("expected round_robin strategy, got %q", cfg.ClickHouseConnOpenStrategy)
+		}
+	})
+
+	t.Run("http protocol uses http port", func(t *testing.T) {
+		t.Setenv("CLICKHOUSE_HOST", "ch")
+		t.Setenv("CLICKHOUSE_PROTOCOL", "http")
+
+		cfg, err := Load()
+		if err != nil {
+			t.Fatalf("Load returned error: %v", err)
+		}
+		if !slices.Equal(cfg.ClickHouseAddrs, []string{"ch:8123"}) {
+			t.Errorf("expected [ch:8123], got %v", cfg.ClickHouseAddrs)
+		}
+	})
+
+	t.Run("unknown protocol", func(t *testing.T) {
+		t.Setenv("CLICKHOUSE_PROTOCOL", "grpc")
+		if _, err := Load(); err == nil {
+			t.Error("expected error for unknown protocol")
+		}
+	})
+
+	t.Run("unknown strategy", func(t *testing.T) {
+		t.Setenv("CLICKHOUSE_CONN_OPEN_STRATEGY", "fastest")
+		if _, err := Load(); err == nil {
+			t.Error("expected error for unknown strategy")
+		}
+	})
+}
+
+func TestLoad_GridCache(t *testing.T) {
+	t.Setenv("GRID_CACHE_TTL", "15m")
+	t.Setenv("GRID_CACHE_VARIABLE_TTLS", "pm2p5=1h, no2 = 30m")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load returned error: %v", err)
+	}
+
+	if cfg.GridCache.DefaultTTL != 15*time.Minute {
+		t.Errorf("expected default TTL 15m, got %s", cfg.GridCache.DefaultTTL)
+	}
+	want := map[string]time.Duration{"pm2p5": time.Hour, "no2": 30 * time.Minute}
+	if !maps.Equal(cfg.GridCache.TTLs, want) {
+		t.Errorf("expected TTLs %v, got %v", want, cfg.GridCache.TTLs)
+	}
+	if cfg.GridCache.MaxEntries != 100_000 {
+		t.Errorf("expected default max entries 100000, got %d", cfg.GridCache.MaxEntries)
+	}
+}
+
+func TestLoad_InterpolationMaxGaps(t *testing.T) {
+	t.Setenv("INTERPOLATION_MAX_GAPS", "pm2p5=1h,pm10=3h")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load returned error: %v", err)
+	}
+
+	want := map[string]time.Duration{"pm2p5": time.Hour, "pm10": 3 * time.Hour}
+	if !maps.Equal(cfg.InterpolationMaxGaps, want) {
+		t.Errorf("expected interpolation gaps %v, got %v", want, cfg.InterpolationMaxGaps)
+	}
+}
+
+func TestLoad_SourcePrecedence(t *testing.T) {
+	t.Setenv("SOURCE_PRECEDENCE", "cams-europe-air-quality-analysis, cams-europe-air-quality-forecast")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load returned error: %v", err)
+	}
+
+	want := []string{"cams-europe-air-quality-analysis", "cams-europe-air-quality-forecast"}
+	if !slices.Equal(cfg.SourcePrecedence, want) {
+		t.Errorf("expected precedence %v, got %v", want, cfg.SourcePrecedence)
+	}
+	if cfg.SourcePrecedenceLookback != 6*time.Hour {
+		t.Errorf("expected default lookback 6h, got %s", cfg.SourcePrecedenceLookback)
+	}
+}
+
+func TestLoad_Retention(t *testing.T) {
+	t.Setenv("RETENTION_MAX_AGE", "720h")
+	t.Setenv("RETENTION_VARIABLE_MAX_AGES", "pm10=168h,pm2p5=0s")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load returned error: %v", err)
+	}
+
+	want := Retention{
+		DefaultMaxAge: 720 * time.Hour,
+		MaxAges:       map[string]time.Duration{"pm10": 168 * time.Hour, "pm2p5": 0},
+		CompactWindow: 48 * time.Hour,
+	}
+	if cfg.Retention.DefaultMaxAge != want.DefaultMaxAge || !maps.Equal(cfg.Retention.MaxAges, want.MaxAges) ||
+		cfg.Retention.CompactWindow != want.CompactWindow {
+		t.Errorf("expected retention %+v, got %+v", want, cfg.Retention)
+	}
+}
+
+func TestLoad_FeatureFlags(t *testing.T) {
+	t.Setenv("FEATURE_FLAGS", "interpolation=false, source_precedence=25%, other=1")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load returned error: %v", err)
+	}
+	want := map[string]int{"interpolation": 0, "source_precedence": 25, "other": 100}
+	if !maps.Equal(cfg.FeatureFlags, want) {
+		t.Errorf("expected %v, got %v", want, cfg.FeatureFlags)
+	}
+}
+
+func TestLoad_Demo(t *testing.T) {
+	t.Setenv("DEMO_MODE", "true")
+	t.Setenv("DEMO_BBOX", "45.5, 5, 48, 10.5")
+	t.Setenv("DEMO_MAX_HISTORY", "24h")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load returned error: %v", err)
+	}
+	want := Demo{Enabled: true, MinLat: 45.5, MinLon: 5, MaxLat: 48, MaxLon: 10.5, MaxHistory: 24 * time.Hour, RequestsPerMinute: 30, Burst: 10}
+	if cfg.Demo != want {
+		t.Errorf("expected %+v, got %+v", want, cfg.Demo)
+	}
+}
+
+func TestLoad_LogLevels(t *testing.T) {
+	t.Setenv("LOG_LEVEL", "warn")
+	t.Setenv("LOG_LEVELS", "clickhouse=debug, api=INFO")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load returned error: %v", err)
+	}
+	if cfg.LogLevel != slog.LevelWarn {
+		t.Errorf("expected warn, got %s", cfg.LogLevel)
+	}
+	want := map[string]slog.Level{"clickhouse": slog.LevelDebug, "api": slog.LevelInfo}
+	if !maps.Equal(cfg.LogLevels, want) {
+		t.Errorf("expected %v, got %v", want, cfg.LogLevels)
+	}
+}
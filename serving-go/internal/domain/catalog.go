@@ -0,0 +1,211 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrCatalogEntryNotFound = errors.New("catalog entry not found")
+	ErrRawFileNotFound      = errors.New("raw file not found")
+)
+
+// RawFile is one ingested source file, as recorded in catalog.raw_files. Its ID is the
+// ingestion run id.
+type RawFile struct {
+	ID        uuid.UUID
+	Source    string
+	Dataset   string
+	Date      time.Time
+	S3Key     string
+	CreatedAt time.Time
+}
+
+// CatalogEntry is one (variable, timestamp) grid produced from a raw file; grid_data rows
+// reference it by catalog_id.
+type CatalogEntry struct {
+	ID        uuid.UUID
+	Variable  string
+	Unit      string
+	Timestamp time.Time
+	CreatedAt time.Time
+	RawFile   RawFile
+	// Rows counts the entry's rows in grid_data; zero means it isn't loaded (any more).
+	Rows uint64
+}
+
+// CatalogFilter narrows catalog listings. Zero fields don't filter.
+type CatalogFilter struct {
+	RawFileID uuid.UUID
+	Variable  string
+	Dataset   string
+	From      time.Time
+	To        time.Time
+	Limit     int
+}
+
+const (
+	DefaultCatalogLimit = 100
+	MaxCatalogLimit     = 1000
+)
+
+type CatalogRetriever interface {
+	GetCatalogEntry(ctx context.Context, id uuid.UUID) (*CatalogEntry, error)
+	// ListCatalogEntries returns matching entries, newest timestamp first.
+	ListCatalogEntries(ctx context.Context, filter CatalogFilter) ([]CatalogEntry, error)
+	GetRawFile(ctx context.Context, id uuid.UUID) (*RawFile, error)
+}
+
+// Run is one ingestion run's raw file and the catalog entries loaded from it.
+type Run struct {
+	RawFile RawFile
+	Entries []CatalogEntry
+}
+
+// GridInventory reports what grid_data holds, as opposed to what the catalog says was produced.
+type GridInventory interface {
+	CountRows(ctx context.Context, entries []CatalogEntry) (map[uuid.UUID]uint64, error)
+	GetCoverage(ctx context.Context, variables ...string) ([]Coverage, error)
+	GetQuality(ctx context.Context, entry CatalogEntry) (*QualityStats, error)
+}
+
+// CatalogService joins catalog entries with their load status in ClickHouse.
+type CatalogService struct {
+	catalog   CatalogRetriever
+	inventory GridInventory
+	contracts map[string]VariableContract
+}
+
+func NewCatalogService(catalog CatalogRetriever, inventory GridInventory) *CatalogService {
+	return &CatalogService{catalog: catalog, inventory: inventory, contracts: DefaultContracts}
+}
+
+// GetEntry fails with ErrCatalogEntryNotFound for unknown ids.
+func (s *CatalogService) GetEntry(ctx context.Context, id uuid.UUID) (*CatalogEntry, error) {
+	entry, err := s.catalog.GetCatalogEntry(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := s.inventory.CountRows(ctx, []CatalogEntry{*entry})
+	if err != nil {
+		return nil, fmt.Errorf("counting rows of catalog entry %s: %w", id, err)
+	}
+	entry.Rows = rows[id]
+
+	return entry, nil
+}
+
+// ListEntries applies DefaultCatalogLimit to a zero limit and fails with *ErrInvalidRequest
+// for limits outside [1, MaxCatalogLimit] or an inverted time range.
+func (s *CatalogService) ListEntries(ctx context.Context, filter CatalogFilter) ([]CatalogEntry, error) {
+	if filter.Limit == 0 {
+		filter.Limit = DefaultCatalogLimit
+	}
+	if filter.Limit < 1 || filter.Limit > MaxCatalogLimit {
+		return nil, &ErrInvalidRequest{
+			Field:   "limit",
+			Message: fmt.Sprintf("must be between 1 and %d, %d given", MaxCatalogLimit, filter.Limit),
+		}
+	}
+	if !filter.From.IsZero() && !filter.To.IsZero() && filter.From.After(filter.To) {
+		return nil, &ErrInvalidRequest{Field: "from", Message: "must not be after to"}
+	}
+	if filter.Variable != "" {
+		filter.Variable = CanonicalVariable(filter.Variable)
+	}
+
+	entries, err := s.catalog.ListCatalogEntries(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	if len(entries) == 0 {
+		return entries, nil
+	}
+	rows, err := s.inventory.CountRows(ctx, entries)
+	if err != nil {
+		return nil, fmt.Errorf("counting rows of %d catalog entries: %w", len(entries), err)
+	}
+	for i := range entries {
+		entries[i].Rows = rows[entries[i].ID]
+	}
+
+	return entries, nil
+}
+
+// GetRun traces an ingestion run to the catalog entries it produced and their rows, listing
+// at most MaxCatalogLimit entries. It fails with ErrRawFileNotFound for unknown run ids.
+func (s *CatalogService) GetRun(ctx context.Context, runID uuid.UUID) (*Run, error) {
+	rawFile, err := s.catalog.GetRawFile(ctx, runID)
+	if err != nil {
+		return nil, err
+	}
+	entries, err := s.ListEntries(ctx, CatalogFilter{RawFileID: runID, Limit: MaxCatalogLimit})
+	if err != nil {
+		return nil, err
+	}
+
+	return &Run{RawFile: *rawFile, Entries: entries}, nil
+}
+
+// GetQuality computes value statistics and anomaly flags for one loaded entry, including
+// DefaultContracts violations. It fails with ErrCatalogEntryNotFound for unknown ids.
+func (s *CatalogService) GetQuality(ctx context.Context, id uuid.UUID) (*Quality, error) {
+	entry, err := s.catalog.GetCatalogEntry(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	stats, err := s.inventory.GetQuality(ctx, *entry)
+	if err != nil {
+		return nil, fmt.Errorf("quality of catalog entry %s: %w", id, err)
+	}
+
+	flags := append(stats.flags(), contractFlags(s.contracts, entry, *stats)...)
+	missing, err := s.missingTimesteps(ctx, entry)
+	if err != nil {
+		return nil, fmt.Errorf("timesteps around catalog entry %s: %w", id, err)
+	}
+	if missing {
+		flags = append(flags, QualityMissingTimesteps)
+	}
+	return &Quality{QualityStats: *stats, Flags: flags}, nil
+}
+
+// missingTimesteps reports whether the entry's run has timestamps for its variable on a
+// side of the entry but not the one a contract step away, i.e. the catalog skips a step
+// next to this entry. The first and last timestamps of a run only need their inner neighbour.
+func (s *CatalogService) missingTimesteps(ctx context.Context, entry *CatalogEntry) (bool, error) {
+	contract, ok := s.contracts[entry.Variable]
+	if !ok || contract.Step <= 0 || entry.RawFile.ID == uuid.Nil {
+		return false, nil
+	}
+	siblings, err := s.catalog.ListCatalogEntries(ctx, CatalogFilter{
+		RawFileID: entry.RawFile.ID,
+		Variable:  entry.Variable,
+		Limit:     MaxCatalogLimit,
+	})
+	if err != nil {
+		return false, err
+	}
+
+	var before, after, previous, next bool
+	for _, sibling := range siblings {
+		switch {
+		case sibling.Timestamp.Before(entry.Timestamp):
+			before = true
+			previous = previous || sibling.Timestamp.Equal(entry.Timestamp.Add(-contract.Step))
+		case sibling.Timestamp.After(entry.Timestamp):
+			after = true
+			next = next || sibling.Timestamp.Equal(entry.Timestamp.Add(contract.Step))
+		}
+	}
+	return before && !previous || after && !next, nil
+}
+
+// GetCoverage reports what grid_data holds per variable; no variables means all of them.
+func (s *CatalogService) GetCoverage(ctx context.Context, variables ...string) ([]Coverage, error) {
+	return s.inventory.GetCoverage(ctx, canonicalVariables(variables)...)
+}
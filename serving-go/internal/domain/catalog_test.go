@@ -0,0 +1,245 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type mockCatalogRetriever struct {
+	entries  []CatalogEntry
+	rawFiles []RawFile
+	filter   CatalogFilter
+}
+
+func (m *mockCatalogRetriever) GetCatalogEntry(_ context.Context, id uuid.UUID) (*CatalogEntry, error) {
+	for _, entry := range m.entries {
+		if entry.ID == id {
+			return &entry, nil
+		}
+	}
+	return nil, ErrCatalogEntryNotFound
+}
+
+func (m *mockCatalogRetriever) ListCatalogEntries(_ context.Context, filter CatalogFilter) ([]CatalogEntry, error) {
+	m.filter = filter
+	return m.entries, nil
+}
+
+func (m *mockCatalogRetriever) GetRawFile(_ context.Context, id uuid.UUID) (*RawFile, error) {
+	for _, rawFile := range m.rawFiles {
+		if rawFile.ID == id {
+			return &rawFile, nil
+		}
+	}
+	return nil, ErrRawFileNotFound
+}
+
+type mockGridInventory struct {
+	rows      map[uuid.UUID]uint64
+	quality   *QualityStats
+	coverage  []Coverage
+	variables []string
+}
+
+func (m *mockGridInventory) CountRows(_ context.Context, _ []CatalogEntry) (map[uuid.UUID]uint64, error) {
+	return m.rows, nil
+}
+
+func (m *mockGridInventory) GetCoverage(_ context.Context, variables ...string) ([]Coverage, error) {
+	m.variables = variables
+	return m.coverage, nil
+}
+
+func (m *mockGridInventory) GetQuality(_ context.Context, _ CatalogEntry) (*QualityStats, error) {
+	return m.quality, nil
+}
+
+func TestCatalogService_ListEntries(t *testing.T) {
+	loaded, pending := uuid.New(), uuid.New()
+	catalog := &mockCatalogRetriever{entries: []CatalogEntry{{ID: loaded}, {ID: pending}}}
+	service := NewCatalogService(catalog, &mockGridInventory{rows: map[uuid.UUID]uint64{loaded: 12}})
+
+	entries, err := service.ListEntries(t.Context(), CatalogFilter{Variable: "PM2.5"})
+	if err != nil {
+		t.Fatalf("ListEntries returned error: %v", err)
+	}
+	if entries[0].Rows != 12 || entries[1].Rows != 0 {
+		t.Errorf("expected row counts 12 and 0, got %d and %d", entries[0].Rows, entries[1].Rows)
+	}
+	if catalog.filter.Variable != "pm2p5" || catalog.filter.Limit != DefaultCatalogLimit {
+		t.Errorf("expected canonical variable and default limit, got %+v", catalog.filter)
+	}
+
+	_, err = service.ListEntries(t.Context(), CatalogFilter{Limit: MaxCatalogLimit + 1})
+	if invalid, ok := errors.AsType[*ErrInvalidRequest](err); !ok || invalid.Field != "limit" {
+		t.Errorf("expected ErrInvalidRequest on limit, got %v", err)
+	}
+}
+
+func TestCatalogService_GetEntry(t *testing.T) {
+	id := uuid.New()
+	service := NewCatalogService(
+		&mockCatalogRetriever{entries: []CatalogEntry{{ID: id, Variable: "pm10"}}},
+		&mockGridInventory{rows: map[uuid.UUID]uint64{id: 3}},
+	)
+
+	entry, err := service.GetEntry(t.Context(), id)
+	if err != nil {
+		t.Fatalf("GetEntry returned error: %v", err)
+	}
+	if entry.Rows != 3 {
+		t.Errorf("expected 3 rows, got %d", entry.Rows)
+	}
+	if _, err := service.GetEntry(t.Context(), uuid.New()); !errors.Is(err, ErrCatalogEntryNotFound) {
+		t.Errorf("expected ErrCatalogEntryNotFound, got %v", err)
+	}
+}
+
+func TestCatalogService_GetCoverage_ResolvesAliases(t *testing.T) {
+	inventory := &mockGridInventory{}
+	service := NewCatalogService(&mockCatalogRetriever{}, inventory)
+
+	if _, err := service.GetCoverage(t.Context(), "pm25", "t2m"); err != nil {
+		t.Fatalf("GetCoverage returned error: %v", err)
+	}
+	if len(inventory.variables) != 2 || inventory.variables[0] != "pm2p5" || inventory.variables[1] != "temperature" {
+		t.Errorf("expected canonical names, got %v", inventory.variables)
+	}
+}
+
+func TestCatalogService_GetQuality(t *testing.T) {
+	id := uuid.New()
+	tests := []struct {
+		name  string
+		stats QualityStats
+		want  []string
+	}{
+		{name: "healthy", stats: QualityStats{Rows: 4, ExpectedRows: 4, Min: 1, Max: 5}, want: []string{}},
+		{name: "not loaded", stats: QualityStats{}, want: []string{QualityNotLoaded}},
+		{name: "all zero", stats: QualityStats{Rows: 4, ExpectedRows: 4, ZeroRows: 4}, want: []string{QualityAllZero}},
+		{name: "all nan", stats: QualityStats{Rows: 4, ExpectedRows: 4, NaNRows: 4}, want: []string{QualityAllNaN}},
+		{name: "constant and holes", stats: QualityStats{Rows: 3, ExpectedRows: 4, Min: 2, Max: 2}, want: []string{QualityConstant, QualityIncompleteGrid}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := NewCatalogService(&mockCatalogRetriever{entries: []CatalogEntry{{ID: id}}}, &mockGridInventory{quality: &tt.stats})
+			quality, err := service.GetQuality(t.Context(), id)
+			if err != nil {
+				t.Fatalf("GetQuality returned error: %v", err)
+			}
+			if !slices.Equal(quality.Flags, tt.want) {
+				t.Errorf("expected flags %v, got %v", tt.want, quality.Flags)
+			}
+		})
+	}
+
+	service := NewCatalogService(&mockCatalogRetriever{}, &mockGridInventory{})
+	if _, err := service.GetQuality(t.Context(), id); !errors.Is(err, ErrCatalogEntryNotFound) {
+		t.Errorf("expected ErrCatalogEntryNotFound, got %v", err)
+	}
+}
+
+func TestCatalogService_GetQuality_Contracts(t *testing.T) {
+	id := uuid.New()
+	healthy := QualityStats{Rows: 4, ExpectedRows: 4, Min: 1, Max: 5}
+	tests := []struct {
+		name  string
+		entry CatalogEntry
+		stats QualityStats
+		want  []string
+	}{
+		{
+			name:  "matches contract",
+			entry: CatalogEntry{ID: id, Variable: "pm2p5", Unit: "µg/m³"},
+			stats: QualityStats{Rows: 4, ExpectedRows: 4, Min: 1, Max: 5, Units: []string{"µg/m³"}, LatStep: 0.1, LonStep: 0.1},
+			want:  []string{},
+		},
+		{
+			name:  "catalog unit off contract",
+			entry: CatalogEntry{ID: id, Variable: "temperature", Unit: "K"},
+			stats: healthy,
+			want:  []string{QualityUnitMismatch},
+		},
+		{
+			name:  "grid unit off catalog",
+			entry: CatalogEntry{ID: id, Variable: "custom", Unit: "ppb"},
+			stats: QualityStats{Rows: 4, ExpectedRows: 4, Min: 1, Max: 5, Units: []string{"ppb", "ppm"}},
+			want:  []string{QualityUnitMismatch},
+		},
+		{
+			name:  "coarser grid",
+			entry: CatalogEntry{ID: id, Variable: "pm10", Unit: "µg/m³"},
+			stats: QualityStats{Rows: 4, ExpectedRows: 4, Min: 1, Max: 5, LatStep: 0.1, LonStep: 0.25},
+			want:  []string{QualityResolutionMismatch},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := NewCatalogService(&mockCatalogRetriever{entries: []CatalogEntry{tt.entry}}, &mockGridInventory{quality: &tt.stats})
+			quality, err := service.GetQuality(t.Context(), id)
+			if err != nil {
+				t.Fatalf("GetQuality returned error: %v", err)
+			}
+			if !slices.Equal(quality.Flags, tt.want) {
+				t.Errorf("expected flags %v, got %v", tt.want, quality.Flags)
+			}
+		})
+	}
+}
+
+func TestCatalogService_GetQuality_MissingTimesteps(t *testing.T) {
+	run := RawFile{ID: uuid.New()}
+	start := time.Date(2025, 3, 11, 0, 0, 0, 0, time.UTC)
+	var entries []CatalogEntry
+	for _, hour := range []int{0, 1, 3} {
+		entries = append(entries, CatalogEntry{
+			ID: uuid.New(), Variable: "pm2p5", Unit: "µg/m³", Timestamp: start.Add(time.Duration(hour) * time.Hour), RawFile: run,
+		})
+	}
+	catalog := &mockCatalogRetriever{entries: entries}
+	service := NewCatalogService(catalog, &mockGridInventory{quality: &QualityStats{Rows: 4, ExpectedRows: 4, Min: 1, Max: 5}})
+
+	for i, want := range [][]string{{}, {QualityMissingTimesteps}, {QualityMissingTimesteps}} {
+		quality, err := service.GetQuality(t.Context(), entries[i].ID)
+		if err != nil {
+			t.Fatalf("GetQuality returned error: %v", err)
+		}
+		if !slices.Equal(quality.Flags, want) {
+			t.Errorf("entry at %s: expected flags %v, got %v", entries[i].Timestamp.Format(time.Kitchen), want, quality.Flags)
+		}
+	}
+	if catalog.filter.RawFileID != run.ID || catalog.filter.Variable != "pm2p5" {
+		t.Errorf("expected the entry's run and variable to be listed, got %+v", catalog.filter)
+	}
+}
+
+func TestCatalogService_GetRun(t *testing.T) {
+	runID, entryID := uuid.New(), uuid.New()
+	catalog := &mockCatalogRetriever{
+		entries:  []CatalogEntry{{ID: entryID, RawFile: RawFile{ID: runID}}},
+		rawFiles: []RawFile{{ID: runID, S3Key: "ads/cams/2025-03-11/run.grib"}},
+	}
+	service := NewCatalogService(catalog, &mockGridInventory{rows: map[uuid.UUID]uint64{entryID: 7}})
+
+	run, err := service.GetRun(t.Context(), runID)
+	if err != nil {
+		t.Fatalf("GetRun returned error: %v", err)
+	}
+	if run.RawFile.S3Key != "ads/cams/2025-03-11/run.grib" || len(run.Entries) != 1 || run.Entries[0].Rows != 7 {
+		t.Errorf("unexpected run %+v", run)
+	}
+	if catalog.filter.RawFileID != runID || catalog.filter.Limit != MaxCatalogLimit {
+		t.Errorf("expected entries filtered by run id at the max limit, got %+v", catalog.filter)
+	}
+
+	if _, err := service.GetRun(t.Context(), uuid.New()); !errors.Is(err, ErrRawFileNotFound) {
+		t.Errorf("expected ErrRawFileNotFound, got %v", err)
+	}
+}
@@ -0,0 +1,99 @@
+package domain
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+// VariableContract declares what the serving layer expects the loader to write for a
+// stored variable, so mislabeled loads fail instead of reaching API clients.
+type VariableContract struct {
+	Unit string
+	// Resolution is the expected grid spacing in degrees; zero skips the check.
+	Resolution float32
+	// Step is the expected interval between a run's timestamps; zero skips the check.
+	Step time.Duration
+}
+
+// DefaultContracts match what pipeline-python loads: hourly CAMS Europe particulates on a
+// 0.1° grid, converted to µg/m³, and 3-hourly IFS surface fields on a 0.25° grid, converted
+// to °C and %.
+var DefaultContracts = map[string]VariableContract{
+	"pm2p5":       {Unit: unitMicrogramsPerCubicMetre, Resolution: 0.1, Step: time.Hour},
+	"pm10":        {Unit: unitMicrogramsPerCubicMetre, Resolution: 0.1, Step: time.Hour},
+	"temperature": {Unit: "°C", Resolution: 0.25, Step: 3 * time.Hour},
+	"dewpoint":    {Unit: "°C", Resolution: 0.25, Step: 3 * time.Hour},
+	"humidity":    {Unit: "%", Resolution: 0.25, Step: 3 * time.Hour},
+}
+
+// resolutionTolerance absorbs Float32 rounding of grid coordinates.
+const resolutionTolerance = 1e-3
+
+// ErrContractViolation means stored data disagrees with its variable's contract.
+type ErrContractViolation struct {
+	Variable string
+	Field    string
+	Want     string
+	Got      string
+}
+
+func (e *ErrContractViolation) Error() string {
+	return fmt.Sprintf("variable %q violates its contract: %s is %q, expected %q", e.Variable, e.Field, e.Got, e.Want)
+}
+
+// WithContracts fails requests whose stored samples carry a unit other than their
+// variable's contract declares. Variables without a contract are served as stored.
+func WithContracts(contracts map[string]VariableContract) ServiceOption {
+	return func(s *Service) {
+		s.contracts = contracts
+	}
+}
+
+// checkContracts returns the first unit violation among samples.
+func (s *Service) checkContracts(samples map[string]*GridSample) error {
+	for variable, sample := range samples {
+		if sample == nil {
+			continue
+		}
+		if err := s.checkUnit(variable, sample.Unit); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *Service) checkUnit(variable, unit string) error {
+	if contract, ok := s.contracts[variable]; ok && unit != contract.Unit {
+		return &ErrContractViolation{Variable: variable, Field: "unit", Want: contract.Unit, Got: unit}
+	}
+	return nil
+}
+
+// contractFlags checks a catalog entry's unit and its measured grid spacing against the
+// entry's contract. Without a contract, only disagreement between catalog and grid_data
+// units is flagged.
+func contractFlags(contracts map[string]VariableContract, entry *CatalogEntry, stats QualityStats) []string {
+	var flags []string
+	contract, ok := contracts[entry.Variable]
+	unitMismatch := ok && entry.Unit != contract.Unit
+	for _, unit := range stats.Units {
+		if unit != entry.Unit || ok && unit != contract.Unit {
+			unitMismatch = true
+		}
+	}
+	if unitMismatch {
+		flags = append(flags, QualityUnitMismatch)
+	}
+	if ok && contract.Resolution > 0 &&
+		(offResolution(stats.LatStep, contract.Resolution) || offResolution(stats.LonStep, contract.Resolution)) {
+		flags = append(flags, QualityResolutionMismatch)
+	}
+	return flags
+}
+
+// offResolution reports whether a measured step differs from want; zero steps (a single
+// row or column) can't be measured and pass.
+func offResolution(step, want float32) bool {
+	return step != 0 && math.Abs(float64(step-want)) > resolutionTolerance
+}
@@ -0,0 +1,60 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestService_Contracts(t *testing.T) {
+	requested := time.Date(2026, 3, 11, 12, 0, 0, 0, time.UTC)
+	grid := &mockGridRetriever{samples: map[string]*GridSample{
+		"pm2p5":       {Value: 10, Unit: "µg/m³", Timestamp: requested},
+		"temperature": {Value: 285, Unit: "K", Timestamp: requested},
+	}}
+	lineage := &mockLineageRetriever{lineages: map[uuid.UUID]*Lineage{{}: {Source: "ads"}}}
+	service := NewService(grid, lineage, WithContracts(DefaultContracts))
+
+	t.Run("serves matching units", func(t *testing.T) {
+		if _, err := service.GetVariables(t.Context(), requested, 52.5, 13.4, []string{"pm2p5"}); err != nil {
+			t.Errorf("expected pm2p5 to be served, got %v", err)
+		}
+	})
+
+	t.Run("fails on mislabeled unit", func(t *testing.T) {
+		_, err := service.GetVariables(t.Context(), requested, 52.5, 13.4, []string{"pm2p5", "t2m"})
+		violation, ok := errors.AsType[*ErrContractViolation](err)
+		if !ok {
+			t.Fatalf("expected ErrContractViolation, got %v", err)
+		}
+		if violation.Variable != "temperature" || violation.Field != "unit" || violation.Want != "°C" || violation.Got != "K" {
+			t.Errorf("unexpected violation %+v", violation)
+		}
+	})
+
+	t.Run("without contracts serves as stored", func(t *testing.T) {
+		service := NewService(grid, lineage)
+		if _, err := service.GetVariables(t.Context(), requested, 52.5, 13.4, []string{"temperature"}); err != nil {
+			t.Errorf("expected temperature to be served, got %v", err)
+		}
+	})
+}
+
+func TestOffResolution(t *testing.T) {
+	tests := []struct {
+		step float32
+		want bool
+	}{
+		{step: 0, want: false},
+		{step: 0.1, want: false},
+		{step: 0.1004, want: false},
+		{step: 0.25, want: true},
+	}
+	for _, tt := range tests {
+		if got := offResolution(tt.step, 0.1); got != tt.want {
+			t.Errorf("offResolution(%v, 0.1) = %v, want %v", tt.step, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,174 @@
+package domain
+
+import (
+	"fmt"
+	"slices"
+	"time"
+)
+
+// DerivedVariable is computed at request time from other variables at the same point.
+// Inputs are canonical names and may themselves be derived.
+type DerivedVariable struct {
+	Inputs []string
+	// Unit of the result; empty means the unit of the first input.
+	Unit string
+	// Compute receives one sample per input, in Inputs order, and reports false when the
+	// inputs admit no value (e.g. division by zero or an unexpected unit).
+	Compute func(inputs []*GridSample) (float32, bool)
+}
+
+const unitMicrogramsPerCubicMetre = "µg/m³"
+
+// DefaultDerivedVariables are served by every Service.
+var DefaultDerivedVariables = map[string]DerivedVariable{
+	"pm_coarse": {
+		Inputs:  []string{"pm10", "pm2p5"},
+		Compute: difference,
+	},
+	"pm2p5_pm10_ratio": {
+		Inputs:  []string{"pm2p5", "pm10"},
+		Unit:    "1",
+		Compute: ratio,
+	},
+	"pm_coarse_fraction": {
+		Inputs:  []string{"pm_coarse", "pm10"},
+		Unit:    "1",
+		Compute: ratio,
+	},
+	// eaqi_pm is the European Air Quality Index (1 good … 6 extremely poor) over particulates only.
+	"eaqi_pm": {
+		Inputs:  []string{"pm2p5", "pm10"},
+		Unit:    "index",
+		Compute: eaqiPM,
+	},
+}
+
+func sameUnit(inputs []*GridSample) bool {
+	for _, input := range inputs[1:] {
+		if input.Unit != inputs[0].Unit {
+			return false
+		}
+	}
+	return true
+}
+
+func difference(inputs []*GridSample) (float32, bool) {
+	if !sameUnit(inputs) {
+		return 0, false
+	}
+	return inputs[0].Value - inputs[1].Value, true
+}
+
+func ratio(inputs []*GridSample) (float32, bool) {
+	if !sameUnit(inputs) || inputs[1].Value == 0 {
+		return 0, false
+	}
+	return inputs[0].Value / inputs[1].Value, true
+}
+
+// EEA upper band limits in µg/m³; values above the last limit are band 6.
+var (
+	eaqiPM2p5Limits = []float32{10, 20, 25, 50, 75}
+	eaqiPM10Limits  = []float32{20, 40, 50, 100, 150}
+)
+
+func eaqiBand(value float32, limits []float32) int {
+	for i, limit := range limits {
+		if value <= limit {
+			return i + 1
+		}
+	}
+	return len(limits) + 1
+}
+
+func eaqiPM(inputs []*GridSample) (float32, bool) {
+	pm2p5, pm10 := inputs[0], inputs[1]
+	if pm2p5.Unit != unitMicrogramsPerCubicMetre || pm10.Unit != unitMicrogramsPerCubicMetre {
+		return 0, false
+	}
+	return float32(max(eaqiBand(pm2p5.Value, eaqiPM2p5Limits), eaqiBand(pm10.Value, eaqiPM10Limits))), true
+}
+
+// baseVariables expands derived variables into the stored variables they depend on,
+// keeping first-seen order and failing on cyclic definitions.
+func baseVariables(derived map[string]DerivedVariable, vars []string) ([]string, error) {
+	var base []string
+	var expand func(variable string, path []string) error
+	expand = func(variable string, path []string) error {
+		if slices.Contains(path, variable) {
+			return fmt.Errorf("derived variable %q depends on itself via %q", variable, path)
+		}
+		definition, ok := derived[variable]
+		if !ok {
+			if !slices.Contains(base, variable) {
+				base = append(base, variable)
+			}
+			return nil
+		}
+		for _, input := range definition.Inputs {
+			if err := expand(input, append(path, variable)); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	for _, variable := range vars {
+		if err := expand(variable, nil); err != nil {
+			return nil, err
+		}
+	}
+	return base, nil
+}
+
+// derive adds samples for the derived variables among vars whose inputs are all available.
+// A derived sample takes the position, catalog id (and so lineage) of its first input and
+// the oldest input timestamp.
+func derive(derived map[string]DerivedVariable, samples map[string]*GridSample, vars []string) {
+	var resolve func(variable string) *GridSample
+	resolve = func(variable string) *GridSample {
+		if sample, ok := samples[variable]; ok {
+			return sample
+		}
+		definition, ok := derived[variable]
+		if !ok {
+			return nil
+		}
+
+		inputs := make([]*GridSample, len(definition.Inputs))
+		for i, input := range definition.Inputs {
+			if inputs[i] = resolve(input); inputs[i] == nil {
+				samples[variable] = nil
+				return nil
+			}
+		}
+		value, ok := definition.Compute(inputs)
+		if !ok {
+			samples[variable] = nil
+			return nil
+		}
+
+		sample := *inputs[0]
+		sample.Value = value
+		sample.InterpolatedTo = time.Time{}
+		if definition.Unit != "" {
+			sample.Unit = definition.Unit
+		}
+		for _, input := range inputs[1:] {
+			sample.Timestamp = minTime(sample.Timestamp, input.Timestamp)
+		}
+		samples[variable] = &sample
+		return &sample
+	}
+
+	for _, variable := range vars {
+		resolve(variable)
+	}
+}
+
+func minTime(a, b time.Time) time.Time {
+	if b.Before(a) {
+		return b
+	}
+	return a
+}
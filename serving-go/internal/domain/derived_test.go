@@ -0,0 +1,103 @@
+package domain
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestBaseVariables(t *testing.T) {
+	base, err := baseVariables(DefaultDerivedVariables, []string{"pm_coarse_fraction", "no2", "pm2p5"})
+	if err != nil {
+		t.Fatalf("baseVariables returned error: %v", err)
+	}
+	if want := []string{"pm10", "pm2p5", "no2"}; !slices.Equal(base, want) {
+		t.Errorf("expected %v, got %v", want, base)
+	}
+}
+
+func TestBaseVariables_Cycle(t *testing.T) {
+	derived := map[string]DerivedVariable{
+		"a": {Inputs: []string{"b"}},
+		"b": {Inputs: []string{"pm10", "a"}},
+	}
+	if _, err := baseVariables(derived, []string{"a"}); err == nil {
+		t.Error("expected error for cyclic definition")
+	}
+}
+
+func TestDefaultDerivedVariables_Acyclic(t *testing.T) {
+	for name := range DefaultDerivedVariables {
+		if _, err := baseVariables(DefaultDerivedVariables, []string{name}); err != nil {
+			t.Errorf("%s: %v", name, err)
+		}
+	}
+}
+
+func TestDerive(t *testing.T) {
+	early := time.Date(2026, 2, 27, 3, 0, 0, 0, time.UTC)
+	late := early.Add(time.Hour)
+	samples := map[string]*GridSample{
+		"pm2p5": {Value: 15, Unit: unitMicrogramsPerCubicMetre, Lat: 52.5, Timestamp: late},
+		"pm10":  {Value: 30, Unit: unitMicrogramsPerCubicMetre, Lat: 52.4, Timestamp: early},
+	}
+
+	derive(DefaultDerivedVariables, samples, []string{"pm_coarse_fraction", "pm2p5_pm10_ratio", "eaqi_pm"})
+
+	tests := []struct {
+		variable string
+		value    float32
+		unit     string
+	}{
+		{variable: "pm_coarse", value: 15, unit: unitMicrogramsPerCubicMetre},
+		{variable: "pm_coarse_fraction", value: 0.5, unit: "1"},
+		{variable: "pm2p5_pm10_ratio", value: 0.5, unit: "1"},
+		{variable: "eaqi_pm", value: 2, unit: "index"},
+	}
+	for _, tt := range tests {
+		sample := samples[tt.variable]
+		if sample == nil {
+			t.Errorf("%s: expected a derived sample", tt.variable)
+			continue
+		}
+		if sample.Value != tt.value || sample.Unit != tt.unit {
+			t.Errorf("%s: expected %v %s, got %v %s", tt.variable, tt.value, tt.unit, sample.Value, sample.Unit)
+		}
+	}
+	if got := samples["pm2p5_pm10_ratio"]; got.Lat != 52.5 || !got.Timestamp.Equal(early) {
+		t.Errorf("expected first input's position and oldest timestamp, got %+v", got)
+	}
+}
+
+func TestDerive_Unavailable(t *testing.T) {
+	samples := map[string]*GridSample{
+		"pm2p5": {Value: 15, Unit: unitMicrogramsPerCubicMetre},
+		"pm10":  {Value: 0, Unit: "kg/m³"},
+	}
+
+	derive(DefaultDerivedVariables, samples, []string{"pm2p5_pm10_ratio", "eaqi_pm"})
+
+	for _, variable := range []string{"pm2p5_pm10_ratio", "eaqi_pm"} {
+		if samples[variable] != nil {
+			t.Errorf("%s: expected no value, got %+v", variable, samples[variable])
+		}
+	}
+}
+
+func TestEAQIBand(t *testing.T) {
+	tests := []struct {
+		value float32
+		want  int
+	}{
+		{value: 0, want: 1},
+		{value: 10, want: 1},
+		{value: 10.1, want: 2},
+		{value: 60, want: 5},
+		{value: 500, want: 6},
+	}
+	for _, tt := range tests {
+		if got := eaqiBand(tt.value, eaqiPM2p5Limits); got != tt.want {
+			t.Errorf("eaqiBand(%v) = %d, want %d", tt.value, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,86 @@
+package domain
+
+import (
+	"fmt"
+	"math"
+)
+
+// MinSeriesPoints is the fewest points a downsampled series may have: its first and last.
+const MinSeriesPoints = 2
+
+// Downsample reduces series, ordered by timestamp, to at most maxPoints samples with
+// Largest-Triangle-Three-Buckets: the first and last samples are kept, and from each of
+// maxPoints-2 equal buckets in between the sample forming the largest triangle with the
+// previously kept one and the next bucket's average. Peaks and troughs survive, and every
+// returned sample is a stored one with its own timestamp and catalog entry. NaN samples
+// are left out of averages and triangles, so a bucket only keeps one when it holds nothing
+// else and gaps stay visible without hiding their neighbours. maxPoints of
+// zero, or at least len(series), returns series unchanged; fewer than MinSeriesPoints
+// fails with *ErrInvalidRequest.
+func Downsample(series []GridSample, maxPoints int) ([]GridSample, error) {
+	if maxPoints == 0 || maxPoints >= len(series) {
+		return series, nil
+	}
+	if maxPoints < MinSeriesPoints {
+		return nil, &ErrInvalidRequest{Field: "max_points", Message: fmt.Sprintf("must be at least %d, %d given", MinSeriesPoints, maxPoints)}
+	}
+
+	start := series[0].Timestamp
+	x := func(i int) float64 { return series[i].Timestamp.Sub(start).Seconds() }
+	y := func(i int) float64 { return float64(series[i].Value) }
+
+	sampled := make([]GridSample, 0, maxPoints)
+	sampled = append(sampled, series[0])
+	bucketSize := float64(len(series)-2) / float64(maxPoints-2)
+	// anchor is the last kept sample with a value, -1 until there is one.
+	anchor := -1
+	if !math.IsNaN(y(0)) {
+		anchor = 0
+	}
+	for bucket := range maxPoints - 2 {
+		nextStart := int(float64(bucket+1)*bucketSize) + 1
+		nextEnd := min(int(float64(bucket+2)*bucketSize)+1, len(series))
+		var avgX, avgY float64
+		var values int
+		for i := nextStart; i < nextEnd; i++ {
+			if math.IsNaN(y(i)) {
+				continue
+			}
+			avgX += x(i)
+			avgY += y(i)
+			values++
+		}
+
+		ax, ay := x(0), 0.0
+		if anchor >= 0 {
+			ax, ay = x(anchor), y(anchor)
+		}
+		if values > 0 {
+			avgX /= float64(values)
+			avgY /= float64(values)
+		} else {
+			// An all-NaN next bucket leaves the triangle flat at the anchor.
+			avgX, avgY = (x(nextStart)+x(nextEnd-1))/2, ay
+		}
+		if anchor < 0 {
+			ay = avgY
+		}
+
+		from, to := int(float64(bucket)*bucketSize)+1, nextStart
+		chosen, maxArea := from, -1.0
+		for i := from; i < to; i++ {
+			if math.IsNaN(y(i)) {
+				continue
+			}
+			area := math.Abs((ax-avgX)*(y(i)-ay) - (ax-x(i))*(avgY-ay))
+			if area > maxArea {
+				chosen, maxArea = i, area
+			}
+		}
+		sampled = append(sampled, series[chosen])
+		if maxArea >= 0 {
+			anchor = chosen
+		}
+	}
+	return append(sampled, series[len(series)-1]), nil
+}
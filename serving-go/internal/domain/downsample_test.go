@@ -0,0 +1,110 @@
+package domain
+
+import (
+	"errors"
+	"math"
+	"testing"
+	"time"
+)
+
+func hourlySeries(values ...float32) []GridSample {
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	series := make([]GridSample, len(values))
+	for i, value := range values {
+		series[i] = GridSample{Value: value, Timestamp: start.Add(time.Duration(i) * time.Hour)}
+	}
+	return series
+}
+
+func TestDownsample_KeepsEndsAndPeaks(t *testing.T) {
+	values := make([]float32, 1000)
+	for i := range values {
+		values[i] = float32(math.Sin(float64(i) / 50))
+	}
+	values[437] = 25
+	values[801] = -25
+	series := hourlySeries(values...)
+
+	sampled, err := Downsample(series, 50)
+	if err != nil {
+		t.Fatalf("Downsample returned error: %v", err)
+	}
+	if len(sampled) != 50 {
+		t.Fatalf("expected 50 points, got %d", len(sampled))
+	}
+	if sampled[0] != series[0] || sampled[49] != series[999] {
+		t.Error("expected first and last samples to be kept")
+	}
+	var peak, trough bool
+	for i, sample := range sampled {
+		if i > 0 && !sample.Timestamp.After(sampled[i-1].Timestamp) {
+			t.Fatalf("expected increasing timestamps, got %s after %s", sample.Timestamp, sampled[i-1].Timestamp)
+		}
+		peak = peak || sample.Value == 25
+		trough = trough || sample.Value == -25
+	}
+	if !peak || !trough {
+		t.Errorf("expected the spikes to survive, peak %v trough %v", peak, trough)
+	}
+}
+
+func TestDownsample_Bounds(t *testing.T) {
+	series := hourlySeries(1, 5, 2, 8, 3)
+	tests := []struct {
+		name      string
+		maxPoints int
+		want      int
+	}{
+		{name: "zero keeps all", maxPoints: 0, want: 5},
+		{name: "above length keeps all", maxPoints: 10, want: 5},
+		{name: "ends only", maxPoints: 2, want: 2},
+		{name: "one bucket", maxPoints: 3, want: 3},
+	}
+	for _, tt := range tests {
+		sampled, err := Downsample(series, tt.maxPoints)
+		if err != nil || len(sampled) != tt.want {
+			t.Errorf("%s: expected %d points, got %d, %v", tt.name, tt.want, len(sampled), err)
+		}
+	}
+	if sampled, _ := Downsample(series, 3); sampled[1].Value != 8 {
+		t.Errorf("expected the single bucket to keep the peak, got %+v", sampled[1])
+	}
+
+	_, err := Downsample(series, 1)
+	if invalid, ok := errors.AsType[*ErrInvalidRequest](err); !ok || invalid.Field != "max_points" {
+		t.Errorf("expected ErrInvalidRequest on max_points, got %v", err)
+	}
+}
+
+func TestDownsample_Gaps(t *testing.T) {
+	nan := float32(math.NaN())
+	values := make([]float32, 100)
+	for i := range values {
+		values[i] = float32(math.Sin(float64(i) / 10))
+	}
+	values[0] = nan
+	for i := 40; i < 60; i++ {
+		values[i] = nan
+	}
+	values[70] = 25
+	series := hourlySeries(values...)
+
+	sampled, err := Downsample(series, 12)
+	if err != nil {
+		t.Fatalf("Downsample returned error: %v", err)
+	}
+	if len(sampled) != 12 {
+		t.Fatalf("expected 12 points, got %d", len(sampled))
+	}
+	var peak, gap bool
+	for _, sample := range sampled[1 : len(sampled)-1] {
+		peak = peak || sample.Value == 25
+		gap = gap || math.IsNaN(float64(sample.Value))
+	}
+	if !peak {
+		t.Errorf("expected the peak after a leading NaN and a gap to survive, got %+v", sampled)
+	}
+	if !gap {
+		t.Errorf("expected an all-NaN bucket to keep a NaN, got %+v", sampled)
+	}
+}
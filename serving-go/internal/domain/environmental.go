@@ -2,12 +2,14 @@ package domain
 
 import (
 	"context"
-	"errors"
 	"fmt"
+	"log/slog"
 	"time"
 
 	"github.com/google/uuid"
 	"golang.org/x/sync/errgroup"
+
+	"github.com/kacper-wojtaszczyk/jackfruit/serving-go/internal/requestid"
 )
 
 type ErrVariableNotFound struct {
@@ -27,32 +29,258 @@ type VariableResult struct {
 	ActualLon    float32
 	CatalogID    uuid.UUID
 	Lineage      Lineage
+	// DerivedFrom lists the direct inputs of a derived variable; empty for stored ones.
+	DerivedFrom []string
+	// InterpolatedTo is set when Value was interpolated between RefTimestamp and this later timestamp.
+	InterpolatedTo time.Time
+	// Stale marks a value older than its variable's max age; only GetAvailableVariables returns those.
+	Stale bool
 }
 
 type Service struct {
 	grid    GridRetriever
 	lineage LineageRetriever
+	derived map[string]DerivedVariable
+	// interpolation holds the max gap between bracketing samples per interpolated variable.
+	interpolation map[string]time.Duration
+	// maxAge holds how old the newest sample of a stored variable may be; absent means unbounded.
+	maxAge map[string]time.Duration
+	// precedence, when set, chooses between datasets covering the same point.
+	precedence *SourcePrecedence
+	now        func() time.Time
+	// contracts, when set, reject samples with units other than declared.
+	contracts map[string]VariableContract
+	// flags, when set, can switch interpolation and source precedence off at runtime.
+	flags FeatureFlags
+	// extent and maxHistory, when set, narrow what requests may ask for beyond validateRequest.
+	extent     *BoundingBox
+	maxHistory time.Duration
+	// slots bounds concurrent grid and lineage lookups across all requests; nil means unbounded.
+	slots chan struct{}
+	// logger, when set, receives DEBUG records of how each request was resolved.
+	logger *slog.Logger
 }
 
-func NewService(grid GridRetriever, lineage LineageRetriever) *Service {
-	return &Service{grid: grid, lineage: lineage}
+type ServiceOption func(*Service)
+
+// WithMaxConcurrency caps in-flight grid and lineage lookups service-wide, so bursts of
+// many-variable requests queue instead of fanning out into the databases. n < 1 disables the cap.
+func WithMaxConcurrency(n int) ServiceOption {
+	return func(s *Service) {
+		s.slots = nil
+		if n > 0 {
+			s.slots = make(chan struct{}, n)
+		}
+	}
 }
 
+// Feature flags the service consults on every request.
+const (
+	FlagInterpolation    = "interpolation"
+	FlagSourcePrecedence = "source_precedence"
+)
+
+// FeatureFlags reports whether a flag is on for a key; partially rolled out flags are on
+// for some keys only.
+type FeatureFlags interface {
+	Enabled(name, key string) bool
+}
+
+// WithFeatureFlags gates configured interpolation and source precedence behind FlagInterpolation
+// and FlagSourcePrecedence, keyed by the request id so partial rollouts apply per request.
+// Without flags, configured features are always on.
+func WithFeatureFlags(flags FeatureFlags) ServiceOption {
+	return func(s *Service) {
+		s.flags = flags
+	}
+}
+
+// WithLogger logs, at DEBUG, the sample each requested variable resolved to.
+func WithLogger(logger *slog.Logger) ServiceOption {
+	return func(s *Service) {
+		s.logger = logger
+	}
+}
+
+func (s *Service) enabled(ctx context.Context, flag string) bool {
+	return s.flags == nil || s.flags.Enabled(flag, requestid.FromContext(ctx))
+}
+
+func NewService(grid GridRetriever, lineage LineageRetriever, opts ...ServiceOption) *Service {
+	s := &Service{grid: grid, lineage: lineage, derived: DefaultDerivedVariables, now: time.Now}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// acquire waits for a lookup slot; the returned func releases it.
+func (s *Service) acquire(ctx context.Context) (func(), error) {
+	if s.slots == nil {
+		return func() {}, nil
+	}
+	select {
+	case s.slots <- struct{}{}:
+		return func() { <-s.slots }, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// GetVariables fails with *ErrVariableNotFound if any requested variable has no data, with
+// *ErrStaleData if its data is older than the configured max age, with *ErrContractViolation
+// if a stored unit differs from its contract, and with *ErrInvalidRequest before querying
+// anything if the request is out of bounds.
+// Aliases resolve to canonical names, which the results carry.
 func (s *Service) GetVariables(
 	ctx context.Context,
 	ts time.Time,
 	lat, lon float32,
 	vars []string,
+) ([]VariableResult, error) {
+	vars = canonicalVariables(vars)
+	if err := validateRequest(ts, lat, lon, vars, s.now()); err != nil {
+		return nil, err
+	}
+	if err := s.checkLimits(ts, lat, lon); err != nil {
+		return nil, err
+	}
+	samples, missing, err := s.getSamples(ctx, ts, lat, lon, vars)
+	if err != nil {
+		return nil, err
+	}
+	if len(missing) > 0 {
+		return nil, &ErrVariableNotFound{Variable: missing[0]}
+	}
+	stale := s.staleness(ts, samples, vars)
+	for _, variable := range vars {
+		if err, ok := stale[variable]; ok {
+			return nil, err
+		}
+	}
+
+	return s.resolveAll(ctx, vars, samples, nil)
+}
+
+// GetAvailableVariables returns results for the variables that have data and, in request
+// order, the names of those that don't. Stale values are returned flagged rather than
+// failing. Grid and lineage errors still fail the whole call.
+func (s *Service) GetAvailableVariables(
+	ctx context.Context,
+	ts time.Time,
+	lat, lon float32,
+	vars []string,
+) ([]VariableResult, []string, error) {
+	vars = canonicalVariables(vars)
+	if err := validateRequest(ts, lat, lon, vars, s.now()); err != nil {
+		return nil, nil, err
+	}
+	if err := s.checkLimits(ts, lat, lon); err != nil {
+		return nil, nil, err
+	}
+	samples, missing, err := s.getSamples(ctx, ts, lat, lon, vars)
+	if err != nil {
+		return nil, nil, err
+	}
+	found := make([]string, 0, len(samples))
+	for _, variable := range vars {
+		if samples[variable] != nil {
+			found = append(found, variable)
+		}
+	}
+
+	results, err := s.resolveAll(ctx, found, samples, s.staleness(ts, samples, found))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return results, missing, nil
+}
+
+// getSamples fetches the stored variables behind vars in one grid call, computes derived
+// ones, and lists, in request order, the variables without data.
+func (s *Service) getSamples(
+	ctx context.Context,
+	ts time.Time,
+	lat, lon float32,
+	vars []string,
+) (map[string]*GridSample, []string, error) {
+	base, err := baseVariables(s.derived, vars)
+	if err != nil {
+		return nil, nil, err
+	}
+	samples, err := s.storedSamples(ctx, base, ts, lat, lon)
+	if err != nil {
+		return nil, nil, fmt.Errorf("getting variables %q: %w", vars, err)
+	}
+	if err := s.checkContracts(samples); err != nil {
+		return nil, nil, err
+	}
+	if s.enabled(ctx, FlagInterpolation) {
+		if err := s.interpolate(ctx, ts, samples); err != nil {
+			return nil, nil, err
+		}
+	}
+	derive(s.derived, samples, vars)
+	var missing []string
+	for _, variable := range vars {
+		sample := samples[variable]
+		if sample == nil {
+			missing = append(missing, variable)
+			continue
+		}
+		if s.logger != nil {
+			s.logger.LogAttrs(ctx, slog.LevelDebug, "resolved variable",
+				slog.String("variable", variable),
+				slog.Time("requested_timestamp", ts),
+				slog.Time("ref_timestamp", sample.Timestamp),
+				slog.Time("interpolated_to", sample.InterpolatedTo),
+				slog.String("catalog_id", sample.CatalogID.String()),
+			)
+		}
+	}
+
+	return samples, missing, nil
+}
+
+// storedSamples fetches stored variables in one grid call, applying source precedence if configured.
+func (s *Service) storedSamples(ctx context.Context, base []string, ts time.Time, lat, lon float32) (map[string]*GridSample, error) {
+	if s.precedence != nil && s.enabled(ctx, FlagSourcePrecedence) {
+		return s.precedentSamples(ctx, base, ts, lat, lon)
+	}
+	release, err := s.acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+	return s.grid.GetSamples(ctx, base, ts, lat, lon)
+}
+
+// resolveAll resolves lineage for vars in parallel, keeping their order, and flags stale ones.
+func (s *Service) resolveAll(
+	ctx context.Context,
+	vars []string,
+	samples map[string]*GridSample,
+	stale map[string]*ErrStaleData,
 ) ([]VariableResult, error) {
 	results := make([]VariableResult, len(vars))
 	g, ctx := errgroup.WithContext(ctx)
 
 	for i, variable := range vars {
 		g.Go(func() error {
-			result, err := s.getVariable(ctx, variable, ts, lat, lon)
+			release, err := s.acquire(ctx)
+			if err != nil {
+				return fmt.Errorf("lineage for variable %q: %w", variable, err)
+			}
+			defer release()
+			result, err := s.resolveLineage(ctx, variable, samples[variable])
 			if err != nil {
 				return err
 			}
+			if definition, ok := s.derived[variable]; ok {
+				result.DerivedFrom = definition.Inputs
+			}
+			result.Stale = stale[variable] != nil
 			results[i] = *result
 
 			return nil
@@ -66,33 +294,25 @@ func (s *Service) GetVariables(
 	return results, nil
 }
 
-func (s *Service) getVariable(
+func (s *Service) resolveLineage(
 	ctx context.Context,
 	variable string,
-	ts time.Time,
-	lat, lon float32,
+	gridSample *GridSample,
 ) (*VariableResult, error) {
-	gridSample, err := s.grid.GetSample(ctx, variable, ts, lat, lon)
-	if errors.Is(err, ErrGridSampleNotFound) {
-		return nil, &ErrVariableNotFound{Variable: variable}
-	}
-	if err != nil {
-		return nil, fmt.Errorf("getting variable %q: %w", variable, err)
-	}
-
 	lineage, err := s.lineage.GetLineage(ctx, gridSample.CatalogID)
 	if err != nil {
 		return nil, fmt.Errorf("lineage for variable %q (catalog_id %s): %w", variable, gridSample.CatalogID, err)
 	}
 
 	return &VariableResult{
-		Name:         variable,
-		Value:        gridSample.Value,
-		Unit:         gridSample.Unit,
-		RefTimestamp: gridSample.Timestamp,
-		ActualLat:    gridSample.Lat,
-		ActualLon:    gridSample.Lon,
-		CatalogID:    gridSample.CatalogID,
-		Lineage:      *lineage,
+		Name:           variable,
+		Value:          gridSample.Value,
+		Unit:           gridSample.Unit,
+		RefTimestamp:   gridSample.Timestamp,
+		ActualLat:      gridSample.Lat,
+		ActualLon:      gridSample.Lon,
+		CatalogID:      gridSample.CatalogID,
+		Lineage:        *lineage,
+		InterpolatedTo: gridSample.InterpolatedTo,
 	}, nil
 }
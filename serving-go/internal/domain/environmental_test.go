@@ -3,7 +3,10 @@ package domain
 import (
 	"context"
 	"errors"
+	"fmt"
+	"slices"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
@@ -11,7 +14,12 @@ import (
 )
 
 type mockGridRetriever struct {
-	samples map[string]*GridSample
+	samples    map[string]*GridSample
+	candidates map[string][]GridSample
+	series     map[string][]GridSample
+	grids      map[string][]GridSample
+	err        error
+	batchCalls int
 }
 
 func (m *mockGridRetriever) GetSample(
@@ -29,6 +37,93 @@ func (m *mockGridRetriever) GetSample(
 	return sample, nil
 }
 
+func (m *mockGridRetriever) GetSamples(
+	ctx context.Context,
+	variables []string,
+	timestamp time.Time,
+	lat float32,
+	lon float32,
+) (map[string]*GridSample, error) {
+	m.batchCalls++
+	if m.err != nil {
+		return nil, m.err
+	}
+	samples := make(map[string]*GridSample, len(variables))
+	for _, variable := range variables {
+		if sample := m.samples[variable]; sample != nil {
+			samples[variable] = sample
+		}
+	}
+
+	return samples, nil
+}
+
+func (m *mockGridRetriever) GetCandidates(
+	ctx context.Context,
+	variables []string,
+	timestamp time.Time,
+	lat float32,
+	lon float32,
+	lookback time.Duration,
+) (map[string][]GridSample, error) {
+	m.batchCalls++
+	if m.err != nil {
+		return nil, m.err
+	}
+	candidates := make(map[string][]GridSample, len(variables))
+	for _, variable := range variables {
+		for _, candidate := range m.candidates[variable] {
+			if !candidate.Timestamp.After(timestamp) && !candidate.Timestamp.Before(timestamp.Add(-lookback)) {
+				candidates[variable] = append(candidates[variable], candidate)
+			}
+		}
+	}
+
+	return candidates, nil
+}
+
+func (m *mockGridRetriever) GetSeries(
+	ctx context.Context,
+	variable string,
+	lat float32,
+	lon float32,
+	from time.Time,
+	to time.Time,
+) ([]GridSample, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	var series []GridSample
+	for _, sample := range m.series[variable] {
+		if !sample.Timestamp.Before(from) && !sample.Timestamp.After(to) {
+			series = append(series, sample)
+		}
+	}
+	if len(series) == 0 {
+		return nil, ErrGridSampleNotFound
+	}
+
+	return series, nil
+}
+
+func (m *mockGridRetriever) GetGrid(
+	ctx context.Context,
+	variable string,
+	timestamp time.Time,
+	bbox BoundingBox,
+	stride int,
+) ([]GridSample, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	cells := m.grids[variable]
+	if len(cells) == 0 {
+		return nil, ErrGridSampleNotFound
+	}
+
+	return cells, nil
+}
+
 type mockLineageRetriever struct {
 	lineages map[uuid.UUID]*Lineage
 	err      error
@@ -239,3 +334,209 @@ func TestService_GetVariables_LineageFails(t *testing.T) {
 		t.Errorf("expected error wrapping original postgres error, got: %v", err)
 	}
 }
+
+func TestService_GetVariables_SingleBatchQuery(t *testing.T) {
+	catalogID, err := uuid.NewV7()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	grid := &mockGridRetriever{
+		samples: map[string]*GridSample{
+			"pm2p5": {Value: 12.5, Unit: "µg/m³", Lat: 52.5, Lon: 13.4, Timestamp: time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC), CatalogID: catalogID},
+			"pm10":  {Value: 20.1, Unit: "µg/m³", Lat: 52.5, Lon: 13.4, Timestamp: time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC), CatalogID: catalogID},
+		},
+	}
+	service := NewService(grid, &mockLineageRetriever{
+		lineages: map[uuid.UUID]*Lineage{
+			catalogID: {Source: "ads", Dataset: "cams-europe-air-quality-forecast"},
+		},
+	})
+
+	variables, err := service.GetVariables(t.Context(), time.Date(2026, 3, 1, 13, 0, 0, 0, time.UTC), 52.5, 13.4, []string{"pm2p5", "pm10"})
+	if err != nil {
+		t.Fatalf("GetVariables returned error: %v", err)
+	}
+	if len(variables) != 2 {
+		t.Fatalf("expected 2 variables, got %d", len(variables))
+	}
+	if variables[0].Name != "pm2p5" || variables[1].Name != "pm10" {
+		t.Errorf("expected results in request order, got %q, %q", variables[0].Name, variables[1].Name)
+	}
+	if grid.batchCalls != 1 {
+		t.Errorf("expected 1 GetSamples call, got %d", grid.batchCalls)
+	}
+}
+
+func TestService_GetVariables_GridFails(t *testing.T) {
+	chErr := errors.New("clickhouse down")
+
+	service := NewService(&mockGridRetriever{err: chErr}, &mockLineageRetriever{})
+
+	_, err := service.GetVariables(t.Context(), time.Date(2026, 3, 1, 13, 0, 0, 0, time.UTC), 52.5, 13.4, []string{"pm2p5"})
+	if !errors.Is(err, chErr) {
+		t.Errorf("expected error wrapping original clickhouse error, got: %v", err)
+	}
+	if _, ok := errors.AsType[*ErrVariableNotFound](err); ok {
+		t.Error("error should not be ErrVariableNotFound for a generic store failure")
+	}
+}
+
+func TestService_GetAvailableVariables(t *testing.T) {
+	catalogID, err := uuid.NewV7()
+	if err != nil {
+		t.Fatal(err)
+	}
+	timestamp := time.Date(2026, 2, 27, 4, 0, 0, 0, time.UTC)
+	service := NewService(&mockGridRetriever{samples: map[string]*GridSample{
+		"pm10": {Value: 1.0, Unit: "µg/m³", Timestamp: timestamp, CatalogID: catalogID},
+	}}, &mockLineageRetriever{lineages: map[uuid.UUID]*Lineage{
+		catalogID: {Source: "ads", Dataset: "cams-europe-air-quality-forecasts"},
+	}})
+
+	results, missing, err := service.GetAvailableVariables(t.Context(), timestamp, 52.5, 13.4, []string{"pm2p5", "pm10", "no2"})
+	if err != nil {
+		t.Fatalf("GetAvailableVariables returned error: %v", err)
+	}
+
+	if len(results) != 1 || results[0].Name != "pm10" || results[0].Lineage.Source != "ads" {
+		t.Errorf("expected only pm10 with lineage, got %+v", results)
+	}
+	if len(missing) != 2 || missing[0] != "pm2p5" || missing[1] != "no2" {
+		t.Errorf("expected missing [pm2p5 no2] in request order, got %v", missing)
+	}
+}
+
+func TestService_GetVariables_ResolvesAliases(t *testing.T) {
+	catalogID, err := uuid.NewV7()
+	if err != nil {
+		t.Fatal(err)
+	}
+	timestamp := time.Date(2026, 2, 27, 4, 0, 0, 0, time.UTC)
+	service := NewService(&mockGridRetriever{samples: map[string]*GridSample{
+		"pm2p5": {Value: 1.0, Unit: "µg/m³", Timestamp: timestamp, CatalogID: catalogID},
+	}}, &mockLineageRetriever{lineages: map[uuid.UUID]*Lineage{catalogID: {Source: "ads"}}})
+
+	results, err := service.GetVariables(t.Context(), timestamp, 52.5, 13.4, []string{"PM2.5"})
+	if err != nil {
+		t.Fatalf("GetVariables returned error: %v", err)
+	}
+	if len(results) != 1 || results[0].Name != "pm2p5" {
+		t.Errorf("expected canonical name pm2p5, got %+v", results)
+	}
+}
+
+func TestService_GetVariables_Derived(t *testing.T) {
+	catalogID, err := uuid.NewV7()
+	if err != nil {
+		t.Fatal(err)
+	}
+	timestamp := time.Date(2026, 2, 27, 4, 0, 0, 0, time.UTC)
+	grid := &mockGridRetriever{samples: map[string]*GridSample{
+		"pm2p5": {Value: 10, Unit: "µg/m³", Timestamp: timestamp, CatalogID: catalogID},
+		"pm10":  {Value: 40, Unit: "µg/m³", Timestamp: timestamp, CatalogID: catalogID},
+	}}
+	service := NewService(grid, &mockLineageRetriever{lineages: map[uuid.UUID]*Lineage{catalogID: {Source: "ads"}}})
+
+	results, err := service.GetVariables(t.Context(), timestamp, 52.5, 13.4, []string{"pm2p5_pm10_ratio", "pm10"})
+	if err != nil {
+		t.Fatalf("GetVariables returned error: %v", err)
+	}
+
+	if len(results) != 2 {
+		t.Fatalf("expected 2 results, got %d", len(results))
+	}
+	if results[0].Name != "pm2p5_pm10_ratio" || results[0].Value != 0.25 || results[0].Lineage.Source != "ads" {
+		t.Errorf("unexpected derived result %+v", results[0])
+	}
+	if !slices.Equal(results[0].DerivedFrom, []string{"pm2p5", "pm10"}) {
+		t.Errorf("expected derived_from [pm2p5 pm10], got %v", results[0].DerivedFrom)
+	}
+	if results[1].DerivedFrom != nil {
+		t.Errorf("expected stored variable without derived_from, got %v", results[1].DerivedFrom)
+	}
+	if grid.batchCalls != 1 {
+		t.Errorf("expected inputs in a single batch, got %d calls", grid.batchCalls)
+	}
+}
+
+func TestService_GetVariables_DerivedInputMissing(t *testing.T) {
+	timestamp := time.Date(2026, 2, 27, 4, 0, 0, 0, time.UTC)
+	service := NewService(&mockGridRetriever{samples: map[string]*GridSample{
+		"pm2p5": {Value: 10, Unit: "µg/m³", Timestamp: timestamp},
+	}}, &mockLineageRetriever{})
+
+	_, err := service.GetVariables(t.Context(), timestamp, 52.5, 13.4, []string{"eaqi_pm"})
+	if notFound, ok := errors.AsType[*ErrVariableNotFound](err); !ok || notFound.Variable != "eaqi_pm" {
+		t.Errorf("expected ErrVariableNotFound for eaqi_pm, got %v", err)
+	}
+}
+
+func TestService_GetVariables_InvalidRequest(t *testing.T) {
+	grid := &mockGridRetriever{}
+	service := NewService(grid, &mockLineageRetriever{})
+
+	_, err := service.GetVariables(t.Context(), time.Now(), 52.5, 13.4, []string{"pm25", "pm2p5"})
+	if _, ok := errors.AsType[*ErrInvalidRequest](err); !ok {
+		t.Errorf("expected ErrInvalidRequest for aliased duplicates, got %v", err)
+	}
+	if grid.batchCalls != 0 {
+		t.Errorf("expected no grid query for an invalid request, got %d", grid.batchCalls)
+	}
+}
+
+type concurrencyTrackingLineage struct {
+	mu       sync.Mutex
+	inFlight int
+	peak     int
+}
+
+func (l *concurrencyTrackingLineage) GetLineage(ctx context.Context, catalogID uuid.UUID) (*Lineage, error) {
+	l.mu.Lock()
+	l.inFlight++
+	l.peak = max(l.peak, l.inFlight)
+	l.mu.Unlock()
+
+	time.Sleep(5 * time.Millisecond)
+
+	l.mu.Lock()
+	l.inFlight--
+	l.mu.Unlock()
+	return &Lineage{Source: "ads"}, nil
+}
+
+func TestService_GetVariables_MaxConcurrency(t *testing.T) {
+	timestamp := time.Date(2026, 2, 27, 4, 0, 0, 0, time.UTC)
+	samples := make(map[string]*GridSample)
+	var vars []string
+	for i := range 8 {
+		variable := fmt.Sprintf("var%d", i)
+		vars = append(vars, variable)
+		samples[variable] = &GridSample{Value: float32(i), Timestamp: timestamp}
+	}
+	lineage := &concurrencyTrackingLineage{}
+	service := NewService(&mockGridRetriever{samples: samples}, lineage, WithMaxConcurrency(2))
+
+	results, err := service.GetVariables(t.Context(), timestamp, 52.5, 13.4, vars)
+	if err != nil {
+		t.Fatalf("GetVariables returned error: %v", err)
+	}
+	if len(results) != len(vars) {
+		t.Errorf("expected %d results, got %d", len(vars), len(results))
+	}
+	if lineage.peak > 2 {
+		t.Errorf("expected at most 2 concurrent lookups, saw %d", lineage.peak)
+	}
+}
+
+func TestService_GetVariables_MaxConcurrencyHonoursContext(t *testing.T) {
+	service := NewService(&mockGridRetriever{}, &mockLineageRetriever{}, WithMaxConcurrency(1))
+	service.slots <- struct{}{} // every slot taken
+
+	ctx, cancel := context.WithTimeout(t.Context(), 10*time.Millisecond)
+	defer cancel()
+	_, err := service.GetVariables(ctx, time.Now(), 52.5, 13.4, []string{"pm2p5"})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected deadline exceeded while waiting for a slot, got %v", err)
+	}
+}
@@ -17,8 +17,51 @@ type GridSample struct {
 	Lon       float32
 	Timestamp time.Time
 	CatalogID uuid.UUID
+	// InterpolatedTo is the later sample's timestamp when the domain service interpolated
+	// Value between Timestamp and it; stores never set it.
+	InterpolatedTo time.Time
+}
+
+// GridValue is a single grid_data row as written by loaders.
+type GridValue struct {
+	Variable  string
+	Timestamp time.Time
+	Lat       float32
+	Lon       float32
+	Value     float32
+	Unit      string
+	CatalogID uuid.UUID
+}
+
+// BoundingBox is an inclusive lat/lon rectangle. It does not wrap the antimeridian.
+type BoundingBox struct {
+	MinLat float32
+	MinLon float32
+	MaxLat float32
+	MaxLon float32
+}
+
+// Coverage summarises what grid_data holds for one variable.
+type Coverage struct {
+	Variable       string
+	FirstTimestamp time.Time
+	LastTimestamp  time.Time
+	Extent         BoundingBox
+	Rows           uint64
 }
 
 type GridRetriever interface {
 	GetSample(ctx context.Context, variable string, timestamp time.Time, lat float32, lon float32) (*GridSample, error)
+	// GetSamples resolves several variables in a single round trip. Variables
+	// with no data at or before timestamp are absent from the returned map.
+	GetSamples(ctx context.Context, variables []string, timestamp time.Time, lat float32, lon float32) (map[string]*GridSample, error)
+	// GetCandidates returns, per variable, the nearest sample of every catalog entry with data
+	// in [timestamp-lookback, timestamp], each at that entry's latest timestamp, so callers can
+	// choose between datasets. Variables without any are absent from the map.
+	GetCandidates(ctx context.Context, variables []string, timestamp time.Time, lat float32, lon float32, lookback time.Duration) (map[string][]GridSample, error)
+	// GetSeries returns the samples in [from, to] of the cell nearest to (lat, lon), oldest first.
+	GetSeries(ctx context.Context, variable string, lat float32, lon float32, from time.Time, to time.Time) ([]GridSample, error)
+	// GetGrid returns every stride-th cell inside bbox at the latest timestamp at or before
+	// timestamp, ordered by (lat, lon).
+	GetGrid(ctx context.Context, variable string, timestamp time.Time, bbox BoundingBox, stride int) ([]GridSample, error)
 }
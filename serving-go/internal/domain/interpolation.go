@@ -0,0 +1,89 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"golang.org/x/sync/errgroup"
+)
+
+// WithInterpolation enables linear interpolation for the listed stored variables: when the
+// requested time falls between two samples of the same cell at most maxGaps[variable]
+// apart, the value is interpolated instead of snapping to the earlier one. Names may be aliases.
+func WithInterpolation(maxGaps map[string]time.Duration) ServiceOption {
+	return func(s *Service) {
+		s.interpolation = make(map[string]time.Duration, len(maxGaps))
+		for variable, maxGap := range maxGaps {
+			s.interpolation[CanonicalVariable(variable)] = maxGap
+		}
+	}
+}
+
+// interpolate replaces the samples of interpolated variables that predate ts by values
+// interpolated towards the next sample of the same cell. Samples without a later
+// neighbour within the gap are kept as they are.
+func (s *Service) interpolate(ctx context.Context, ts time.Time, samples map[string]*GridSample) error {
+	g, ctx := errgroup.WithContext(ctx)
+	var mu sync.Mutex
+	interpolated := make(map[string]*GridSample)
+
+	for variable, sample := range samples {
+		maxGap, ok := s.interpolation[variable]
+		if !ok || sample == nil || !sample.Timestamp.Before(ts) || ts.Sub(sample.Timestamp) >= maxGap {
+			continue
+		}
+		g.Go(func() error {
+			release, err := s.acquire(ctx)
+			if err != nil {
+				return fmt.Errorf("interpolating variable %q: %w", variable, err)
+			}
+			defer release()
+			series, err := s.grid.GetSeries(ctx, variable, sample.Lat, sample.Lon, sample.Timestamp, sample.Timestamp.Add(maxGap))
+			if errors.Is(err, ErrGridSampleNotFound) {
+				return nil
+			}
+			if err != nil {
+				return fmt.Errorf("interpolating variable %q: %w", variable, err)
+			}
+			if next := nextSample(series, sample, ts); next != nil {
+				mu.Lock()
+				interpolated[variable] = lerp(sample, next, ts)
+				mu.Unlock()
+			}
+			return nil
+		})
+	}
+
+	if err := g.Wait(); err != nil {
+		return err
+	}
+	for variable, sample := range interpolated {
+		samples[variable] = sample
+	}
+
+	return nil
+}
+
+// nextSample returns the first sample of earlier's cell after ts, if any.
+func nextSample(series []GridSample, earlier *GridSample, ts time.Time) *GridSample {
+	for i := range series {
+		next := &series[i]
+		if next.Lat == earlier.Lat && next.Lon == earlier.Lon && next.Timestamp.After(ts) {
+			return next
+		}
+	}
+	return nil
+}
+
+// lerp interpolates linearly between earlier and later at ts. The result keeps the
+// earlier sample's timestamp and catalog id, so lineage points at the data actually read.
+func lerp(earlier, later *GridSample, ts time.Time) *GridSample {
+	fraction := float32(ts.Sub(earlier.Timestamp).Seconds() / later.Timestamp.Sub(earlier.Timestamp).Seconds())
+	sample := *earlier
+	sample.Value = earlier.Value + (later.Value-earlier.Value)*fraction
+	sample.InterpolatedTo = later.Timestamp
+	return &sample
+}
@@ -0,0 +1,174 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/kacper-wojtaszczyk/jackfruit/serving-go/internal/requestid"
+)
+
+type staticFlags map[string]bool
+
+func (f staticFlags) Enabled(name, _ string) bool {
+	return f[name]
+}
+
+// keyRecorder records the keys flags are checked for.
+type keyRecorder struct {
+	keys []string
+}
+
+func (f *keyRecorder) Enabled(_, key string) bool {
+	f.keys = append(f.keys, key)
+	return true
+}
+
+func TestService_GetVariables_FlagsKeyedByRequestID(t *testing.T) {
+	hour := time.Date(2026, 2, 27, 4, 0, 0, 0, time.UTC)
+	grid := &mockGridRetriever{
+		samples: map[string]*GridSample{"pm2p5": {Value: 10, Timestamp: hour}},
+		series:  map[string][]GridSample{"pm2p5": {{Value: 10, Timestamp: hour}}},
+	}
+	flags := &keyRecorder{}
+	lineage := &mockLineageRetriever{lineages: map[uuid.UUID]*Lineage{uuid.Nil: {Source: "ads"}}}
+	service := NewService(grid, lineage,
+		WithInterpolation(map[string]time.Duration{"pm2p5": 90 * time.Minute}), WithFeatureFlags(flags))
+
+	ctx := requestid.WithID(t.Context(), "req-1")
+	if _, err := service.GetVariables(ctx, hour.Add(15*time.Minute), 52.5, 13.4, []string{"pm2p5"}); err != nil {
+		t.Fatalf("GetVariables returned error: %v", err)
+	}
+	if len(flags.keys) == 0 {
+		t.Fatal("expected the interpolation flag to be consulted")
+	}
+	for _, key := range flags.keys {
+		if key != "req-1" {
+			t.Errorf("expected flags keyed by the request id, got %q", key)
+		}
+	}
+}
+
+func TestService_GetVariables_Interpolation(t *testing.T) {
+	catalogID, err := uuid.NewV7()
+	if err != nil {
+		t.Fatal(err)
+	}
+	hour := time.Date(2026, 2, 27, 4, 0, 0, 0, time.UTC)
+	earlier := GridSample{Value: 10, Unit: "µg/m³", Lat: 52.5, Lon: 13.4, Timestamp: hour, CatalogID: catalogID}
+	later := GridSample{Value: 20, Unit: "µg/m³", Lat: 52.5, Lon: 13.4, Timestamp: hour.Add(time.Hour), CatalogID: catalogID}
+	lineage := &mockLineageRetriever{lineages: map[uuid.UUID]*Lineage{catalogID: {Source: "ads"}}}
+
+	tests := []struct {
+		name         string
+		maxGaps      map[string]time.Duration
+		flags        staticFlags
+		series       []GridSample
+		requested    time.Time
+		wantValue    float32
+		wantInterpTo time.Time
+	}{
+		{
+			name:         "mid-hour",
+			maxGaps:      map[string]time.Duration{"pm25": 90 * time.Minute},
+			series:       []GridSample{earlier, later},
+			requested:    hour.Add(15 * time.Minute),
+			wantValue:    12.5,
+			wantInterpTo: later.Timestamp,
+		},
+		{
+			name:      "not configured",
+			series:    []GridSample{earlier, later},
+			requested: hour.Add(15 * time.Minute),
+			wantValue: 10,
+		},
+		{
+			name:      "switched off by flag",
+			maxGaps:   map[string]time.Duration{"pm2p5": 90 * time.Minute},
+			flags:     staticFlags{FlagInterpolation: false},
+			series:    []GridSample{earlier, later},
+			requested: hour.Add(15 * time.Minute),
+			wantValue: 10,
+		},
+		{
+			name:      "gap too wide",
+			maxGaps:   map[string]time.Duration{"pm2p5": 30 * time.Minute},
+			series:    []GridSample{earlier, later},
+			requested: hour.Add(15 * time.Minute),
+			wantValue: 10,
+		},
+		{
+			name:      "no later sample",
+			maxGaps:   map[string]time.Duration{"pm2p5": 90 * time.Minute},
+			series:    []GridSample{earlier},
+			requested: hour.Add(15 * time.Minute),
+			wantValue: 10,
+		},
+		{
+			name:      "exact timestamp",
+			maxGaps:   map[string]time.Duration{"pm2p5": 90 * time.Minute},
+			series:    []GridSample{earlier, later},
+			requested: hour,
+			wantValue: 10,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			grid := &mockGridRetriever{
+				samples: map[string]*GridSample{"pm2p5": &earlier},
+				series:  map[string][]GridSample{"pm2p5": tt.series},
+			}
+			opts := []ServiceOption{WithInterpolation(tt.maxGaps)}
+			if tt.flags != nil {
+				opts = append(opts, WithFeatureFlags(tt.flags))
+			}
+			service := NewService(grid, lineage, opts...)
+
+			results, err := service.GetVariables(t.Context(), tt.requested, 52.5, 13.4, []string{"pm2p5"})
+			if err != nil {
+				t.Fatalf("GetVariables returned error: %v", err)
+			}
+			got := results[0]
+			if got.Value != tt.wantValue {
+				t.Errorf("expected value %v, got %v", tt.wantValue, got.Value)
+			}
+			if !got.InterpolatedTo.Equal(tt.wantInterpTo) {
+				t.Errorf("expected interpolated_to %v, got %v", tt.wantInterpTo, got.InterpolatedTo)
+			}
+			if !got.RefTimestamp.Equal(hour) || got.Lineage.Source != "ads" {
+				t.Errorf("expected ref timestamp and lineage of the earlier sample, got %+v", got)
+			}
+		})
+	}
+	if earlier.Value != 10 {
+		t.Errorf("interpolation mutated the retrieved sample: %v", earlier.Value)
+	}
+}
+
+func TestService_GetVariables_InterpolatedDerivedInputs(t *testing.T) {
+	hour := time.Date(2026, 2, 27, 4, 0, 0, 0, time.UTC)
+	grid := &mockGridRetriever{
+		samples: map[string]*GridSample{
+			"pm2p5": {Value: 10, Timestamp: hour},
+			"pm10":  {Value: 40, Timestamp: hour},
+		},
+		series: map[string][]GridSample{
+			"pm2p5": {{Value: 10, Timestamp: hour}, {Value: 20, Timestamp: hour.Add(time.Hour)}},
+			"pm10":  {{Value: 40, Timestamp: hour}, {Value: 60, Timestamp: hour.Add(time.Hour)}},
+		},
+	}
+	service := NewService(grid, &mockLineageRetriever{lineages: map[uuid.UUID]*Lineage{{}: {}}},
+		WithInterpolation(map[string]time.Duration{"pm2p5": time.Hour, "pm10": time.Hour}))
+
+	results, err := service.GetVariables(t.Context(), hour.Add(30*time.Minute), 0, 0, []string{"pm_coarse"})
+	if err != nil {
+		t.Fatalf("GetVariables returned error: %v", err)
+	}
+	if results[0].Value != 35 {
+		t.Errorf("expected pm_coarse from interpolated inputs (50 - 15), got %v", results[0].Value)
+	}
+	if !results[0].InterpolatedTo.IsZero() {
+		t.Errorf("expected derived result without interpolated_to, got %v", results[0].InterpolatedTo)
+	}
+}
@@ -0,0 +1,152 @@
+package domain
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/sync/errgroup"
+)
+
+// SourcePrecedence picks between datasets that cover the same point, e.g. preferring an
+// analysis over a recent forecast over an older one.
+type SourcePrecedence struct {
+	// Datasets ranks lineage datasets, most preferred first. Unlisted datasets, and samples
+	// without lineage, rank after all listed ones.
+	Datasets []string
+	// Lookback bounds how long before the requested time a candidate may be. A preferred
+	// dataset within it wins over a newer but less preferred one.
+	Lookback time.Duration
+}
+
+// WithSourcePrecedence makes point lookups choose each stored variable's sample by dataset
+// rank, then newest timestamp, instead of taking whichever sample is newest. The chosen
+// dataset is reported through the result's lineage.
+func WithSourcePrecedence(precedence SourcePrecedence) ServiceOption {
+	return func(s *Service) {
+		s.precedence = nil
+		if len(precedence.Datasets) > 0 {
+			s.precedence = &precedence
+		}
+	}
+}
+
+// precedentSamples returns, for each of variables with data, the candidate ranked first.
+// Variables without candidates in the lookback, e.g. daily datasets or old requests, fall
+// back to their newest sample as without precedence.
+func (s *Service) precedentSamples(
+	ctx context.Context,
+	variables []string,
+	ts time.Time,
+	lat, lon float32,
+) (map[string]*GridSample, error) {
+	release, err := s.acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+	candidates, err := s.grid.GetCandidates(ctx, variables, ts, lat, lon, s.precedence.Lookback)
+	release()
+	if err != nil {
+		return nil, err
+	}
+
+	datasets, err := s.candidateDatasets(ctx, candidates)
+	if err != nil {
+		return nil, err
+	}
+	rank := func(sample GridSample) int {
+		if i := slices.Index(s.precedence.Datasets, datasets[sample.CatalogID]); i >= 0 {
+			return i
+		}
+		return len(s.precedence.Datasets)
+	}
+
+	samples := make(map[string]*GridSample, len(candidates))
+	for variable, options := range candidates {
+		if len(options) == 0 {
+			continue
+		}
+		best := slices.MinFunc(options, func(a, b GridSample) int {
+			if byRank := rank(a) - rank(b); byRank != 0 {
+				return byRank
+			}
+			if byTime := b.Timestamp.Compare(a.Timestamp); byTime != 0 {
+				return byTime
+			}
+			// UUIDv7 catalog ids sort by creation, so the later-loaded entry wins ties.
+			return bytes.Compare(b.CatalogID[:], a.CatalogID[:])
+		})
+		samples[variable] = &best
+	}
+
+	var rest []string
+	for _, variable := range variables {
+		if samples[variable] == nil {
+			rest = append(rest, variable)
+		}
+	}
+	if len(rest) == 0 {
+		return samples, nil
+	}
+	release, err = s.acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+	newest, err := s.grid.GetSamples(ctx, rest, ts, lat, lon)
+	release()
+	if err != nil {
+		return nil, err
+	}
+	for variable, sample := range newest {
+		samples[variable] = sample
+	}
+
+	return samples, nil
+}
+
+// candidateDatasets looks up the dataset of every distinct catalog entry among candidates.
+// Entries without lineage are left out and so rank last.
+func (s *Service) candidateDatasets(ctx context.Context, candidates map[string][]GridSample) (map[uuid.UUID]string, error) {
+	datasets := make(map[uuid.UUID]string)
+	var mu sync.Mutex
+	g, ctx := errgroup.WithContext(ctx)
+	seen := make(map[uuid.UUID]bool)
+
+	for _, options := range candidates {
+		for _, candidate := range options {
+			catalogID := candidate.CatalogID
+			if seen[catalogID] {
+				continue
+			}
+			seen[catalogID] = true
+			g.Go(func() error {
+				release, err := s.acquire(ctx)
+				if err != nil {
+					return fmt.Errorf("lineage for catalog_id %s: %w", catalogID, err)
+				}
+				defer release()
+				lineage, err := s.lineage.GetLineage(ctx, catalogID)
+				if errors.Is(err, ErrLineageNotFound) {
+					return nil
+				}
+				if err != nil {
+					return fmt.Errorf("lineage for catalog_id %s: %w", catalogID, err)
+				}
+				mu.Lock()
+				datasets[catalogID] = lineage.Dataset
+				mu.Unlock()
+				return nil
+			})
+		}
+	}
+
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+	return datasets, nil
+}
@@ -0,0 +1,95 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestService_GetVariables_SourcePrecedence(t *testing.T) {
+	analysisID, olderForecastID, newerForecastID := uuid.New(), uuid.New(), uuid.New()
+	unknownID := uuid.New()
+	lineage := &mockLineageRetriever{lineages: map[uuid.UUID]*Lineage{
+		analysisID:      {Source: "ads", Dataset: "cams-europe-air-quality-analysis"},
+		olderForecastID: {Source: "ads", Dataset: "cams-europe-air-quality-forecast"},
+		newerForecastID: {Source: "ads", Dataset: "cams-europe-air-quality-forecast"},
+	}}
+	requested := time.Date(2026, 3, 11, 14, 30, 0, 0, time.UTC)
+	analysis := GridSample{Value: 1, Timestamp: time.Date(2026, 3, 11, 12, 0, 0, 0, time.UTC), CatalogID: analysisID}
+	olderForecast := GridSample{Value: 2, Timestamp: time.Date(2026, 3, 11, 13, 0, 0, 0, time.UTC), CatalogID: olderForecastID}
+	newerForecast := GridSample{Value: 3, Timestamp: time.Date(2026, 3, 11, 14, 0, 0, 0, time.UTC), CatalogID: newerForecastID}
+	unknown := GridSample{Value: 4, Timestamp: time.Date(2026, 3, 11, 14, 0, 0, 0, time.UTC), CatalogID: unknownID}
+	precedence := []string{"cams-europe-air-quality-analysis", "cams-europe-air-quality-forecast"}
+
+	tests := []struct {
+		name        string
+		candidates  []GridSample
+		lookback    time.Duration
+		wantValue   float32
+		wantDataset string
+	}{
+		{name: "analysis over forecast", candidates: []GridSample{newerForecast, analysis, olderForecast}, lookback: 6 * time.Hour, wantValue: 1, wantDataset: "cams-europe-air-quality-analysis"},
+		{name: "recent forecast over older", candidates: []GridSample{olderForecast, newerForecast}, lookback: 6 * time.Hour, wantValue: 3, wantDataset: "cams-europe-air-quality-forecast"},
+		{name: "analysis outside lookback", candidates: []GridSample{analysis, newerForecast}, lookback: time.Hour, wantValue: 3, wantDataset: "cams-europe-air-quality-forecast"},
+		{name: "unlisted dataset ranks last", candidates: []GridSample{unknown, olderForecast}, lookback: 6 * time.Hour, wantValue: 2, wantDataset: "cams-europe-air-quality-forecast"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			grid := &mockGridRetriever{candidates: map[string][]GridSample{"pm2p5": tt.candidates}}
+			service := NewService(grid, lineage, WithSourcePrecedence(SourcePrecedence{Datasets: precedence, Lookback: tt.lookback}))
+
+			results, err := service.GetVariables(t.Context(), requested, 52.5, 13.4, []string{"pm2p5"})
+			if err != nil {
+				t.Fatalf("GetVariables returned error: %v", err)
+			}
+			if results[0].Value != tt.wantValue || results[0].Lineage.Dataset != tt.wantDataset {
+				t.Errorf("expected value %v from %s, got %v from %s",
+					tt.wantValue, tt.wantDataset, results[0].Value, results[0].Lineage.Dataset)
+			}
+		})
+	}
+}
+
+func TestService_GetVariables_SourcePrecedenceNotFound(t *testing.T) {
+	service := NewService(&mockGridRetriever{}, &mockLineageRetriever{},
+		WithSourcePrecedence(SourcePrecedence{Datasets: []string{"analysis"}, Lookback: time.Hour}))
+
+	_, missing, err := service.GetAvailableVariables(t.Context(), time.Date(2026, 3, 11, 14, 0, 0, 0, time.UTC), 52.5, 13.4, []string{"pm2p5"})
+	if err != nil {
+		t.Fatalf("GetAvailableVariables returned error: %v", err)
+	}
+	if len(missing) != 1 || missing[0] != "pm2p5" {
+		t.Errorf("expected pm2p5 missing, got %v", missing)
+	}
+}
+
+func TestService_GetVariables_SourcePrecedenceFallback(t *testing.T) {
+	forecastID, dailyID := uuid.New(), uuid.New()
+	lineage := &mockLineageRetriever{lineages: map[uuid.UUID]*Lineage{
+		forecastID: {Source: "ads", Dataset: "cams-europe-air-quality-forecast"},
+		dailyID:    {Source: "ads", Dataset: "daily-reanalysis"},
+	}}
+	requested := time.Date(2026, 3, 11, 14, 30, 0, 0, time.UTC)
+	grid := &mockGridRetriever{
+		candidates: map[string][]GridSample{
+			"pm2p5": {{Value: 1, Timestamp: requested.Add(-time.Hour), CatalogID: forecastID}},
+		},
+		samples: map[string]*GridSample{
+			"pm10": {Value: 7, Timestamp: requested.Add(-30 * time.Hour), CatalogID: dailyID},
+		},
+	}
+	service := NewService(grid, lineage, WithSourcePrecedence(SourcePrecedence{
+		Datasets: []string{"cams-europe-air-quality-forecast"},
+		Lookback: 6 * time.Hour,
+	}))
+
+	results, err := service.GetVariables(t.Context(), requested, 52.5, 13.4, []string{"pm2p5", "pm10"})
+	if err != nil {
+		t.Fatalf("GetVariables returned error: %v", err)
+	}
+	if results[0].Value != 1 || results[1].Value != 7 || results[1].Lineage.Dataset != "daily-reanalysis" {
+		t.Errorf("expected the candidate for pm2p5 and the newest sample for pm10, got %+v", results)
+	}
+}
@@ -0,0 +1,65 @@
+package domain
+
+// QualityStats summarises one catalog entry's grid_data values. Min, Max and Mean skip NaNs.
+type QualityStats struct {
+	Rows uint64
+	// ExpectedRows is distinct latitudes times distinct longitudes, the size of a complete grid.
+	ExpectedRows uint64
+	NaNRows      uint64
+	ZeroRows     uint64
+	Min          float32
+	Max          float32
+	Mean         float32
+	// Units lists the distinct units of the entry's rows.
+	Units []string
+	// LatStep and LonStep are the measured grid spacing in degrees, zero for a single row or column.
+	LatStep float32
+	LonStep float32
+}
+
+// Quality flags; each marks a likely broken load rather than a certain one.
+const (
+	QualityNotLoaded      = "not_loaded"
+	QualityAllNaN         = "all_nan"
+	QualityAllZero        = "all_zero"
+	QualityConstant       = "constant"
+	QualityIncompleteGrid = "incomplete_grid"
+	// QualityUnitMismatch and QualityResolutionMismatch mark data contract violations.
+	QualityUnitMismatch       = "unit_mismatch"
+	QualityResolutionMismatch = "resolution_mismatch"
+	// QualityMissingTimesteps marks an entry whose run lacks a neighbouring timestamp at the
+	// contract's step.
+	QualityMissingTimesteps = "missing_timesteps"
+)
+
+type Quality struct {
+	QualityStats
+	Flags []string
+}
+
+func (s QualityStats) flags() []string {
+	if s.Rows == 0 {
+		return []string{QualityNotLoaded}
+	}
+	flags := []string{}
+	switch {
+	case s.NaNRows == s.Rows:
+		flags = append(flags, QualityAllNaN)
+	case s.ZeroRows == s.Rows:
+		flags = append(flags, QualityAllZero)
+	case s.Min == s.Max:
+		flags = append(flags, QualityConstant)
+	}
+	if s.Rows < s.ExpectedRows {
+		flags = append(flags, QualityIncompleteGrid)
+	}
+	return flags
+}
+
+// NaNFraction is the share of rows holding NaN.
+func (s QualityStats) NaNFraction() float64 {
+	if s.Rows == 0 {
+		return 0
+	}
+	return float64(s.NaNRows) / float64(s.Rows)
+}
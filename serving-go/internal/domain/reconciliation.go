@@ -0,0 +1,134 @@
+package domain
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"time"
+)
+
+const (
+	// DefaultReconciliationDays is how many days, ending today, a reconciliation covers by default.
+	DefaultReconciliationDays = 7
+	// MaxReconciliationDays bounds the dates one reconciliation may expect.
+	MaxReconciliationDays = 366
+)
+
+// Delivery is one raw file recorded for a dataset and the number of catalog entries produced from it.
+type Delivery struct {
+	RawFile RawFile
+	Entries int
+}
+
+type DeliveryRetriever interface {
+	// ListDeliveries returns the raw files of dataset dated within [from, to], oldest date first.
+	ListDeliveries(ctx context.Context, dataset string, from, to time.Time) ([]Delivery, error)
+}
+
+type PartitionStatus string
+
+const (
+	// PartitionMissing means no raw file was recorded for the date.
+	PartitionMissing PartitionStatus = "missing"
+	// PartitionEmpty means raw files were recorded but produced no catalog entries.
+	PartitionEmpty    PartitionStatus = "empty"
+	PartitionIngested PartitionStatus = "ingested"
+)
+
+// ExpectedPartition is one date a dataset's cadence expects a delivery for.
+type ExpectedPartition struct {
+	Date       time.Time
+	Deliveries []Delivery
+}
+
+func (p ExpectedPartition) Status() PartitionStatus {
+	if len(p.Deliveries) == 0 {
+		return PartitionMissing
+	}
+	for _, delivery := range p.Deliveries {
+		if delivery.Entries > 0 {
+			return PartitionIngested
+		}
+	}
+	return PartitionEmpty
+}
+
+type Reconciliation struct {
+	Dataset    string
+	Cadence    time.Duration
+	Partitions []ExpectedPartition
+}
+
+// Reconciler compares each dataset's expected cadence with the raw files the catalog holds.
+type Reconciler struct {
+	deliveries DeliveryRetriever
+	cadences   map[string]time.Duration
+	now        func() time.Time
+}
+
+// NewReconciler expects a delivery of each dataset every cadence. Cadences must be whole days,
+// as the pipeline partitions by date; dates are aligned to multiples of the cadence since the Unix epoch.
+func NewReconciler(deliveries DeliveryRetriever, cadences map[string]time.Duration) *Reconciler {
+	return &Reconciler{deliveries: deliveries, cadences: cadences, now: time.Now}
+}
+
+// Datasets returns the datasets with an expected cadence, sorted.
+func (r *Reconciler) Datasets() []string {
+	return slices.Sorted(maps.Keys(r.cadences))
+}
+
+// Reconcile lists the dates in [from, to] that dataset's cadence expects and the raw files
+// recorded for each. Zero bounds default to the DefaultReconciliationDays ending today. It fails
+// with *ErrInvalidRequest for datasets without a cadence, an inverted range or more than
+// MaxReconciliationDays expected dates.
+func (r *Reconciler) Reconcile(ctx context.Context, dataset string, from, to time.Time) (*Reconciliation, error) {
+	cadence, ok := r.cadences[dataset]
+	if !ok {
+		return nil, &ErrInvalidRequest{Field: "dataset", Message: fmt.Sprintf("no expected cadence for %q", dataset)}
+	}
+	if to.IsZero() {
+		to = r.now()
+	}
+	to = to.UTC().Truncate(24 * time.Hour)
+	if from.IsZero() {
+		from = to.AddDate(0, 0, 1-DefaultReconciliationDays)
+	}
+	from = from.UTC().Truncate(24 * time.Hour)
+	if from.After(to) {
+		return nil, &ErrInvalidRequest{Field: "from", Message: "must not be after to"}
+	}
+
+	var dates []time.Time
+	for date := from.Add(-time.Duration(from.UnixNano() % int64(cadence))); !date.After(to); date = date.Add(cadence) {
+		if date.Before(from) {
+			continue
+		}
+		if len(dates) == MaxReconciliationDays {
+			return nil, &ErrInvalidRequest{
+				Field:   "from",
+				Message: fmt.Sprintf("range may expect at most %d dates", MaxReconciliationDays),
+			}
+		}
+		dates = append(dates, date)
+	}
+
+	reconciliation := &Reconciliation{Dataset: dataset, Cadence: cadence, Partitions: make([]ExpectedPartition, len(dates))}
+	if len(dates) == 0 {
+		return reconciliation, nil
+	}
+	deliveries, err := r.deliveries.ListDeliveries(ctx, dataset, dates[0], dates[len(dates)-1])
+	if err != nil {
+		return nil, fmt.Errorf("listing deliveries of %q: %w", dataset, err)
+	}
+	byDate := make(map[time.Time][]Delivery)
+	for _, delivery := range deliveries {
+		date := delivery.RawFile.Date.UTC().Truncate(24 * time.Hour)
+		byDate[date] = append(byDate[date], delivery)
+	}
+	for i, date := range dates {
+		reconciliation.Partitions[i] = ExpectedPartition{Date: date, Deliveries: byDate[date]}
+	}
+
+	return reconciliation, nil
+}
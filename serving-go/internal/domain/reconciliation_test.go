@@ -0,0 +1,84 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+type mockDeliveryRetriever struct {
+	deliveries []Delivery
+	from, to   time.Time
+}
+
+func (m *mockDeliveryRetriever) ListDeliveries(_ context.Context, _ string, from, to time.Time) ([]Delivery, error) {
+	m.from, m.to = from, to
+	return m.deliveries, nil
+}
+
+func day(d int) time.Time {
+	return time.Date(2026, 10, d, 0, 0, 0, 0, time.UTC)
+}
+
+func TestReconciler_Reconcile(t *testing.T) {
+	deliveries := &mockDeliveryRetriever{deliveries: []Delivery{
+		{RawFile: RawFile{Date: day(10)}, Entries: 0},
+		{RawFile: RawFile{Date: day(11)}, Entries: 0},
+		{RawFile: RawFile{Date: day(11)}, Entries: 4},
+	}}
+	reconciler := NewReconciler(deliveries, map[string]time.Duration{"cams": 24 * time.Hour})
+	reconciler.now = func() time.Time { return day(12).Add(9 * time.Hour) }
+
+	got, err := reconciler.Reconcile(t.Context(), "cams", time.Time{}, time.Time{})
+	if err != nil {
+		t.Fatalf("Reconcile returned error: %v", err)
+	}
+	if len(got.Partitions) != DefaultReconciliationDays {
+		t.Fatalf("expected %d partitions, got %d", DefaultReconciliationDays, len(got.Partitions))
+	}
+	if !deliveries.from.Equal(day(6)) || !deliveries.to.Equal(day(12)) {
+		t.Errorf("expected deliveries listed for 6..12 Oct, got %s..%s", deliveries.from, deliveries.to)
+	}
+	want := map[time.Time]PartitionStatus{day(9): PartitionMissing, day(10): PartitionEmpty, day(11): PartitionIngested, day(12): PartitionMissing}
+	for _, partition := range got.Partitions {
+		if status, ok := want[partition.Date]; ok && partition.Status() != status {
+			t.Errorf("expected %s to be %s, got %s", partition.Date.Format(time.DateOnly), status, partition.Status())
+		}
+	}
+}
+
+func TestReconciler_ReconcileAlignsToCadence(t *testing.T) {
+	reconciler := NewReconciler(&mockDeliveryRetriever{}, map[string]time.Duration{"weekly": 7 * 24 * time.Hour})
+
+	got, err := reconciler.Reconcile(t.Context(), "weekly", day(1), day(31))
+	if err != nil {
+		t.Fatalf("Reconcile returned error: %v", err)
+	}
+	// The Unix epoch was a Thursday, so a 7-day cadence expects Thursdays.
+	if len(got.Partitions) != 5 || !got.Partitions[0].Date.Equal(day(1)) || !got.Partitions[4].Date.Equal(day(29)) {
+		t.Errorf("expected the five Thursdays of October 2026, got %+v", got.Partitions)
+	}
+}
+
+func TestReconciler_ReconcileInvalid(t *testing.T) {
+	reconciler := NewReconciler(&mockDeliveryRetriever{}, map[string]time.Duration{"cams": 24 * time.Hour})
+	tests := []struct {
+		name     string
+		dataset  string
+		from, to time.Time
+		field    string
+	}{
+		{name: "unknown dataset", dataset: "other", field: "dataset"},
+		{name: "inverted range", dataset: "cams", from: day(12), to: day(11), field: "from"},
+		{name: "too long", dataset: "cams", from: day(1).AddDate(-2, 0, 0), to: day(1), field: "from"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := reconciler.Reconcile(t.Context(), tt.dataset, tt.from, tt.to)
+			if invalid, ok := errors.AsType[*ErrInvalidRequest](err); !ok || invalid.Field != tt.field {
+				t.Errorf("expected ErrInvalidRequest on %s, got %v", tt.field, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,101 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// MaxSeriesDays bounds the range of one series request, which is loaded whole before
+// downsampling.
+const MaxSeriesDays = 366
+
+// GetSeries returns the stored samples of variable in [from, to] at the cell nearest to
+// (lat, lon), failing with *ErrContractViolation if any carries a unit off its contract.
+// Derived variables are not supported for ranges, and ranges are at most MaxSeriesDays.
+func (s *Service) GetSeries(
+	ctx context.Context,
+	variable string,
+	lat, lon float32,
+	from, to time.Time,
+) ([]GridSample, error) {
+	variable = CanonicalVariable(variable)
+	if err := validateRequest(to, lat, lon, []string{variable}, s.now()); err != nil {
+		return nil, err
+	}
+	if from.IsZero() || from.After(to) {
+		return nil, &ErrInvalidRequest{Field: "from", Message: "must be set and not after to"}
+	}
+	if to.Sub(from) > MaxSeriesDays*24*time.Hour {
+		return nil, &ErrInvalidRequest{Field: "from", Message: fmt.Sprintf("must be at most %d days before to", MaxSeriesDays)}
+	}
+	if err := s.checkLimits(from, lat, lon); err != nil {
+		return nil, err
+	}
+
+	release, err := s.acquire(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("getting series %q: %w", variable, err)
+	}
+	defer release()
+	series, err := s.grid.GetSeries(ctx, variable, lat, lon, from, to)
+	if errors.Is(err, ErrGridSampleNotFound) || err == nil && len(series) == 0 {
+		return nil, &ErrVariableNotFound{Variable: variable}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("getting series %q: %w", variable, err)
+	}
+	for _, sample := range series {
+		if err := s.checkUnit(variable, sample.Unit); err != nil {
+			return nil, err
+		}
+	}
+
+	return series, nil
+}
+
+// GetGrid returns every stride-th stored cell of variable inside bbox at the latest
+// timestamp at or before ts.
+func (s *Service) GetGrid(
+	ctx context.Context,
+	variable string,
+	ts time.Time,
+	bbox BoundingBox,
+	stride int,
+) ([]GridSample, error) {
+	variable = CanonicalVariable(variable)
+	if err := validateRequest(ts, bbox.MinLat, bbox.MinLon, []string{variable}, s.now()); err != nil {
+		return nil, err
+	}
+	if err := validateRequest(ts, bbox.MaxLat, bbox.MaxLon, []string{variable}, s.now()); err != nil {
+		return nil, err
+	}
+	if err := s.checkLimits(ts, bbox.MinLat, bbox.MinLon); err != nil {
+		return nil, err
+	}
+	if err := s.checkLimits(ts, bbox.MaxLat, bbox.MaxLon); err != nil {
+		return nil, err
+	}
+	if bbox.MinLat > bbox.MaxLat || bbox.MinLon > bbox.MaxLon {
+		return nil, &ErrInvalidRequest{Field: "bbox", Message: "minimum must not exceed maximum"}
+	}
+	if stride < 1 {
+		return nil, &ErrInvalidRequest{Field: "stride", Message: fmt.Sprintf("must be at least 1, %d given", stride)}
+	}
+
+	release, err := s.acquire(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("getting grid %q: %w", variable, err)
+	}
+	defer release()
+	cells, err := s.grid.GetGrid(ctx, variable, ts, bbox, stride)
+	if errors.Is(err, ErrGridSampleNotFound) {
+		return nil, &ErrVariableNotFound{Variable: variable}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("getting grid %q: %w", variable, err)
+	}
+
+	return cells, nil
+}
@@ -0,0 +1,78 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestService_GetSeries(t *testing.T) {
+	start := time.Date(2026, 2, 27, 0, 0, 0, 0, time.UTC)
+	grid := &mockGridRetriever{series: map[string][]GridSample{
+		"pm2p5": {
+			{Value: 1, Timestamp: start},
+			{Value: 2, Timestamp: start.Add(time.Hour)},
+			{Value: 3, Timestamp: start.Add(2 * time.Hour)},
+		},
+	}}
+	service := NewService(grid, &mockLineageRetriever{})
+
+	series, err := service.GetSeries(t.Context(), "PM2.5", 52.5, 13.4, start.Add(time.Hour), start.Add(2*time.Hour))
+	if err != nil {
+		t.Fatalf("GetSeries returned error: %v", err)
+	}
+	if len(series) != 2 || series[0].Value != 2 {
+		t.Errorf("expected the two samples in range, got %+v", series)
+	}
+
+	_, err = service.GetSeries(t.Context(), "no2", 52.5, 13.4, start, start.Add(time.Hour))
+	if notFound, ok := errors.AsType[*ErrVariableNotFound](err); !ok || notFound.Variable != "no2" {
+		t.Errorf("expected ErrVariableNotFound for no2, got %v", err)
+	}
+
+	_, err = service.GetSeries(t.Context(), "pm2p5", 52.5, 13.4, start.Add(time.Hour), start)
+	if _, ok := errors.AsType[*ErrInvalidRequest](err); !ok {
+		t.Errorf("expected ErrInvalidRequest for reversed range, got %v", err)
+	}
+
+	_, err = service.GetSeries(t.Context(), "pm2p5", 52.5, 13.4, start.AddDate(0, 0, -MaxSeriesDays), start)
+	if err != nil {
+		t.Errorf("expected a %d-day range to be served, got %v", MaxSeriesDays, err)
+	}
+	_, err = service.GetSeries(t.Context(), "pm2p5", 52.5, 13.4, start.AddDate(0, 0, -MaxSeriesDays).Add(-time.Hour), start)
+	if invalid, ok := errors.AsType[*ErrInvalidRequest](err); !ok || invalid.Field != "from" {
+		t.Errorf("expected ErrInvalidRequest on from for a longer range, got %v", err)
+	}
+}
+
+func TestService_GetGrid(t *testing.T) {
+	ts := time.Date(2026, 2, 27, 0, 0, 0, 0, time.UTC)
+	bbox := BoundingBox{MinLat: 50, MinLon: 10, MaxLat: 55, MaxLon: 15}
+	grid := &mockGridRetriever{grids: map[string][]GridSample{
+		"pm10": {{Value: 1, Lat: 50, Lon: 10}, {Value: 2, Lat: 50, Lon: 10.1}},
+	}}
+	service := NewService(grid, &mockLineageRetriever{})
+
+	cells, err := service.GetGrid(t.Context(), "pm10", ts, bbox, 1)
+	if err != nil {
+		t.Fatalf("GetGrid returned error: %v", err)
+	}
+	if len(cells) != 2 {
+		t.Errorf("expected 2 cells, got %d", len(cells))
+	}
+
+	tests := []struct {
+		name  string
+		bbox  BoundingBox
+		field string
+	}{
+		{name: "inverted", bbox: BoundingBox{MinLat: 55, MinLon: 10, MaxLat: 50, MaxLon: 15}, field: "bbox"},
+		{name: "out of range", bbox: BoundingBox{MinLat: 50, MinLon: 10, MaxLat: 95, MaxLon: 15}, field: "lat"},
+	}
+	for _, tt := range tests {
+		_, err := service.GetGrid(t.Context(), "pm10", ts, tt.bbox, 1)
+		if invalid, ok := errors.AsType[*ErrInvalidRequest](err); !ok || invalid.Field != tt.field {
+			t.Errorf("%s: expected ErrInvalidRequest on %s, got %v", tt.name, tt.field, err)
+		}
+	}
+}
@@ -0,0 +1,56 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+)
+
+// ErrStaleData means the newest value at or before the requested time is older than the
+// variable's max age, so serving it as current would mislead.
+type ErrStaleData struct {
+	Variable     string
+	RefTimestamp time.Time
+	MaxAge       time.Duration
+}
+
+func (e *ErrStaleData) Error() string {
+	return fmt.Sprintf("variable %q is stale: newest data at %s is older than %s",
+		e.Variable, e.RefTimestamp.UTC().Format(time.RFC3339), e.MaxAge)
+}
+
+// WithMaxAge sets, per stored variable, how far the newest sample may predate the requested
+// time before it counts as stale. Derived variables are stale when any input is. Names may be aliases.
+func WithMaxAge(maxAges map[string]time.Duration) ServiceOption {
+	return func(s *Service) {
+		s.maxAge = make(map[string]time.Duration, len(maxAges))
+		for variable, maxAge := range maxAges {
+			s.maxAge[CanonicalVariable(variable)] = maxAge
+		}
+	}
+}
+
+// staleness returns, for each stale variable among vars, the error describing it.
+// Interpolated samples bracket ts and are never stale.
+func (s *Service) staleness(ts time.Time, samples map[string]*GridSample, vars []string) map[string]*ErrStaleData {
+	if len(s.maxAge) == 0 {
+		return nil
+	}
+	stale := make(map[string]*ErrStaleData)
+	for _, variable := range vars {
+		if samples[variable] == nil {
+			continue
+		}
+		// The tree was already validated by getSamples, so expansion can't fail here.
+		base, _ := baseVariables(s.derived, []string{variable})
+		for _, input := range base {
+			sample := samples[input]
+			maxAge, ok := s.maxAge[input]
+			if !ok || sample == nil || !sample.InterpolatedTo.IsZero() || ts.Sub(sample.Timestamp) <= maxAge {
+				continue
+			}
+			stale[variable] = &ErrStaleData{Variable: variable, RefTimestamp: sample.Timestamp, MaxAge: maxAge}
+			break
+		}
+	}
+	return stale
+}
@@ -0,0 +1,66 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestService_Staleness(t *testing.T) {
+	requested := time.Date(2026, 3, 11, 12, 0, 0, 0, time.UTC)
+	grid := &mockGridRetriever{samples: map[string]*GridSample{
+		"pm2p5": {Value: 10, Timestamp: requested.Add(-7 * 24 * time.Hour)},
+		"pm10":  {Value: 40, Timestamp: requested.Add(-time.Hour)},
+	}}
+	lineage := &mockLineageRetriever{lineages: map[uuid.UUID]*Lineage{{}: {Source: "ads"}}}
+	service := NewService(grid, lineage, WithMaxAge(map[string]time.Duration{"PM2.5": 6 * time.Hour, "pm10": 6 * time.Hour}))
+
+	t.Run("strict fails on stale variable", func(t *testing.T) {
+		_, err := service.GetVariables(t.Context(), requested, 52.5, 13.4, []string{"pm10", "pm2p5"})
+		stale, ok := errors.AsType[*ErrStaleData](err)
+		if !ok {
+			t.Fatalf("expected ErrStaleData, got %v", err)
+		}
+		if stale.Variable != "pm2p5" || stale.MaxAge != 6*time.Hour || !stale.RefTimestamp.Equal(requested.Add(-7*24*time.Hour)) {
+			t.Errorf("unexpected stale error %+v", stale)
+		}
+	})
+
+	t.Run("strict serves fresh variables", func(t *testing.T) {
+		if _, err := service.GetVariables(t.Context(), requested, 52.5, 13.4, []string{"pm10"}); err != nil {
+			t.Errorf("expected fresh pm10 to be served, got %v", err)
+		}
+	})
+
+	t.Run("partial flags stale variables and derived ones", func(t *testing.T) {
+		results, missing, err := service.GetAvailableVariables(t.Context(), requested, 52.5, 13.4, []string{"pm2p5", "pm10", "pm_coarse"})
+		if err != nil {
+			t.Fatalf("GetAvailableVariables returned error: %v", err)
+		}
+		if len(missing) != 0 || len(results) != 3 {
+			t.Fatalf("expected 3 results and nothing missing, got %d results, missing %v", len(results), missing)
+		}
+		for i, want := range []bool{true, false, true} {
+			if results[i].Stale != want {
+				t.Errorf("%s: expected stale=%v, got %v", results[i].Name, want, results[i].Stale)
+			}
+		}
+	})
+}
+
+func TestService_Staleness_Unconfigured(t *testing.T) {
+	requested := time.Date(2026, 3, 11, 12, 0, 0, 0, time.UTC)
+	grid := &mockGridRetriever{samples: map[string]*GridSample{"pm2p5": {Timestamp: requested.Add(-30 * 24 * time.Hour)}}}
+	service := NewService(grid, &mockLineageRetriever{lineages: map[uuid.UUID]*Lineage{{}: {}}},
+		WithMaxAge(map[string]time.Duration{"pm10": time.Hour}))
+
+	results, err := service.GetVariables(t.Context(), requested, 52.5, 13.4, []string{"pm2p5"})
+	if err != nil {
+		t.Fatalf("expected variables without a max age to be served, got %v", err)
+	}
+	if results[0].Stale {
+		t.Error("expected unconfigured variable not to be flagged stale")
+	}
+}
@@ -0,0 +1,154 @@
+package domain
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"time"
+)
+
+type FreshnessRetriever interface {
+	// GetLatestTimestamps returns the newest stored timestamp per variable.
+	GetLatestTimestamps(ctx context.Context) (map[string]time.Time, error)
+}
+
+// LoadRetriever reports when data last arrived, as opposed to the valid times it covers.
+type LoadRetriever interface {
+	// GetLatestVariableLoads returns when each variable last had a catalog entry created.
+	GetLatestVariableLoads(ctx context.Context) (map[string]time.Time, error)
+	// GetLatestDatasetLoads returns when each dataset last had a raw file ingested.
+	GetLatestDatasetLoads(ctx context.Context) (map[string]time.Time, error)
+}
+
+// VariableStatus describes how current one stored variable is. LatestTimestamp is its newest
+// valid time, which forecasts put ahead of now; Lag is how long ago it was last loaded.
+type VariableStatus struct {
+	Variable        string
+	LatestTimestamp time.Time
+	LastLoadedAt    time.Time
+	Lag             time.Duration
+	// MaxLag is the variable's configured max load lag; zero means it is never stale.
+	MaxLag time.Duration
+	Stale  bool
+}
+
+// DatasetStatus describes how long ago one dataset was last ingested.
+type DatasetStatus struct {
+	Dataset      string
+	LastLoadedAt time.Time
+	Lag          time.Duration
+	// Cadence is the dataset's expected ingestion cadence; zero means it is never stale.
+	Cadence time.Duration
+	Stale   bool
+}
+
+type Status struct {
+	CheckedAt time.Time
+	Variables []VariableStatus
+	Datasets  []DatasetStatus
+}
+
+// Stale reports whether any variable or dataset is stale.
+func (s Status) Stale() bool {
+	for _, variable := range s.Variables {
+		if variable.Stale {
+			return true
+		}
+	}
+	for _, dataset := range s.Datasets {
+		if dataset.Stale {
+			return true
+		}
+	}
+	return false
+}
+
+// StatusReporter summarises data freshness: each variable's time since its last load
+// against its max load lag, and each dataset's time since its last ingestion against its
+// cadence. Max load lags are not the service's max ages, which bound how far a value may
+// predate the requested time rather than how long ago it arrived.
+type StatusReporter struct {
+	freshness FreshnessRetriever
+	loads     LoadRetriever
+	maxLag    map[string]time.Duration
+	cadences  map[string]time.Duration
+	now       func() time.Time
+}
+
+// NewStatusReporter takes max load lags per stored variable (names may be aliases) and
+// ingestion cadences per dataset.
+func NewStatusReporter(freshness FreshnessRetriever, loads LoadRetriever, maxLags, cadences map[string]time.Duration) *StatusReporter {
+	r := &StatusReporter{
+		freshness: freshness,
+		loads:     loads,
+		maxLag:    make(map[string]time.Duration, len(maxLags)),
+		cadences:  cadences,
+		now:       time.Now,
+	}
+	for variable, maxLag := range maxLags {
+		r.maxLag[CanonicalVariable(variable)] = maxLag
+	}
+	return r
+}
+
+// GetStatus lists every stored variable and every ingested dataset, sorted by name.
+// Variables with a max load lag and datasets with a cadence but no recorded load are listed as
+// stale with a zero LastLoadedAt.
+func (r *StatusReporter) GetStatus(ctx context.Context) (*Status, error) {
+	latest, err := r.freshness.GetLatestTimestamps(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("getting latest timestamps: %w", err)
+	}
+	variableLoads, err := r.loads.GetLatestVariableLoads(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("getting latest variable loads: %w", err)
+	}
+	datasetLoads, err := r.loads.GetLatestDatasetLoads(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("getting latest dataset loads: %w", err)
+	}
+
+	now := r.now().UTC()
+	status := &Status{CheckedAt: now}
+	for _, variable := range sortedKeys(latest, r.maxLag) {
+		maxLag := r.maxLag[variable]
+		loadedAt, ok := variableLoads[variable]
+		variableStatus := VariableStatus{
+			Variable:        variable,
+			LatestTimestamp: latest[variable],
+			LastLoadedAt:    loadedAt,
+			MaxLag:          maxLag,
+			Stale:           maxLag > 0,
+		}
+		if ok {
+			variableStatus.Lag = max(now.Sub(loadedAt), 0)
+			variableStatus.Stale = maxLag > 0 && variableStatus.Lag > maxLag
+		}
+		status.Variables = append(status.Variables, variableStatus)
+	}
+	for _, dataset := range sortedKeys(datasetLoads, r.cadences) {
+		cadence := r.cadences[dataset]
+		loadedAt, ok := datasetLoads[dataset]
+		datasetStatus := DatasetStatus{Dataset: dataset, LastLoadedAt: loadedAt, Cadence: cadence, Stale: cadence > 0}
+		if ok {
+			datasetStatus.Lag = max(now.Sub(loadedAt), 0)
+			datasetStatus.Stale = cadence > 0 && datasetStatus.Lag > cadence
+		}
+		status.Datasets = append(status.Datasets, datasetStatus)
+	}
+
+	return status, nil
+}
+
+// sortedKeys returns the names in either map, sorted.
+func sortedKeys[V, W any](a map[string]V, b map[string]W) []string {
+	keys := slices.Collect(maps.Keys(a))
+	for key := range b {
+		if _, ok := a[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	slices.Sort(keys)
+	return keys
+}
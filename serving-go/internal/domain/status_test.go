@@ -0,0 +1,133 @@
+package domain
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type mockFreshnessRetriever map[string]time.Time
+
+func (m mockFreshnessRetriever) GetLatestTimestamps(_ context.Context) (map[string]time.Time, error) {
+	return m, nil
+}
+
+type mockLoadRetriever struct {
+	variables, datasets map[string]time.Time
+}
+
+func (m mockLoadRetriever) GetLatestVariableLoads(_ context.Context) (map[string]time.Time, error) {
+	return m.variables, nil
+}
+
+func (m mockLoadRetriever) GetLatestDatasetLoads(_ context.Context) (map[string]time.Time, error) {
+	return m.datasets, nil
+}
+
+func TestStatusReporter_GetStatus(t *testing.T) {
+	now := time.Date(2026, 10, 14, 12, 0, 0, 0, time.UTC)
+	forecastEnd := now.Add(48 * time.Hour)
+	reporter := NewStatusReporter(
+		mockFreshnessRetriever{"pm2p5": forecastEnd, "pm10": forecastEnd, "temperature": forecastEnd},
+		mockLoadRetriever{
+			variables: map[string]time.Time{
+				"pm2p5":       now.Add(-2 * time.Hour),
+				"pm10":        now.Add(-8 * time.Hour),
+				"temperature": now.Add(-30 * time.Hour),
+			},
+			datasets: map[string]time.Time{
+				"cams-europe-air-quality-forecast": now.Add(-2 * time.Hour),
+				"ifs-weather-forecast":             now.Add(-30 * time.Hour),
+			},
+		},
+		map[string]time.Duration{"PM2.5": 6 * time.Hour, "pm10": 6 * time.Hour, "no2": 6 * time.Hour},
+		map[string]time.Duration{"cams-europe-air-quality-forecast": 24 * time.Hour, "ifs-weather-forecast": 24 * time.Hour, "cams-global": 24 * time.Hour},
+	)
+	reporter.now = func() time.Time { return now }
+
+	status, err := reporter.GetStatus(t.Context())
+	if err != nil {
+		t.Fatalf("GetStatus returned error: %v", err)
+	}
+	wantVariables := []VariableStatus{
+		{Variable: "no2", MaxLag: 6 * time.Hour, Stale: true},
+		{Variable: "pm10", LatestTimestamp: forecastEnd, LastLoadedAt: now.Add(-8 * time.Hour), Lag: 8 * time.Hour, MaxLag: 6 * time.Hour, Stale: true},
+		{Variable: "pm2p5", LatestTimestamp: forecastEnd, LastLoadedAt: now.Add(-2 * time.Hour), Lag: 2 * time.Hour, MaxLag: 6 * time.Hour},
+		{Variable: "temperature", LatestTimestamp: forecastEnd, LastLoadedAt: now.Add(-30 * time.Hour), Lag: 30 * time.Hour},
+	}
+	if len(status.Variables) != len(wantVariables) {
+		t.Fatalf("expected %d variables, got %+v", len(wantVariables), status.Variables)
+	}
+	for i, got := range status.Variables {
+		if got != wantVariables[i] {
+			t.Errorf("variable %d: expected %+v, got %+v", i, wantVariables[i], got)
+		}
+	}
+	wantDatasets := []DatasetStatus{
+		{Dataset: "cams-europe-air-quality-forecast", LastLoadedAt: now.Add(-2 * time.Hour), Lag: 2 * time.Hour, Cadence: 24 * time.Hour},
+		{Dataset: "cams-global", Cadence: 24 * time.Hour, Stale: true},
+		{Dataset: "ifs-weather-forecast", LastLoadedAt: now.Add(-30 * time.Hour), Lag: 30 * time.Hour, Cadence: 24 * time.Hour, Stale: true},
+	}
+	if len(status.Datasets) != len(wantDatasets) {
+		t.Fatalf("expected %d datasets, got %+v", len(wantDatasets), status.Datasets)
+	}
+	for i, got := range status.Datasets {
+		if got != wantDatasets[i] {
+			t.Errorf("dataset %d: expected %+v, got %+v", i, wantDatasets[i], got)
+		}
+	}
+	if !status.Stale() || !status.CheckedAt.Equal(now) {
+		t.Errorf("expected a stale status checked at %s, got %+v", now, status)
+	}
+}
+
+func TestStatus_StaleDatasetOnly(t *testing.T) {
+	status := Status{
+		Variables: []VariableStatus{{Variable: "pm2p5"}},
+		Datasets:  []DatasetStatus{{Dataset: "cams-europe-air-quality-forecast", Stale: true}},
+	}
+	if !status.Stale() {
+		t.Error("expected an overdue dataset to make the status stale")
+	}
+}
+
+func TestStatusReporter_LoadLagIsNotMaxAge(t *testing.T) {
+	// A daily forecast loaded 18 hours ago still serves the current hour within a 6h max age,
+	// so only a max load lag, not the max age, may mark it stale in the status.
+	now := time.Date(2026, 10, 14, 18, 0, 0, 0, time.UTC)
+	loadedAt := now.Add(-18 * time.Hour)
+	grid := &mockGridRetriever{samples: map[string]*GridSample{"pm2p5": {Value: 10, Timestamp: now}}}
+	lineage := &mockLineageRetriever{lineages: map[uuid.UUID]*Lineage{{}: {Source: "ads"}}}
+	maxAges := map[string]time.Duration{"pm2p5": 6 * time.Hour}
+	service := NewService(grid, lineage, WithMaxAge(maxAges))
+	if _, err := service.GetVariables(t.Context(), now, 52.5, 13.4, []string{"pm2p5"}); err != nil {
+		t.Fatalf("expected pm2p5 to be served as fresh, got %v", err)
+	}
+
+	for _, tt := range []struct {
+		name      string
+		maxLags   map[string]time.Duration
+		wantStale bool
+	}{
+		{name: "max age as load lag", maxLags: maxAges, wantStale: true},
+		{name: "daily load lag", maxLags: map[string]time.Duration{"pm2p5": 26 * time.Hour}},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			reporter := NewStatusReporter(
+				mockFreshnessRetriever{"pm2p5": now.Add(30 * time.Hour)},
+				mockLoadRetriever{variables: map[string]time.Time{"pm2p5": loadedAt}},
+				tt.maxLags, nil,
+			)
+			reporter.now = func() time.Time { return now }
+			status, err := reporter.GetStatus(t.Context())
+			if err != nil {
+				t.Fatalf("GetStatus returned error: %v", err)
+			}
+			if status.Variables[0].Stale != tt.wantStale {
+				t.Errorf("expected stale=%v, got %+v", tt.wantStale, status.Variables[0])
+			}
+		})
+	}
+}
@@ -0,0 +1,87 @@
+package domain
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"time"
+)
+
+// MaxFutureHorizon bounds how far ahead of now a request may ask; CAMS forecasts reach five days.
+const MaxFutureHorizon = 7 * 24 * time.Hour
+
+// ErrInvalidRequest reports a well-formed request whose values the service can't serve.
+type ErrInvalidRequest struct {
+	Field   string
+	Message string
+}
+
+func (e *ErrInvalidRequest) Error() string {
+	return fmt.Sprintf("invalid %s: %s", e.Field, e.Message)
+}
+
+// validateRequest checks a point request; vars must already be canonical so aliases of the
+// same variable count as duplicates.
+func validateRequest(ts time.Time, lat, lon float32, vars []string, now time.Time) error {
+	if isNonFinite(lat) || lat < -90 || lat > 90 {
+		return &ErrInvalidRequest{Field: "lat", Message: fmt.Sprintf("must be between -90 and 90, %g given", lat)}
+	}
+	if isNonFinite(lon) || lon < -180 || lon > 180 {
+		return &ErrInvalidRequest{Field: "lon", Message: fmt.Sprintf("must be between -180 and 180, %g given", lon)}
+	}
+	if ts.IsZero() {
+		return &ErrInvalidRequest{Field: "timestamp", Message: "must be set"}
+	}
+	if ts.After(now.Add(MaxFutureHorizon)) {
+		return &ErrInvalidRequest{Field: "timestamp", Message: fmt.Sprintf("must be at most %s ahead", MaxFutureHorizon)}
+	}
+	if len(vars) == 0 {
+		return &ErrInvalidRequest{Field: "variables", Message: "must not be empty"}
+	}
+	for i, variable := range vars {
+		if variable == "" {
+			return &ErrInvalidRequest{Field: "variables", Message: "must not contain empty names"}
+		}
+		if slices.Contains(vars[:i], variable) {
+			return &ErrInvalidRequest{Field: "variables", Message: fmt.Sprintf("%q requested more than once", variable)}
+		}
+	}
+	return nil
+}
+
+// isNonFinite reports whether v is NaN or infinite; NaN fails every range comparison, so
+// it has to be rejected explicitly.
+func isNonFinite(v float32) bool {
+	f := float64(v)
+	return math.IsNaN(f) || math.IsInf(f, 0)
+}
+
+// WithExtent rejects requests outside bbox, e.g. to keep a public demo to a small region.
+func WithExtent(bbox BoundingBox) ServiceOption {
+	return func(s *Service) {
+		s.extent = &bbox
+	}
+}
+
+// WithMaxHistory rejects requests for timestamps more than d before now; zero allows any.
+func WithMaxHistory(d time.Duration) ServiceOption {
+	return func(s *Service) {
+		s.maxHistory = d
+	}
+}
+
+// checkLimits applies the deployment's extent and history limits to one requested point.
+func (s *Service) checkLimits(ts time.Time, lat, lon float32) error {
+	if e := s.extent; e != nil {
+		if lat < e.MinLat || lat > e.MaxLat {
+			return &ErrInvalidRequest{Field: "lat", Message: fmt.Sprintf("must be between %g and %g here, %g given", e.MinLat, e.MaxLat, lat)}
+		}
+		if lon < e.MinLon || lon > e.MaxLon {
+			return &ErrInvalidRequest{Field: "lon", Message: fmt.Sprintf("must be between %g and %g here, %g given", e.MinLon, e.MaxLon, lon)}
+		}
+	}
+	if s.maxHistory > 0 && ts.Before(s.now().Add(-s.maxHistory)) {
+		return &ErrInvalidRequest{Field: "timestamp", Message: fmt.Sprintf("must be at most %s ago", s.maxHistory)}
+	}
+	return nil
+}
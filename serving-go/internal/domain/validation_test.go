@@ -0,0 +1,98 @@
+package domain
+
+import (
+	"errors"
+	"math"
+	"testing"
+	"time"
+)
+
+func TestValidateRequest(t *testing.T) {
+	now := time.Date(2026, 2, 27, 4, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name      string
+		ts        time.Time
+		lat, lon  float32
+		vars      []string
+		wantField string
+	}{
+		{name: "valid", ts: now, lat: 52.5, lon: 13.4, vars: []string{"pm2p5", "pm10"}},
+		{name: "forecast within horizon", ts: now.Add(5 * 24 * time.Hour), lat: 52.5, lon: 13.4, vars: []string{"pm2p5"}},
+		{name: "lat too high", ts: now, lat: 90.5, lon: 13.4, vars: []string{"pm2p5"}, wantField: "lat"},
+		{name: "lon too low", ts: now, lat: 52.5, lon: -180.1, vars: []string{"pm2p5"}, wantField: "lon"},
+		{name: "lat NaN", ts: now, lat: float32(math.NaN()), lon: 13.4, vars: []string{"pm2p5"}, wantField: "lat"},
+		{name: "lon infinite", ts: now, lat: 52.5, lon: float32(math.Inf(1)), vars: []string{"pm2p5"}, wantField: "lon"},
+		{name: "lat negative infinite", ts: now, lat: float32(math.Inf(-1)), lon: 13.4, vars: []string{"pm2p5"}, wantField: "lat"},
+		{name: "zero timestamp", lat: 52.5, lon: 13.4, vars: []string{"pm2p5"}, wantField: "timestamp"},
+		{name: "beyond horizon", ts: now.Add(MaxFutureHorizon + time.Hour), lat: 52.5, lon: 13.4, vars: []string{"pm2p5"}, wantField: "timestamp"},
+		{name: "no variables", ts: now, lat: 52.5, lon: 13.4, wantField: "variables"},
+		{name: "duplicate variable", ts: now, lat: 52.5, lon: 13.4, vars: []string{"pm2p5", "pm10", "pm2p5"}, wantField: "variables"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateRequest(tt.ts, tt.lat, tt.lon, tt.vars, now)
+			if tt.wantField == "" {
+				if err != nil {
+					t.Errorf("expected no error, got %v", err)
+				}
+				return
+			}
+			invalid, ok := errors.AsType[*ErrInvalidRequest](err)
+			if !ok {
+				t.Fatalf("expected ErrInvalidRequest, got %v", err)
+			}
+			if invalid.Field != tt.wantField {
+				t.Errorf("expected field %q, got %q", tt.wantField, invalid.Field)
+			}
+		})
+	}
+}
+
+func TestService_CheckLimits(t *testing.T) {
+	now := time.Date(2026, 2, 27, 4, 0, 0, 0, time.UTC)
+	service := NewService(&mockGridRetriever{}, &mockLineageRetriever{},
+		WithExtent(BoundingBox{MinLat: 52, MinLon: 13, MaxLat: 53, MaxLon: 14}),
+		WithMaxHistory(72*time.Hour),
+	)
+	service.now = func() time.Time { return now }
+
+	tests := []struct {
+		name      string
+		ts        time.Time
+		lat, lon  float32
+		wantField string
+	}{
+		{name: "inside", ts: now.Add(-time.Hour), lat: 52.5, lon: 13.4},
+		{name: "on the edge", ts: now.Add(-72 * time.Hour), lat: 53, lon: 13},
+		{name: "forecast", ts: now.Add(48 * time.Hour), lat: 52.5, lon: 13.4},
+		{name: "north of extent", ts: now, lat: 53.1, lon: 13.4, wantField: "lat"},
+		{name: "west of extent", ts: now, lat: 52.5, lon: 12.9, wantField: "lon"},
+		{name: "NaN within extent", ts: now, lat: float32(math.NaN()), lon: 13.4, wantField: "lat"},
+		{name: "too old", ts: now.Add(-73 * time.Hour), lat: 52.5, lon: 13.4, wantField: "timestamp"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := service.GetVariables(t.Context(), tt.ts, tt.lat, tt.lon, []string{"pm2p5"})
+			invalid, ok := errors.AsType[*ErrInvalidRequest](err)
+			if tt.wantField == "" {
+				if ok {
+					t.Errorf("expected request within limits, got %v", err)
+				}
+				return
+			}
+			if !ok {
+				t.Fatalf("expected ErrInvalidRequest, got %v", err)
+			}
+			if invalid.Field != tt.wantField {
+				t.Errorf("expected field %q, got %q", tt.wantField, invalid.Field)
+			}
+		})
+	}
+
+	if _, err := service.GetSeries(t.Context(), "pm2p5", 52.5, 13.4, now.Add(-30*24*time.Hour), now); err == nil {
+		t.Error("expected series reaching past max history to fail")
+	}
+}
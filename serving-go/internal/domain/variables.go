@@ -0,0 +1,59 @@
+package domain
+
+import "strings"
+
+// variableAliases maps normalized spellings (see aliasKey) to canonical grid_data variable
+// names. Canonical names map to themselves so case variants like PM2P5 resolve too.
+var variableAliases = map[string]string{
+	"pm2p5":       "pm2p5",
+	"pm25":        "pm2p5",
+	"pm2.5":       "pm2p5",
+	"pm10":        "pm10",
+	"temperature": "temperature",
+	"temp":        "temperature",
+	"t2m":         "temperature",
+	"2t":          "temperature",
+}
+
+var aliasKeyReplacer = strings.NewReplacer("_", "", "-", "", " ", "")
+
+func aliasKey(name string) string {
+	return aliasKeyReplacer.Replace(strings.ToLower(name))
+}
+
+// CanonicalVariable resolves a known alias (pm25, PM2.5, pm_2_5 → pm2p5) to its canonical
+// name. Unknown names are returned unchanged.
+func CanonicalVariable(name string) string {
+	if canonical, ok := variableAliases[aliasKey(name)]; ok {
+		return canonical
+	}
+	return name
+}
+
+func canonicalVariables(vars []string) []string {
+	canonical := make([]string, len(vars))
+	for i, name := range vars {
+		canonical[i] = CanonicalVariable(name)
+	}
+	return canonical
+}
+
+// OtherVariable labels metrics of variables MetricVariable doesn't know.
+const OtherVariable = "other"
+
+// MetricVariable returns the canonical name of a variable known to the alias table,
+// DefaultContracts or DefaultDerivedVariables, and OtherVariable for anything else, so
+// request-supplied names can't grow metric label sets without bound.
+func MetricVariable(name string) string {
+	canonical := CanonicalVariable(name)
+	if _, ok := DefaultContracts[canonical]; ok {
+		return canonical
+	}
+	if _, ok := DefaultDerivedVariables[canonical]; ok {
+		return canonical
+	}
+	if alias, ok := variableAliases[canonical]; ok && alias == canonical {
+		return canonical
+	}
+	return OtherVariable
+}
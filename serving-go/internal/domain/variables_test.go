@@ -0,0 +1,46 @@
+package domain
+
+import "testing"
+
+func TestCanonicalVariable(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+	}{
+		{name: "pm2p5", want: "pm2p5"},
+		{name: "pm25", want: "pm2p5"},
+		{name: "PM2.5", want: "pm2p5"},
+		{name: "pm_2_5", want: "pm2p5"},
+		{name: "PM2P5", want: "pm2p5"},
+		{name: "PM10", want: "pm10"},
+		{name: "t2m", want: "temperature"},
+		{name: "no2", want: "no2"},
+		{name: "custom_var", want: "custom_var"},
+	}
+
+	for _, tt := range tests {
+		if got := CanonicalVariable(tt.name); got != tt.want {
+			t.Errorf("CanonicalVariable(%q) = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestMetricVariable(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+	}{
+		{name: "PM2.5", want: "pm2p5"},
+		{name: "temp", want: "temperature"},
+		{name: "humidity", want: "humidity"},
+		{name: "pm_coarse", want: "pm_coarse"},
+		{name: "no_such_variable_42", want: OtherVariable},
+		{name: "", want: OtherVariable},
+	}
+
+	for _, tt := range tests {
+		if got := MetricVariable(tt.name); got != tt.want {
+			t.Errorf("MetricVariable(%q) = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
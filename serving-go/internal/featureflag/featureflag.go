@@ -0,0 +1,177 @@
+// Package featureflag holds rollout switches for risky features. Each flag is on for a
+// percentage of keys, from 0 (off) to 100 (on for all); in between, a key's hash decides, so
+// a feature can be rolled out gradually. Values come from built-in defaults, then a JSON file
+// and the environment at startup, and can be overridden at runtime; overrides live in
+// memory and are lost on restart.
+package featureflag
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"maps"
+	"os"
+	"slices"
+	"sync"
+)
+
+var (
+	ErrUnknownFlag    = errors.New("unknown feature flag")
+	ErrInvalidPercent = errors.New("feature flag percent must be between 0 and 100")
+)
+
+// Off and On are the percents of a flag disabled or enabled for every key.
+const (
+	Off = 0
+	On  = 100
+)
+
+// State is one flag's effective value and where it comes from.
+type State struct {
+	Name    string
+	Percent int
+	// Default is the value configured at startup, which Clear restores.
+	Default    int
+	Overridden bool
+}
+
+// Set is a fixed set of named flags, safe for concurrent use.
+type Set struct {
+	mu        sync.RWMutex
+	defaults  map[string]int
+	overrides map[string]int
+}
+
+// New registers the known flags with their built-in defaults. Other names are rejected.
+func New(defaults map[string]bool) *Set {
+	s := &Set{defaults: make(map[string]int, len(defaults)), overrides: make(map[string]int)}
+	for name, enabled := range defaults {
+		s.defaults[name] = percent(enabled)
+	}
+	return s
+}
+
+// Configure replaces the startup defaults of the given flags. It fails with ErrUnknownFlag
+// or ErrInvalidPercent, changing nothing, if any name isn't registered or any percent is
+// out of range.
+func (s *Set) Configure(values map[string]int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for name, p := range values {
+		if err := s.check(name, p); err != nil {
+			return err
+		}
+	}
+	maps.Copy(s.defaults, values)
+	return nil
+}
+
+// Enabled reports whether the flag is on for key. Flags at neither Off nor On are on for
+// that share of keys, chosen by a hash of the flag name and key so each flag buckets keys
+// independently; an empty key only gets flags that are On. Unknown flags are off.
+func (s *Set) Enabled(name, key string) bool {
+	s.mu.RLock()
+	p, ok := s.overrides[name]
+	if !ok {
+		p = s.defaults[name]
+	}
+	s.mu.RUnlock()
+
+	switch {
+	case p >= On:
+		return true
+	case p <= Off || key == "":
+		return false
+	}
+	h := fnv.New32a()
+	h.Write([]byte(name))
+	h.Write([]byte{0})
+	h.Write([]byte(key))
+	return int(h.Sum32()%100) < p
+}
+
+// Override sets the flag's percent until Clear or restart.
+func (s *Set) Override(name string, p int) (State, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.check(name, p); err != nil {
+		return State{}, err
+	}
+	s.overrides[name] = p
+	return s.state(name), nil
+}
+
+// Clear drops the flag's runtime override, restoring its default.
+func (s *Set) Clear(name string) (State, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.defaults[name]; !ok {
+		return State{}, fmt.Errorf("%w %q", ErrUnknownFlag, name)
+	}
+	delete(s.overrides, name)
+	return s.state(name), nil
+}
+
+// List returns every flag, sorted by name.
+func (s *Set) List() []State {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	states := make([]State, 0, len(s.defaults))
+	for _, name := range slices.Sorted(maps.Keys(s.defaults)) {
+		states = append(states, s.state(name))
+	}
+	return states
+}
+
+func (s *Set) check(name string, p int) error {
+	if _, ok := s.defaults[name]; !ok {
+		return fmt.Errorf("%w %q", ErrUnknownFlag, name)
+	}
+	if p < Off || p > On {
+		return fmt.Errorf("%w: %s: %d given", ErrInvalidPercent, name, p)
+	}
+	return nil
+}
+
+func (s *Set) state(name string) State {
+	p, overridden := s.overrides[name]
+	if !overridden {
+		p = s.defaults[name]
+	}
+	return State{Name: name, Percent: p, Default: s.defaults[name], Overridden: overridden}
+}
+
+func percent(enabled bool) int {
+	if enabled {
+		return On
+	}
+	return Off
+}
+
+// LoadFile reads flag values from a JSON object of names to booleans or percents,
+// e.g. {"interpolation": false, "source_precedence": 25}.
+func LoadFile(path string) (map[string]int, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read feature flags: %w", err)
+	}
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("parse feature flags %s: %w", path, err)
+	}
+	values := make(map[string]int, len(raw))
+	for name, value := range raw {
+		var enabled bool
+		if err := json.Unmarshal(value, &enabled); err == nil {
+			values[name] = percent(enabled)
+			continue
+		}
+		var p int
+		if err := json.Unmarshal(value, &p); err != nil {
+			return nil, fmt.Errorf("parse feature flags %s: %s: expected a boolean or a percent, %s given", path, name, value)
+		}
+		values[name] = p
+	}
+	return values, nil
+}
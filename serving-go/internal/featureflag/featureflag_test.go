@@ -0,0 +1,116 @@
+package featureflag
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestSet_OverrideAndClear(t *testing.T) {
+	flags := New(map[string]bool{"interpolation": true, "source_precedence": true})
+	if err := flags.Configure(map[string]int{"source_precedence": Off}); err != nil {
+		t.Fatalf("Configure returned error: %v", err)
+	}
+	if !flags.Enabled("interpolation", "") || flags.Enabled("source_precedence", "req-1") || flags.Enabled("unknown", "req-1") {
+		t.Fatalf("unexpected startup values %+v", flags.List())
+	}
+
+	state, err := flags.Override("interpolation", Off)
+	if err != nil {
+		t.Fatalf("Override returned error: %v", err)
+	}
+	if want := (State{Name: "interpolation", Percent: Off, Default: On, Overridden: true}); state != want {
+		t.Errorf("expected %+v, got %+v", want, state)
+	}
+	if flags.Enabled("interpolation", "req-1") {
+		t.Error("expected the override to disable interpolation")
+	}
+
+	state, err = flags.Clear("interpolation")
+	if err != nil {
+		t.Fatalf("Clear returned error: %v", err)
+	}
+	if state.Percent != On || state.Overridden || !flags.Enabled("interpolation", "req-1") {
+		t.Errorf("expected Clear to restore the default, got %+v", state)
+	}
+}
+
+func TestSet_PartialRollout(t *testing.T) {
+	flags := New(map[string]bool{"interpolation": false, "source_precedence": false})
+	if err := flags.Configure(map[string]int{"interpolation": 25, "source_precedence": 25}); err != nil {
+		t.Fatalf("Configure returned error: %v", err)
+	}
+
+	var on, both int
+	for i := range 10000 {
+		key := fmt.Sprintf("req-%d", i)
+		interpolation := flags.Enabled("interpolation", key)
+		if interpolation != flags.Enabled("interpolation", key) {
+			t.Fatalf("expected key %s to stay in its bucket", key)
+		}
+		if interpolation {
+			on++
+			if flags.Enabled("source_precedence", key) {
+				both++
+			}
+		}
+	}
+	if on < 2300 || on > 2700 {
+		t.Errorf("expected about 25%% of keys enabled, got %d of 10000", on)
+	}
+	// Independent buckets put about a quarter of those in the other flag's rollout too.
+	if both < on/8 || both > on*3/8 {
+		t.Errorf("expected flags to bucket independently, %d of %d keys have both", both, on)
+	}
+	if flags.Enabled("interpolation", "") {
+		t.Error("expected a partial rollout to be off without a key")
+	}
+}
+
+func TestSet_RejectsUnknownFlagsAndInvalidPercents(t *testing.T) {
+	flags := New(map[string]bool{"interpolation": true})
+	if err := flags.Configure(map[string]int{"interpolation": Off, "typo": On}); !errors.Is(err, ErrUnknownFlag) {
+		t.Errorf("expected ErrUnknownFlag from Configure, got %v", err)
+	}
+	if err := flags.Configure(map[string]int{"interpolation": 101}); !errors.Is(err, ErrInvalidPercent) {
+		t.Errorf("expected ErrInvalidPercent from Configure, got %v", err)
+	}
+	if !flags.Enabled("interpolation", "") {
+		t.Error("expected a failed Configure to change nothing")
+	}
+	if _, err := flags.Override("typo", On); !errors.Is(err, ErrUnknownFlag) {
+		t.Errorf("expected ErrUnknownFlag from Override, got %v", err)
+	}
+	if _, err := flags.Override("interpolation", -1); !errors.Is(err, ErrInvalidPercent) {
+		t.Errorf("expected ErrInvalidPercent from Override, got %v", err)
+	}
+	if _, err := flags.Clear("typo"); !errors.Is(err, ErrUnknownFlag) {
+		t.Errorf("expected ErrUnknownFlag from Clear, got %v", err)
+	}
+}
+
+func TestLoadFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "flags.json")
+	if err := os.WriteFile(path, []byte(`{"interpolation": false, "source_precedence": 25}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	values, err := LoadFile(path)
+	if err != nil {
+		t.Fatalf("LoadFile returned error: %v", err)
+	}
+	if want := map[string]int{"interpolation": Off, "source_precedence": 25}; !maps.Equal(values, want) {
+		t.Errorf("expected %v, got %v", want, values)
+	}
+
+	for _, body := range []string{`{"interpolation": "off"}`, `{"interpolation": 12.5}`} {
+		if err := os.WriteFile(path, []byte(body), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := LoadFile(path); err == nil {
+			t.Errorf("expected an error for %s", body)
+		}
+	}
+}
@@ -0,0 +1,111 @@
+package grid
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"log/slog"
+	"os"
+
+	"github.com/ClickHouse/clickhouse-go/v2"
+
+	"github.com/kacper-wojtaszczyk/jackfruit/serving-go/internal/config"
+)
+
+// Options builds driver options from the service configuration.
+func Options(cfg *config.Config, logger *slog.Logger) (*clickhouse.Options, error) {
+	pool := cfg.ClickHousePool
+	options := &clickhouse.Options{
+		Addr: cfg.ClickHouseAddrs,
+		Auth: clickhouse.Auth{
+			Database: cfg.ClickHouseDatabase,
+			Username: cfg.ClickHouseUser,
+			Password: cfg.ClickHousePassword,
+		},
+		Logger: logger,
+		Settings: clickhouse.Settings{
+			"max_execution_time": int(cfg.ClickHouseMaxExecutionTime.Seconds()),
+		},
+		MaxOpenConns:    pool.MaxOpenConns,
+		MaxIdleConns:    pool.MaxIdleConns,
+		ConnMaxLifetime: pool.ConnMaxLifetime,
+		DialTimeout:     pool.DialTimeout,
+		ReadTimeout:     pool.ReadTimeout,
+	}
+	protocol, ok := protocols[cfg.ClickHouseProtocol]
+	if !ok {
+		return nil, fmt.Errorf("unknown protocol %q", cfg.ClickHouseProtocol)
+	}
+	options.Protocol = protocol
+	strategy, ok := connOpenStrategies[cfg.ClickHouseConnOpenStrategy]
+	if !ok {
+		return nil, fmt.Errorf("unknown connection open strategy %q", cfg.ClickHouseConnOpenStrategy)
+	}
+	options.ConnOpenStrategy = strategy
+	if cfg.ClickHouseTLS.Enabled {
+		tlsConfig, err := tlsConfig(cfg.ClickHouseTLS)
+		if err != nil {
+			return nil, fmt.Errorf("clickhouse tls: %w", err)
+		}
+		options.TLS = tlsConfig
+	}
+	if pool.Compression != "" {
+		method, ok := compressionMethods[pool.Compression]
+		if !ok {
+			return nil, fmt.Errorf("unknown compression method %q", pool.Compression)
+		}
+		options.Compression = &clickhouse.Compression{Method: method}
+	}
+
+	return options, nil
+}
+
+func tlsConfig(cfg config.ClickHouseTLS) (*tls.Config, error) {
+	tlsConfig := &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		ServerName:         cfg.ServerName,
+		InsecureSkipVerify: cfg.InsecureSkipVerify,
+	}
+	if cfg.CAFile != "" {
+		pem, err := os.ReadFile(cfg.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("read CA file: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("CA file %s contains no PEM certificates", cfg.CAFile)
+		}
+		tlsConfig.RootCAs = pool
+	}
+	if cfg.CertFile != "" {
+		cert, err := tls.LoadX509KeyPair(cfg.CertFile, cfg.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("load client certificate: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+	return tlsConfig, nil
+}
+
+// protocols maps config names to driver protocols. Over HTTP the driver API is unchanged,
+// but the server sends no progress or profile packets, so rows-read and result-size metrics stay at zero.
+var protocols = map[string]clickhouse.Protocol{
+	"native": clickhouse.Native,
+	"http":   clickhouse.HTTP,
+}
+
+var connOpenStrategies = map[string]clickhouse.ConnOpenStrategy{
+	"in_order":    clickhouse.ConnOpenInOrder,
+	"round_robin": clickhouse.ConnOpenRoundRobin,
+	"random":      clickhouse.ConnOpenRandom,
+}
+
+var compressionMethods = map[string]clickhouse.CompressionMethod{
+	"none":    clickhouse.CompressionNone,
+	"lz4":     clickhouse.CompressionLZ4,
+	"lz4hc":   clickhouse.CompressionLZ4HC,
+	"zstd":    clickhouse.CompressionZSTD,
+	"gzip":    clickhouse.CompressionGZIP,
+	"deflate": clickhouse.CompressionDeflate,
+	"br":      clickhouse.CompressionBrotli,
+}
@@ -0,0 +1,124 @@
+package grid
+
+import (
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/ClickHouse/clickhouse-go/v2"
+
+	"github.com/kacper-wojtaszczyk/jackfruit/serving-go/internal/config"
+)
+
+func testConfig(t *testing.T) *config.Config {
+	t.Helper()
+	cfg, err := config.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return cfg
+}
+
+func TestOptions_MultiHost(t *testing.T) {
+	cfg := testConfig(t)
+	cfg.ClickHouseAddrs = []string{"ch-1:9000", "ch-2:9000"}
+	cfg.ClickHouseConnOpenStrategy = "round_robin"
+
+	options, err := Options(cfg, slog.New(slog.DiscardHandler))
+	if err != nil {
+		t.Fatalf("Options returned error: %v", err)
+	}
+	if !slices.Equal(options.Addr, cfg.ClickHouseAddrs) {
+		t.Errorf("expected addrs %v, got %v", cfg.ClickHouseAddrs, options.Addr)
+	}
+	if options.ConnOpenStrategy != clickhouse.ConnOpenRoundRobin {
+		t.Errorf("expected round robin strategy, got %v", options.ConnOpenStrategy)
+	}
+}
+
+func TestOptions_MaxExecutionTime(t *testing.T) {
+	cfg := testConfig(t)
+	cfg.ClickHouseMaxExecutionTime = 30 * time.Second
+
+	options, err := Options(cfg, slog.New(slog.DiscardHandler))
+	if err != nil {
+		t.Fatalf("Options returned error: %v", err)
+	}
+	if got := options.Settings["max_execution_time"]; got != 30 {
+		t.Errorf("expected max_execution_time 30, got %v", got)
+	}
+}
+
+func TestOptions_Protocol(t *testing.T) {
+	cfg := testConfig(t)
+
+	options, err := Options(cfg, slog.New(slog.DiscardHandler))
+	if err != nil {
+		t.Fatalf("Options returned error: %v", err)
+	}
+	if options.Protocol != clickhouse.Native {
+		t.Errorf("expected native protocol by default, got %v", options.Protocol)
+	}
+
+	cfg.ClickHouseProtocol = "http"
+	if options, err = Options(cfg, slog.New(slog.DiscardHandler)); err != nil {
+		t.Fatalf("Options returned error: %v", err)
+	}
+	if options.Protocol != clickhouse.HTTP {
+		t.Errorf("expected http protocol, got %v", options.Protocol)
+	}
+}
+
+func TestOptions_Compression(t *testing.T) {
+	cfg := testConfig(t)
+	cfg.ClickHousePool.Compression = "zstd"
+
+	options, err := Options(cfg, slog.New(slog.DiscardHandler))
+	if err != nil {
+		t.Fatalf("Options returned error: %v", err)
+	}
+	if options.Compression == nil || options.Compression.Method != clickhouse.CompressionZSTD {
+		t.Errorf("expected zstd compression, got %+v", options.Compression)
+	}
+
+	cfg.ClickHousePool.Compression = "snappy"
+	if _, err := Options(cfg, slog.New(slog.DiscardHandler)); err == nil {
+		t.Error("expected error for unknown compression method")
+	}
+}
+
+func TestOptions_TLS(t *testing.T) {
+	cfg := testConfig(t)
+	if options, err := Options(cfg, slog.New(slog.DiscardHandler)); err != nil || options.TLS != nil {
+		t.Fatalf("expected plaintext by default, got TLS %+v, err %v", options.TLS, err)
+	}
+
+	cfg.ClickHouseTLS = config.ClickHouseTLS{Enabled: true, ServerName: "abc.clickhouse.cloud", InsecureSkipVerify: true}
+	options, err := Options(cfg, slog.New(slog.DiscardHandler))
+	if err != nil {
+		t.Fatalf("Options returned error: %v", err)
+	}
+	if options.TLS == nil {
+		t.Fatal("expected TLS config")
+	}
+	if options.TLS.ServerName != "abc.clickhouse.cloud" || !options.TLS.InsecureSkipVerify {
+		t.Errorf("expected server name and skip-verify to be applied, got %+v", options.TLS)
+	}
+
+	cfg.ClickHouseTLS.CAFile = filepath.Join(t.TempDir(), "missing.pem")
+	if _, err := Options(cfg, slog.New(slog.DiscardHandler)); err == nil {
+		t.Error("expected error for missing CA file")
+	}
+
+	notPEM := filepath.Join(t.TempDir(), "ca.pem")
+	if err := os.WriteFile(notPEM, []byte("not a certificate"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	cfg.ClickHouseTLS.CAFile = notPEM
+	if _, err := Options(cfg, slog.New(slog.DiscardHandler)); err == nil {
+		t.Error("expected error for CA file without certificates")
+	}
+}
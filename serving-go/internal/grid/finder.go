@@ -5,20 +5,104 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"log/slog"
 	"time"
 
 	"github.com/ClickHouse/clickhouse-go/v2"
 	"github.com/ClickHouse/clickhouse-go/v2/lib/driver"
+	"github.com/google/uuid"
 
 	"github.com/kacper-wojtaszczyk/jackfruit/serving-go/internal/domain"
 )
 
 type Finder struct {
-	conn driver.Conn
+	conn               driver.Conn
+	retry              RetryPolicy
+	latestFastPath     bool
+	geohashPrefilter   bool
+	auditLogSchema     bool
+	settings           QuerySettings
+	metrics            *Metrics
+	slowQueryLogger    *slog.Logger
+	slowQueryThreshold time.Duration
+	queryLogger        *slog.Logger
+	now                func() time.Time
 }
 
-func NewFinder(conn driver.Conn) *Finder {
-	return &Finder{conn: conn}
+type FinderOption func(*Finder)
+
+// WithRetryPolicy overrides DefaultRetryPolicy. Use MaxAttempts 1 to disable retries.
+func WithRetryPolicy(policy RetryPolicy) FinderOption {
+	return func(f *Finder) {
+		f.retry = policy
+	}
+}
+
+// WithLatestFastPath serves point lookups at or after now from the grid_latest materialized
+// view when the nearest cell's newest sample is the variable's newest timestamp and not after
+// the requested one, so it is what the full grid_data query would snap to. Other lookups use
+// the full query. Requires migration 0002_create_grid_latest.
+func WithLatestFastPath() FinderOption {
+	return func(f *Finder) {
+		f.latestFastPath = true
+	}
+}
+
+// WithGeohashPrefilter narrows nearest-cell searches to the 3x3 geohash neighbourhood of the
+// point before distance sorting, falling back to the full scan when it finds nothing. Exact
+// for grids finer than one cell height (0.18°); coarser grids may get a near-but-not-nearest
+// cell. Requires migration 0003_add_grid_geohash.
+func WithGeohashPrefilter() FinderOption {
+	return func(f *Finder) {
+		f.geohashPrefilter = true
+	}
+}
+
+// WithAuditLogSchema makes CheckSchema also verify audit_log, for servers whose audit store
+// writes and reads it over the finder's connection.
+func WithAuditLogSchema() FinderOption {
+	return func(f *Finder) {
+		f.auditLogSchema = true
+	}
+}
+
+// WithDefaultSettings sets the QuerySettings of every call, e.g. strict limits for a finder
+// that only serves point lookups. ContextWithSettings overrides them per call.
+func WithDefaultSettings(settings QuerySettings) FinderOption {
+	return func(f *Finder) {
+		f.settings = settings
+	}
+}
+
+// WithMetrics records duration, rows read and result size of every query attempt.
+func WithMetrics(metrics *Metrics) FinderOption {
+	return func(f *Finder) {
+		f.metrics = metrics
+	}
+}
+
+// WithSlowQueryLog logs every query attempt taking at least threshold, with its request id.
+func WithSlowQueryLog(logger *slog.Logger, threshold time.Duration) FinderOption {
+	return func(f *Finder) {
+		f.slowQueryLogger = logger
+		f.slowQueryThreshold = threshold
+	}
+}
+
+// WithQueryLog logs every query attempt at DEBUG, with the same stats as the slow query log.
+// It costs nothing beyond the stats callbacks while logger's level is above DEBUG.
+func WithQueryLog(logger *slog.Logger) FinderOption {
+	return func(f *Finder) {
+		f.queryLogger = logger
+	}
+}
+
+func NewFinder(conn driver.Conn, opts ...FinderOption) *Finder {
+	f := &Finder{conn: conn, retry: DefaultRetryPolicy, now: time.Now}
+	for _, opt := range opts {
+		opt(f)
+	}
+	return f
 }
 
 func (c *Finder) GetSample(
@@ -27,26 +111,48 @@ func (c *Finder) GetSample(
 	timestamp time.Time,
 	lat float32,
 	lon float32,
+) (*domain.GridSample, error) {
+	if c.latestFastPath {
+		latest, err := c.getLatestSamples(ctx, []string{variable}, timestamp, lat, lon)
+		if err != nil {
+			return nil, err
+		}
+		if sample := latest[variable]; sample != nil {
+			return sample, nil
+		}
+	}
+
+	if c.geohashPrefilter {
+		sample, err := c.querySample(ctx, "sample_nearby", nearbySampleQuery, variable, timestamp, lat, lon)
+		if !errors.Is(err, domain.ErrGridSampleNotFound) {
+			return sample, err
+		}
+	}
+
+	return c.querySample(ctx, "sample", sampleQuery, variable, timestamp, lat, lon)
+}
+
+func (c *Finder) querySample(
+	ctx context.Context,
+	name string,
+	query string,
+	variable string,
+	timestamp time.Time,
+	lat float32,
+	lon float32,
 ) (*domain.GridSample, error) {
 	var result domain.GridSample
-	err := c.conn.QueryRow(
-		ctx,
-		`
-        SELECT value, unit, lat, lon, catalog_id, timestamp
-        FROM grid_data FINAL
-        WHERE variable = @variable
-          AND timestamp = (
-            SELECT max(timestamp) FROM grid_data FINAL
-            WHERE variable = @variable AND timestamp <= @timestamp
-          )
-        ORDER BY (lat - @lat) * (lat - @lat) + (lon - @lon) * (lon - @lon)
-        LIMIT 1
-        `,
-		clickhouse.Named("variable", variable),
-		clickhouse.Named("timestamp", timestamp),
-		clickhouse.Named("lat", lat),
-		clickhouse.Named("lon", lon),
-	).Scan(&result.Value, &result.Unit, &result.Lat, &result.Lon, &result.CatalogID, &result.Timestamp)
+	err := c.run(ctx, name, func(ctx context.Context) error {
+		return c.conn.QueryRow(
+			ctx,
+			query,
+			clickhouse.Named(paramVariable, variable),
+			clickhouse.Named(paramTimestamp, timestamp),
+			clickhouse.Named(paramLat, lat),
+			clickhouse.Named(paramLon, lon),
+			clickhouse.Named(paramCells, geohashNeighborhood(lat, lon)),
+		).Scan(&result.Value, &result.Unit, &result.Lat, &result.Lon, &result.CatalogID, &result.Timestamp)
+	})
 
 	if errors.Is(err, sql.ErrNoRows) {
 		return nil, domain.ErrGridSampleNotFound
@@ -58,3 +164,554 @@ func (c *Finder) GetSample(
 
 	return &result, nil
 }
+
+func (c *Finder) GetSamples(
+	ctx context.Context,
+	variables []string,
+	timestamp time.Time,
+	lat float32,
+	lon float32,
+) (map[string]*domain.GridSample, error) {
+	results := make(map[string]*domain.GridSample, len(variables))
+	remaining := variables
+	if c.latestFastPath {
+		latest, err := c.getLatestSamples(ctx, variables, timestamp, lat, lon)
+		if err != nil {
+			return nil, err
+		}
+		remaining = nil
+		for _, variable := range variables {
+			if sample := latest[variable]; sample != nil {
+				results[variable] = sample
+			} else {
+				remaining = append(remaining, variable)
+			}
+		}
+		if len(remaining) == 0 {
+			return results, nil
+		}
+	}
+
+	if c.geohashPrefilter {
+		var err error
+		if remaining, err = c.collectSamples(ctx, "samples_nearby", nearbySamplesQuery, results, remaining, timestamp, lat, lon); err != nil {
+			return nil, err
+		}
+		if len(remaining) == 0 {
+			return results, nil
+		}
+	}
+
+	if _, err := c.collectSamples(ctx, "samples", samplesQuery, results, remaining, timestamp, lat, lon); err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
+
+// collectSamples adds the samples query finds to results and returns the variables it missed.
+func (c *Finder) collectSamples(
+	ctx context.Context,
+	name string,
+	query string,
+	results map[string]*domain.GridSample,
+	variables []string,
+	timestamp time.Time,
+	lat float32,
+	lon float32,
+) ([]string, error) {
+	var samples map[string]*domain.GridSample
+	err := c.run(ctx, name, func(ctx context.Context) error {
+		var err error
+		samples, err = c.queryVariableSamples(ctx, query, variables, timestamp, lat, lon)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var missing []string
+	for _, variable := range variables {
+		if sample := samples[variable]; sample != nil {
+			results[variable] = sample
+		} else {
+			missing = append(missing, variable)
+		}
+	}
+	return missing, nil
+}
+
+// getLatestSamples returns grid_latest samples that are usable for timestamp: the nearest
+// cell's newest sample, when that is the variable's newest timestamp and not after timestamp.
+// A cell missing from the newest load would otherwise serve an older value than grid_data
+// snaps to. Other variables are absent from the map. Timestamps before now are nearly always
+// before the newest load, so they skip the query and get an empty map.
+func (c *Finder) getLatestSamples(
+	ctx context.Context,
+	variables []string,
+	timestamp time.Time,
+	lat float32,
+	lon float32,
+) (map[string]*domain.GridSample, error) {
+	if timestamp.Before(c.now()) {
+		return nil, nil
+	}
+	latest := make(map[string]*domain.GridSample, len(variables))
+	err := c.run(ctx, "latest_samples", func(ctx context.Context) error {
+		clear(latest)
+		rows, err := c.conn.Query(
+			ctx,
+			latestSamplesQuery,
+			clickhouse.Named(paramVariables, variables),
+			clickhouse.Named(paramLat, lat),
+			clickhouse.Named(paramLon, lon),
+		)
+		if err != nil {
+			return fmt.Errorf("query clickhouse: %w", err)
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			var variable string
+			var sample domain.GridSample
+			var newest time.Time
+			if err := rows.Scan(&variable, &sample.Value, &sample.Unit, &sample.Lat, &sample.Lon, &sample.CatalogID, &sample.Timestamp, &newest); err != nil {
+				return fmt.Errorf("scan clickhouse row: %w", err)
+			}
+			if sample.Timestamp.Equal(newest) && !sample.Timestamp.After(timestamp) {
+				latest[variable] = &sample
+			}
+		}
+		if err := rows.Err(); err != nil {
+			return fmt.Errorf("iterate clickhouse rows: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return latest, nil
+}
+
+// queryVariableSamples runs a query selecting (variable, value, unit, lat, lon, catalog_id, timestamp).
+func (c *Finder) queryVariableSamples(
+	ctx context.Context,
+	query string,
+	variables []string,
+	timestamp time.Time,
+	lat float32,
+	lon float32,
+) (map[string]*domain.GridSample, error) {
+	rows, err := c.conn.Query(
+		ctx,
+		query,
+		clickhouse.Named(paramVariables, variables),
+		clickhouse.Named(paramTimestamp, timestamp),
+		clickhouse.Named(paramLat, lat),
+		clickhouse.Named(paramLon, lon),
+		clickhouse.Named(paramCells, geohashNeighborhood(lat, lon)),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("query clickhouse: %w", err)
+	}
+	defer rows.Close()
+
+	results := make(map[string]*domain.GridSample, len(variables))
+	for rows.Next() {
+		var variable string
+		var result domain.GridSample
+		if err := rows.Scan(&variable, &result.Value, &result.Unit, &result.Lat, &result.Lon, &result.CatalogID, &result.Timestamp); err != nil {
+			return nil, fmt.Errorf("scan clickhouse row: %w", err)
+		}
+		results[variable] = &result
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate clickhouse rows: %w", err)
+	}
+
+	return results, nil
+}
+
+// GetCandidates returns, per variable, the nearest sample of every catalog entry with data in
+// [timestamp-lookback, timestamp], each at that entry's latest timestamp. Variables without
+// any are absent from the map.
+func (c *Finder) GetCandidates(
+	ctx context.Context,
+	variables []string,
+	timestamp time.Time,
+	lat float32,
+	lon float32,
+	lookback time.Duration,
+) (map[string][]domain.GridSample, error) {
+	results := make(map[string][]domain.GridSample, len(variables))
+	err := c.run(ctx, "candidates", func(ctx context.Context) error {
+		clear(results)
+		rows, err := c.conn.Query(
+			ctx,
+			candidatesQuery,
+			clickhouse.Named(paramVariables, variables),
+			clickhouse.Named(paramFrom, timestamp.Add(-lookback)),
+			clickhouse.Named(paramTimestamp, timestamp),
+			clickhouse.Named(paramLat, lat),
+			clickhouse.Named(paramLon, lon),
+		)
+		if err != nil {
+			return fmt.Errorf("query clickhouse: %w", err)
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			var variable string
+			var result domain.GridSample
+			if err := rows.Scan(&variable, &result.Value, &result.Unit, &result.Lat, &result.Lon, &result.CatalogID, &result.Timestamp); err != nil {
+				return fmt.Errorf("scan clickhouse row: %w", err)
+			}
+			results[variable] = append(results[variable], result)
+		}
+		if err := rows.Err(); err != nil {
+			return fmt.Errorf("iterate clickhouse rows: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
+
+// GetSeries returns every sample of variable in [from, to] at the grid cell nearest
+// to (lat, lon), ordered by timestamp. The cell is chosen at the latest timestamp in range.
+func (c *Finder) GetSeries(
+	ctx context.Context,
+	variable string,
+	lat float32,
+	lon float32,
+	from time.Time,
+	to time.Time,
+) ([]domain.GridSample, error) {
+	var results []domain.GridSample
+	if c.geohashPrefilter {
+		err := c.run(ctx, "series_nearby", func(ctx context.Context) error {
+			var err error
+			results, err = c.querySeries(ctx, nearbySeriesQuery, variable, lat, lon, from, to)
+			return err
+		})
+		if err != nil {
+			return nil, err
+		}
+		if len(results) > 0 {
+			return results, nil
+		}
+	}
+
+	err := c.run(ctx, "series", func(ctx context.Context) error {
+		var err error
+		results, err = c.querySeries(ctx, seriesQuery, variable, lat, lon, from, to)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(results) == 0 {
+		return nil, domain.ErrGridSampleNotFound
+	}
+
+	return results, nil
+}
+
+func (c *Finder) querySeries(
+	ctx context.Context,
+	query string,
+	variable string,
+	lat float32,
+	lon float32,
+	from time.Time,
+	to time.Time,
+) ([]domain.GridSample, error) {
+	rows, err := c.conn.Query(
+		ctx,
+		query,
+		clickhouse.Named(paramVariable, variable),
+		clickhouse.Named(paramFrom, from),
+		clickhouse.Named(paramTo, to),
+		clickhouse.Named(paramLat, lat),
+		clickhouse.Named(paramLon, lon),
+		clickhouse.Named(paramCells, geohashNeighborhood(lat, lon)),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("query clickhouse: %w", err)
+	}
+
+	return scanSamples(rows)
+}
+
+// GetGrid returns all cells of variable inside bbox at the latest timestamp at or before
+// timestamp with data inside bbox, ordered by (lat, lon). A stride of n keeps every n-th distinct latitude and
+// longitude, thinning the grid for overview tiles; 1 returns every cell.
+func (c *Finder) GetGrid(
+	ctx context.Context,
+	variable string,
+	timestamp time.Time,
+	bbox domain.BoundingBox,
+	stride int,
+) ([]domain.GridSample, error) {
+	if stride < 1 {
+		return nil, fmt.Errorf("stride must be at least 1, %d given", stride)
+	}
+
+	var results []domain.GridSample
+	err := c.run(ctx, "grid", func(ctx context.Context) error {
+		var err error
+		results, err = c.queryGrid(ctx, variable, timestamp, bbox, stride)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(results) == 0 {
+		return nil, domain.ErrGridSampleNotFound
+	}
+
+	return results, nil
+}
+
+func (c *Finder) queryGrid(
+	ctx context.Context,
+	variable string,
+	timestamp time.Time,
+	bbox domain.BoundingBox,
+	stride int,
+) ([]domain.GridSample, error) {
+	rows, err := c.conn.Query(
+		ctx,
+		gridQuery,
+		clickhouse.Named(paramVariable, variable),
+		clickhouse.Named(paramTimestamp, timestamp),
+		clickhouse.Named(paramMinLat, bbox.MinLat),
+		clickhouse.Named(paramMaxLat, bbox.MaxLat),
+		clickhouse.Named(paramMinLon, bbox.MinLon),
+		clickhouse.Named(paramMaxLon, bbox.MaxLon),
+		clickhouse.Named(paramStride, stride),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("query clickhouse: %w", err)
+	}
+
+	return scanSamples(rows)
+}
+
+// scanSamples reads rows selected as (value, unit, lat, lon, catalog_id, timestamp).
+func scanSamples(rows driver.Rows) ([]domain.GridSample, error) {
+	defer rows.Close()
+
+	var results []domain.GridSample
+	for rows.Next() {
+		var result domain.GridSample
+		if err := rows.Scan(&result.Value, &result.Unit, &result.Lat, &result.Lon, &result.CatalogID, &result.Timestamp); err != nil {
+			return nil, fmt.Errorf("scan clickhouse row: %w", err)
+		}
+		results = append(results, result)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate clickhouse rows: %w", err)
+	}
+
+	return results, nil
+}
+
+// run executes an idempotent read under the call's settings and the retry policy,
+// observing each attempt.
+func (c *Finder) run(ctx context.Context, name string, query func(ctx context.Context) error) error {
+	ctx, cancel := c.settings.merge(settingsFromContext(ctx)).apply(ctx)
+	defer cancel()
+	return c.retry.retry(ctx, func(ctx context.Context) error {
+		return c.observe(ctx, name, query)
+	})
+}
+
+// GetCoverage returns per-variable coverage ordered by variable, for all variables when none
+// are given. Requested variables without data are absent from the result.
+func (c *Finder) GetCoverage(ctx context.Context, variables ...string) ([]domain.Coverage, error) {
+	query, args := coverageQuery, []any(nil)
+	if len(variables) > 0 {
+		query, args = variableCoverageQuery, []any{clickhouse.Named(paramVariables, variables)}
+	}
+
+	var results []domain.Coverage
+	err := c.run(ctx, "coverage", func(ctx context.Context) error {
+		var err error
+		results, err = c.queryCoverage(ctx, query, args)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
+
+func (c *Finder) queryCoverage(ctx context.Context, query string, args []any) ([]domain.Coverage, error) {
+	rows, err := c.conn.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("query clickhouse: %w", err)
+	}
+	defer rows.Close()
+
+	var results []domain.Coverage
+	for rows.Next() {
+		var result domain.Coverage
+		if err := rows.Scan(
+			&result.Variable,
+			&result.FirstTimestamp,
+			&result.LastTimestamp,
+			&result.Extent.MinLat,
+			&result.Extent.MinLon,
+			&result.Extent.MaxLat,
+			&result.Extent.MaxLon,
+			&result.Rows,
+		); err != nil {
+			return nil, fmt.Errorf("scan clickhouse row: %w", err)
+		}
+		results = append(results, result)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate clickhouse rows: %w", err)
+	}
+
+	return results, nil
+}
+
+// GetLatestTimestamps returns the newest timestamp per variable. It reads grid_latest,
+// which grid_data deletes don't reach; retention only removes old data, so the newest
+// timestamps still hold.
+func (c *Finder) GetLatestTimestamps(ctx context.Context) (map[string]time.Time, error) {
+	latest := make(map[string]time.Time)
+	err := c.run(ctx, "latest_timestamps", func(ctx context.Context) error {
+		clear(latest)
+		rows, err := c.conn.Query(ctx, latestTimestampsQuery)
+		if err != nil {
+			return fmt.Errorf("query clickhouse: %w", err)
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			var variable string
+			var ts time.Time
+			if err := rows.Scan(&variable, &ts); err != nil {
+				return fmt.Errorf("scan clickhouse row: %w", err)
+			}
+			latest[variable] = ts
+		}
+		if err := rows.Err(); err != nil {
+			return fmt.Errorf("iterate clickhouse rows: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return latest, nil
+}
+
+// PingTimeout bounds Ping when the caller's context has no earlier deadline.
+const PingTimeout = 2 * time.Second
+
+// CountRows returns the number of grid_data rows per catalog entry, looked up by the entry's
+// variable and timestamp; entries without rows are absent from the map.
+func (c *Finder) CountRows(ctx context.Context, entries []domain.CatalogEntry) (map[uuid.UUID]uint64, error) {
+	catalogIDs := make([]uuid.UUID, len(entries))
+	keys := make([]clickhouse.GroupSet, 0, len(entries))
+	seen := make(map[entryKey]bool, len(entries))
+	for i, entry := range entries {
+		catalogIDs[i] = entry.ID
+		key := entryKey{entry.Variable, entry.Timestamp.UTC()}
+		if !seen[key] {
+			seen[key] = true
+			keys = append(keys, clickhouse.GroupSet{Value: []any{key.variable, key.timestamp}})
+		}
+	}
+
+	counts := make(map[uuid.UUID]uint64, len(entries))
+	err := c.run(ctx, "catalog_rows", func(ctx context.Context) error {
+		clear(counts)
+		rows, err := c.conn.Query(ctx, catalogRowsQuery,
+			clickhouse.Named(paramEntryKeys, keys),
+			clickhouse.Named(paramCatalogIDs, catalogIDs),
+		)
+		if err != nil {
+			return fmt.Errorf("query clickhouse: %w", err)
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			var catalogID uuid.UUID
+			var count uint64
+			if err := rows.Scan(&catalogID, &count); err != nil {
+				return fmt.Errorf("scan clickhouse row: %w", err)
+			}
+			counts[catalogID] = count
+		}
+		if err := rows.Err(); err != nil {
+			return fmt.Errorf("iterate clickhouse rows: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return counts, nil
+}
+
+type entryKey struct {
+	variable  string
+	timestamp time.Time
+}
+
+// GetQuality summarises the values of one catalog entry; an entry without rows yields zero stats.
+func (c *Finder) GetQuality(ctx context.Context, entry domain.CatalogEntry) (*domain.QualityStats, error) {
+	var stats domain.QualityStats
+	var mean, latStep, lonStep float64
+	err := c.run(ctx, "quality", func(ctx context.Context) error {
+		err := c.conn.QueryRow(ctx, qualityQuery,
+			clickhouse.Named(paramVariable, entry.Variable),
+			clickhouse.Named(paramTimestamp, entry.Timestamp),
+			clickhouse.Named(paramCatalogID, entry.ID),
+		).Scan(
+			&stats.Rows, &stats.ExpectedRows, &stats.NaNRows, &stats.ZeroRows, &stats.Min, &stats.Max, &mean,
+			&stats.Units, &latStep, &lonStep,
+		)
+		if err != nil {
+			return fmt.Errorf("query clickhouse: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	// avgIf over no non-NaN rows is NaN, which has no JSON encoding.
+	if stats.NaNRows < stats.Rows {
+		stats.Mean = float32(mean)
+	}
+	stats.LatStep, stats.LonStep = float32(latStep), float32(lonStep)
+
+	return &stats, nil
+}
+
+// Ping checks that ClickHouse is reachable over the finder's connection pool.
+func (c *Finder) Ping(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, PingTimeout)
+	defer cancel()
+	if err := c.conn.Ping(ctx); err != nil {
+		return fmt.Errorf("ping clickhouse: %w", err)
+	}
+	return nil
+}
+
+var (
+	_ domain.GridRetriever = (*Finder)(nil)
+	_ domain.GridInventory = (*Finder)(nil)
+)
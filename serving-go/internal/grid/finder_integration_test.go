@@ -1,12 +1,17 @@
 package grid_test
 
 import (
+	"context"
 	"errors"
 	"testing"
 	"time"
 
+	"github.com/ClickHouse/clickhouse-go/v2"
+	"github.com/google/uuid"
+
 	"github.com/kacper-wojtaszczyk/jackfruit/serving-go/internal/domain"
 	"github.com/kacper-wojtaszczyk/jackfruit/serving-go/internal/grid"
+	"github.com/kacper-wojtaszczyk/jackfruit/serving-go/internal/migrate"
 	"github.com/kacper-wojtaszczyk/jackfruit/serving-go/internal/testutil"
 )
 
@@ -65,3 +70,535 @@ func TestGetSampleNotFound(t *testing.T) {
 		t.Errorf("expected ErrGridSampleNotFound, got %v", err)
 	}
 }
+
+func TestGetSamples(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test, requires ClickHouse")
+	}
+
+	ctx := t.Context()
+	rawConn := testutil.NewRawConn(t)
+
+	timestamp := time.Now().UTC().Truncate(time.Second)
+	lat := float32(55.05)
+	lon := float32(106.15)
+
+	pm25CatalogID := testutil.InsertGridRow(t, rawConn, "pm2p5_batch", float32(3.05), "µg/m³", timestamp, lat, lon)
+	testutil.InsertGridRow(t, rawConn, "pm2p5_batch", float32(9.99), "µg/m³", timestamp, lat+1, lon+1)
+	pm10CatalogID := testutil.InsertGridRow(t, rawConn, "pm10_batch", float32(7.5), "µg/m³", timestamp.Add(-time.Hour), lat, lon)
+
+	samples, err := grid.NewFinder(rawConn).GetSamples(
+		ctx,
+		[]string{"pm2p5_batch", "pm10_batch", "nonexistent_variable"},
+		timestamp.Add(30*time.Minute),
+		lat+0.1,
+		lon+0.1,
+	)
+	if err != nil {
+		t.Fatalf("GetSamples returned error: %v", err)
+	}
+
+	if len(samples) != 2 {
+		t.Fatalf("expected 2 samples, got %d: %v", len(samples), samples)
+	}
+	if _, ok := samples["nonexistent_variable"]; ok {
+		t.Error("expected nonexistent_variable to be absent")
+	}
+
+	pm25 := samples["pm2p5_batch"]
+	if pm25 == nil {
+		t.Fatal("expected pm2p5_batch sample")
+	}
+	if pm25.Value != 3.05 || pm25.CatalogID != pm25CatalogID {
+		t.Errorf("expected nearest pm2p5_batch cell (3.05, %v), got (%v, %v)", pm25CatalogID, pm25.Value, pm25.CatalogID)
+	}
+	if pm25.Lat != lat || pm25.Lon != lon {
+		t.Errorf("expected coords (%v, %v), got (%v, %v)", lat, lon, pm25.Lat, pm25.Lon)
+	}
+
+	pm10 := samples["pm10_batch"]
+	if pm10 == nil {
+		t.Fatal("expected pm10_batch sample")
+	}
+	if !pm10.Timestamp.Equal(timestamp.Add(-time.Hour)) {
+		t.Errorf("expected pm10_batch timestamp %v, got %v", timestamp.Add(-time.Hour), pm10.Timestamp)
+	}
+	if pm10.CatalogID != pm10CatalogID {
+		t.Errorf("expected catalogID %v, got %v", pm10CatalogID, pm10.CatalogID)
+	}
+}
+
+func TestGetSeries(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test, requires ClickHouse")
+	}
+
+	ctx := t.Context()
+	rawConn := testutil.NewRawConn(t)
+
+	variable := "pm2p5_series"
+	start := time.Now().UTC().Truncate(time.Hour).Add(-3 * time.Hour)
+	lat := float32(52.5)
+	lon := float32(13.4)
+
+	for i := range 4 {
+		testutil.InsertGridRow(t, rawConn, variable, float32(i), "µg/m³", start.Add(time.Duration(i)*time.Hour), lat, lon)
+		// A farther cell at every timestamp must not leak into the series.
+		testutil.InsertGridRow(t, rawConn, variable, float32(100+i), "µg/m³", start.Add(time.Duration(i)*time.Hour), lat+1, lon+1)
+	}
+
+	series, err := grid.NewFinder(rawConn).GetSeries(ctx, variable, lat+0.1, lon+0.1, start.Add(time.Hour), start.Add(3*time.Hour))
+	if err != nil {
+		t.Fatalf("GetSeries returned error: %v", err)
+	}
+
+	if len(series) != 3 {
+		t.Fatalf("expected 3 samples, got %d: %v", len(series), series)
+	}
+	for i, sample := range series {
+		wantTimestamp := start.Add(time.Duration(i+1) * time.Hour)
+		if !sample.Timestamp.Equal(wantTimestamp) {
+			t.Errorf("sample %d: expected timestamp %v, got %v", i, wantTimestamp, sample.Timestamp)
+		}
+		if sample.Value != float32(i+1) {
+			t.Errorf("sample %d: expected value %v, got %v", i, i+1, sample.Value)
+		}
+		if sample.Lat != lat || sample.Lon != lon {
+			t.Errorf("sample %d: expected coords (%v, %v), got (%v, %v)", i, lat, lon, sample.Lat, sample.Lon)
+		}
+	}
+}
+
+func TestGetSeriesNotFound(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test, requires ClickHouse")
+	}
+
+	now := time.Now()
+	_, err := grid.NewFinder(testutil.NewRawConn(t)).GetSeries(t.Context(), "nonexistent_variable", 0, 0, now.Add(-time.Hour), now)
+	if !errors.Is(err, domain.ErrGridSampleNotFound) {
+		t.Errorf("expected ErrGridSampleNotFound, got %v", err)
+	}
+}
+
+func TestGetGrid(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test, requires ClickHouse")
+	}
+
+	ctx := t.Context()
+	rawConn := testutil.NewRawConn(t)
+
+	variable := "pm2p5_grid"
+	timestamp := time.Now().UTC().Truncate(time.Second)
+	// 4x4 grid at 0.1° resolution starting at (50.0, 10.0).
+	for i := range 4 {
+		for j := range 4 {
+			lat := 50.0 + float32(i)*0.1
+			lon := 10.0 + float32(j)*0.1
+			testutil.InsertGridRow(t, rawConn, variable, float32(i*4+j), "µg/m³", timestamp, lat, lon)
+		}
+	}
+	finder := grid.NewFinder(rawConn)
+
+	t.Run("bbox subset", func(t *testing.T) {
+		bbox := domain.BoundingBox{MinLat: 50.05, MinLon: 10.05, MaxLat: 50.25, MaxLon: 10.25}
+		cells, err := finder.GetGrid(ctx, variable, timestamp.Add(30*time.Minute), bbox, 1)
+		if err != nil {
+			t.Fatalf("GetGrid returned error: %v", err)
+		}
+		if len(cells) != 4 {
+			t.Fatalf("expected 4 cells inside bbox, got %d: %v", len(cells), cells)
+		}
+		for _, cell := range cells {
+			if cell.Lat < bbox.MinLat || cell.Lat > bbox.MaxLat || cell.Lon < bbox.MinLon || cell.Lon > bbox.MaxLon {
+				t.Errorf("cell (%v, %v) outside bbox %+v", cell.Lat, cell.Lon, bbox)
+			}
+		}
+	})
+
+	t.Run("stride", func(t *testing.T) {
+		bbox := domain.BoundingBox{MinLat: 49, MinLon: 9, MaxLat: 51, MaxLon: 11}
+		cells, err := finder.GetGrid(ctx, variable, timestamp, bbox, 2)
+		if err != nil {
+			t.Fatalf("GetGrid returned error: %v", err)
+		}
+		if len(cells) != 4 {
+			t.Fatalf("expected 4 cells with stride 2, got %d: %v", len(cells), cells)
+		}
+		wantValues := []float32{0, 2, 8, 10}
+		for i, cell := range cells {
+			if cell.Value != wantValues[i] {
+				t.Errorf("cell %d: expected value %v, got %v", i, wantValues[i], cell.Value)
+			}
+		}
+	})
+
+	t.Run("empty bbox", func(t *testing.T) {
+		bbox := domain.BoundingBox{MinLat: -10, MinLon: -10, MaxLat: -5, MaxLon: -5}
+		_, err := finder.GetGrid(ctx, variable, timestamp, bbox, 1)
+		if !errors.Is(err, domain.ErrGridSampleNotFound) {
+			t.Errorf("expected ErrGridSampleNotFound, got %v", err)
+		}
+	})
+
+	t.Run("invalid stride", func(t *testing.T) {
+		bbox := domain.BoundingBox{MinLat: 49, MinLon: 9, MaxLat: 51, MaxLon: 11}
+		if _, err := finder.GetGrid(ctx, variable, timestamp, bbox, 0); err == nil {
+			t.Error("expected error for stride 0")
+		}
+	})
+
+	t.Run("newer timestamp outside bbox", func(t *testing.T) {
+		partialVariable := "pm10_grid"
+		catalogID := testutil.InsertGridRow(t, rawConn, partialVariable, float32(1), "µg/m³", timestamp, 50.0, 10.0)
+		testutil.InsertGridRow(t, rawConn, partialVariable, float32(2), "µg/m³", timestamp.Add(time.Hour), 60.0, 20.0)
+
+		bbox := domain.BoundingBox{MinLat: 49, MinLon: 9, MaxLat: 51, MaxLon: 11}
+		cells, err := finder.GetGrid(ctx, partialVariable, timestamp.Add(2*time.Hour), bbox, 1)
+		if err != nil {
+			t.Fatalf("GetGrid returned error: %v", err)
+		}
+		if len(cells) != 1 || cells[0].CatalogID != catalogID {
+			t.Errorf("expected the older cell %v inside bbox, got %+v", catalogID, cells)
+		}
+	})
+}
+
+func TestGetCoverage(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test, requires ClickHouse")
+	}
+
+	ctx := t.Context()
+	rawConn := testutil.NewRawConn(t)
+
+	variable := "pm2p5_coverage"
+	start := time.Now().UTC().Truncate(time.Hour).Add(-2 * time.Hour)
+	testutil.InsertGridRow(t, rawConn, variable, 1, "µg/m³", start, 50, 10)
+	testutil.InsertGridRow(t, rawConn, variable, 2, "µg/m³", start.Add(time.Hour), 52, 14)
+	testutil.InsertGridRow(t, rawConn, variable, 3, "µg/m³", start.Add(2*time.Hour), 51, 12)
+
+	coverage, err := grid.NewFinder(rawConn).GetCoverage(ctx, variable, "missing_coverage")
+	if err != nil {
+		t.Fatalf("GetCoverage returned error: %v", err)
+	}
+
+	if len(coverage) != 1 {
+		t.Fatalf("expected coverage for 1 variable, got %d: %v", len(coverage), coverage)
+	}
+	got := coverage[0]
+	want := domain.Coverage{
+		Variable:       variable,
+		FirstTimestamp: start,
+		LastTimestamp:  start.Add(2 * time.Hour),
+		Extent:         domain.BoundingBox{MinLat: 50, MinLon: 10, MaxLat: 52, MaxLon: 14},
+		Rows:           3,
+	}
+	if got.Variable != want.Variable || !got.FirstTimestamp.Equal(want.FirstTimestamp) ||
+		!got.LastTimestamp.Equal(want.LastTimestamp) || got.Extent != want.Extent || got.Rows != want.Rows {
+		t.Errorf("expected %+v, got %+v", want, got)
+	}
+}
+
+func TestCountRows(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test, requires ClickHouse")
+	}
+
+	ctx := t.Context()
+	rawConn := testutil.NewRawConn(t)
+
+	variable := "pm2p5_rows"
+	timestamp := time.Now().UTC().Truncate(time.Hour)
+	loaded := testutil.InsertGridRow(t, rawConn, variable, 1, "µg/m³", timestamp, 50, 10)
+	other := testutil.InsertGridRow(t, rawConn, variable, 2, "µg/m³", timestamp.Add(time.Hour), 50, 10)
+
+	counts, err := grid.NewFinder(rawConn).CountRows(ctx, []domain.CatalogEntry{
+		{ID: loaded, Variable: variable, Timestamp: timestamp},
+		{ID: other, Variable: variable, Timestamp: timestamp.Add(time.Hour)},
+		{ID: uuid.New(), Variable: variable, Timestamp: timestamp},
+	})
+	if err != nil {
+		t.Fatalf("CountRows returned error: %v", err)
+	}
+	if len(counts) != 2 || counts[loaded] != 1 || counts[other] != 1 {
+		t.Errorf("expected one row for each loaded entry, got %v", counts)
+	}
+}
+
+func TestGetLatestTimestamps(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test, requires ClickHouse")
+	}
+
+	ctx := t.Context()
+	rawConn := testutil.NewRawConn(t)
+
+	variable := "pm2p5_latest_timestamps"
+	start := time.Now().UTC().Truncate(time.Hour).Add(-2 * time.Hour)
+	testutil.InsertGridRow(t, rawConn, variable, 1, "µg/m³", start, 50, 10)
+	testutil.InsertGridRow(t, rawConn, variable, 2, "µg/m³", start.Add(time.Hour), 51, 10)
+
+	latest, err := grid.NewFinder(rawConn).GetLatestTimestamps(ctx)
+	if err != nil {
+		t.Fatalf("GetLatestTimestamps returned error: %v", err)
+	}
+	if got := latest[variable]; !got.Equal(start.Add(time.Hour)) {
+		t.Errorf("expected latest timestamp %s, got %s", start.Add(time.Hour), got)
+	}
+}
+
+func TestGetCandidates(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test, requires ClickHouse")
+	}
+
+	ctx := t.Context()
+	rawConn := testutil.NewRawConn(t)
+
+	variable := "pm2p5_candidates"
+	start := time.Now().UTC().Truncate(time.Hour).Add(-3 * time.Hour)
+	older := testutil.InsertGridRow(t, rawConn, variable, 1, "µg/m³", start, 52.5, 13.4)
+	newer := testutil.InsertGridRow(t, rawConn, variable, 2, "µg/m³", start.Add(time.Hour), 52.5, 13.4)
+	testutil.InsertGridRow(t, rawConn, variable, 3, "µg/m³", start.Add(time.Hour), 40, 0)
+
+	candidates, err := grid.NewFinder(rawConn).GetCandidates(ctx, []string{variable}, start.Add(90*time.Minute), 52.5, 13.4, 2*time.Hour)
+	if err != nil {
+		t.Fatalf("GetCandidates returned error: %v", err)
+	}
+
+	got := candidates[variable]
+	if len(got) != 3 {
+		t.Fatalf("expected one candidate per catalog entry, got %d: %+v", len(got), got)
+	}
+	values := make(map[uuid.UUID]float32)
+	for _, candidate := range got {
+		values[candidate.CatalogID] = candidate.Value
+	}
+	if values[older] != 1 || values[newer] != 2 {
+		t.Errorf("expected each entry's own nearest sample, got %v", values)
+	}
+
+	candidates, err = grid.NewFinder(rawConn).GetCandidates(ctx, []string{variable}, start.Add(90*time.Minute), 52.5, 13.4, time.Hour)
+	if err != nil {
+		t.Fatalf("GetCandidates returned error: %v", err)
+	}
+	if len(candidates[variable]) != 2 {
+		t.Errorf("expected lookback to exclude the older entry, got %+v", candidates[variable])
+	}
+}
+
+func TestPing(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test, requires ClickHouse")
+	}
+
+	if err := grid.NewFinder(testutil.NewRawConn(t)).Ping(t.Context()); err != nil {
+		t.Fatalf("Ping returned error: %v", err)
+	}
+}
+
+func TestInsertGridValues(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test, requires ClickHouse")
+	}
+
+	ctx := t.Context()
+	rawConn := testutil.NewRawConn(t)
+
+	catalogID, err := uuid.NewV7()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		_ = rawConn.Exec(context.Background(), "ALTER TABLE grid_data DELETE WHERE catalog_id = @catalog_id",
+			clickhouse.Named("catalog_id", catalogID))
+	})
+
+	timestamp := time.Now().UTC().Truncate(time.Second)
+	values := make([]domain.GridValue, 5)
+	for i := range values {
+		values[i] = domain.GridValue{
+			Variable:  "pm2p5_insert",
+			Timestamp: timestamp,
+			Lat:       40 + float32(i),
+			Lon:       10,
+			Value:     float32(i),
+			Unit:      "µg/m³",
+			CatalogID: catalogID,
+		}
+	}
+
+	if err := grid.NewWriter(rawConn, grid.WithBatchSize(2)).InsertGridValues(ctx, values); err != nil {
+		t.Fatalf("InsertGridValues returned error: %v", err)
+	}
+
+	var count uint64
+	err = rawConn.QueryRow(ctx, "SELECT count() FROM grid_data FINAL WHERE catalog_id = @catalog_id",
+		clickhouse.Named("catalog_id", catalogID)).Scan(&count)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if count != uint64(len(values)) {
+		t.Errorf("expected %d rows, got %d", len(values), count)
+	}
+}
+
+func TestInsertGridValues_Async(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test, requires ClickHouse")
+	}
+
+	ctx := t.Context()
+	rawConn := testutil.NewRawConn(t)
+
+	catalogID, err := uuid.NewV7()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		_ = rawConn.Exec(context.Background(), "ALTER TABLE grid_data DELETE WHERE catalog_id = @catalog_id",
+			clickhouse.Named("catalog_id", catalogID))
+	})
+
+	values := []domain.GridValue{{
+		Variable:  "pm2p5_async",
+		Timestamp: time.Now().UTC().Truncate(time.Second),
+		Lat:       40,
+		Lon:       10,
+		Value:     1,
+		Unit:      "µg/m³",
+		CatalogID: catalogID,
+	}}
+
+	writer := grid.NewWriter(rawConn, grid.WithAsyncInsert(grid.AsyncInsert{Wait: true}))
+	if err := writer.InsertGridValues(ctx, values); err != nil {
+		t.Fatalf("InsertGridValues returned error: %v", err)
+	}
+
+	var count uint64
+	err = rawConn.QueryRow(ctx, "SELECT count() FROM grid_data FINAL WHERE catalog_id = @catalog_id",
+		clickhouse.Named("catalog_id", catalogID)).Scan(&count)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if count != 1 {
+		t.Errorf("expected async insert to be flushed before returning with Wait, got %d rows", count)
+	}
+}
+
+func TestGetSamples_LatestFastPath(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test, requires ClickHouse")
+	}
+
+	ctx := t.Context()
+	rawConn := testutil.NewRawConn(t)
+	migrator, err := migrate.NewMigrator(rawConn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := migrator.Up(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	variable := "pm2p5_latest"
+	timestamp := time.Now().UTC().Truncate(time.Second)
+	lat := float32(52.5)
+	lon := float32(13.4)
+	latestCatalogID := testutil.InsertGridRow(t, rawConn, variable, float32(2), "µg/m³", timestamp, lat, lon)
+	olderCatalogID := testutil.InsertGridRow(t, rawConn, variable, float32(1), "µg/m³", timestamp.Add(-time.Hour), lat, lon)
+
+	finder := grid.NewFinder(rawConn, grid.WithLatestFastPath())
+
+	t.Run("current conditions", func(t *testing.T) {
+		samples, err := finder.GetSamples(ctx, []string{variable}, timestamp.Add(30*time.Minute), lat, lon)
+		if err != nil {
+			t.Fatalf("GetSamples returned error: %v", err)
+		}
+		if sample := samples[variable]; sample == nil || sample.CatalogID != latestCatalogID {
+			t.Errorf("expected latest sample %v, got %+v", latestCatalogID, sample)
+		}
+	})
+
+	t.Run("historical falls back to grid_data", func(t *testing.T) {
+		sample, err := finder.GetSample(ctx, variable, timestamp.Add(-30*time.Minute), lat, lon)
+		if err != nil {
+			t.Fatalf("GetSample returned error: %v", err)
+		}
+		if sample.CatalogID != olderCatalogID {
+			t.Errorf("expected older sample %v, got %v", olderCatalogID, sample.CatalogID)
+		}
+	})
+
+	t.Run("cell missing from the newest load falls back to grid_data", func(t *testing.T) {
+		staleVariable := "pm10_latest"
+		testutil.InsertGridRow(t, rawConn, staleVariable, float32(1), "µg/m³", timestamp.Add(-time.Hour), lat, lon)
+		newestCatalogID := testutil.InsertGridRow(t, rawConn, staleVariable, float32(2), "µg/m³", timestamp, lat+1, lon+1)
+
+		sample, err := finder.GetSample(ctx, staleVariable, timestamp.Add(30*time.Minute), lat, lon)
+		if err != nil {
+			t.Fatalf("GetSample returned error: %v", err)
+		}
+		if sample.CatalogID != newestCatalogID {
+			t.Errorf("expected sample %v at the newest timestamp, got %v at %s", newestCatalogID, sample.CatalogID, sample.Timestamp)
+		}
+	})
+}
+
+func TestGetSamples_GeohashPrefilter(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test, requires ClickHouse")
+	}
+
+	ctx := t.Context()
+	rawConn := testutil.NewRawConn(t)
+	migrator, err := migrate.NewMigrator(rawConn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := migrator.Up(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	variable := "pm2p5_geohash"
+	timestamp := time.Now().UTC().Truncate(time.Hour)
+	nearCatalogID := testutil.InsertGridRow(t, rawConn, variable, float32(1), "µg/m³", timestamp, 52.5, 13.4)
+	farVariable := "pm10_geohash"
+	farCatalogID := testutil.InsertGridRow(t, rawConn, farVariable, float32(2), "µg/m³", timestamp, 48.1, 11.6)
+
+	finder := grid.NewFinder(rawConn, grid.WithGeohashPrefilter())
+
+	samples, err := finder.GetSamples(ctx, []string{variable, farVariable}, timestamp, 52.52, 13.40)
+	if err != nil {
+		t.Fatalf("GetSamples returned error: %v", err)
+	}
+	if sample := samples[variable]; sample == nil || sample.CatalogID != nearCatalogID {
+		t.Errorf("expected nearby sample %v, got %+v", nearCatalogID, sample)
+	}
+	if sample := samples[farVariable]; sample == nil || sample.CatalogID != farCatalogID {
+		t.Errorf("expected distant sample %v via fallback, got %+v", farCatalogID, sample)
+	}
+}
+
+func TestCheckSchema(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test, requires ClickHouse")
+	}
+
+	ctx := t.Context()
+	rawConn := testutil.NewRawConn(t)
+	migrator, err := migrate.NewMigrator(rawConn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := migrator.Up(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	finder := grid.NewFinder(rawConn, grid.WithLatestFastPath(), grid.WithGeohashPrefilter(), grid.WithAuditLogSchema())
+	if err := finder.CheckSchema(ctx); err != nil {
+		t.Fatalf("CheckSchema returned error on a migrated schema: %v", err)
+	}
+}
@@ -0,0 +1,69 @@
+package grid
+
+import "slices"
+
+// geohashPrecision must match the geohash column expression in migration 0003_add_grid_geohash.
+// At precision 4 a cell spans 0.35° of longitude and 0.18° of latitude.
+const geohashPrecision = 4
+
+const geohashAlphabet = "0123456789bcdefghjkmnpqrstuvwxyz"
+
+// geohashEncode mirrors ClickHouse geohashEncode: bits alternate longitude first, and a
+// coordinate on a bisection boundary goes to the upper half.
+func geohashEncode(lat, lon float64, precision int) string {
+	latRange, lonRange := [2]float64{-90, 90}, [2]float64{-180, 180}
+	hash := make([]byte, precision)
+	even := true
+	for i := range hash {
+		var index byte
+		for range 5 {
+			r, v := &latRange, lat
+			if even {
+				r, v = &lonRange, lon
+			}
+			mid := (r[0] + r[1]) / 2
+			index <<= 1
+			if v >= mid {
+				index |= 1
+				r[0] = mid
+			} else {
+				r[1] = mid
+			}
+			even = !even
+		}
+		hash[i] = geohashAlphabet[index]
+	}
+	return string(hash)
+}
+
+// geohashCellSize returns the cell height and width in degrees.
+func geohashCellSize(precision int) (latDeg, lonDeg float64) {
+	bits := 5 * precision
+	lonBits := (bits + 1) / 2
+	latBits := bits / 2
+	return 180 / float64(uint64(1)<<latBits), 360 / float64(uint64(1)<<lonBits)
+}
+
+// geohashNeighborhood returns the cell containing (lat, lon) and its eight neighbours, so
+// any grid point within one cell height of the point is covered. Longitude wraps at the
+// antimeridian; latitude clamps at the poles.
+func geohashNeighborhood(lat, lon float32) []string {
+	height, width := geohashCellSize(geohashPrecision)
+	cells := make([]string, 0, 9)
+	for _, dLat := range []float64{-height, 0, height} {
+		for _, dLon := range []float64{-width, 0, width} {
+			cellLat := min(max(float64(lat)+dLat, -90), 90)
+			cellLon := float64(lon) + dLon
+			if cellLon >= 180 {
+				cellLon -= 360
+			} else if cellLon < -180 {
+				cellLon += 360
+			}
+			cell := geohashEncode(cellLat, cellLon, geohashPrecision)
+			if !slices.Contains(cells, cell) {
+				cells = append(cells, cell)
+			}
+		}
+	}
+	return cells
+}
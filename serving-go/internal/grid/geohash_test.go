@@ -0,0 +1,50 @@
+package grid
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestGeohashEncode(t *testing.T) {
+	tests := []struct {
+		lat, lon  float64
+		precision int
+		want      string
+	}{
+		{lat: 57.64911, lon: 10.40744, precision: 11, want: "u4pruydqqvj"},
+		{lat: 42.6, lon: -5.6, precision: 5, want: "ezs42"},
+		{lat: 52.52, lon: 13.40, precision: 4, want: "u33d"},
+		{lat: -90, lon: -180, precision: 4, want: "0000"},
+	}
+
+	for _, tt := range tests {
+		if got := geohashEncode(tt.lat, tt.lon, tt.precision); got != tt.want {
+			t.Errorf("geohashEncode(%v, %v, %d) = %q, want %q", tt.lat, tt.lon, tt.precision, got, tt.want)
+		}
+	}
+}
+
+func TestGeohashNeighborhood(t *testing.T) {
+	cells := geohashNeighborhood(52.52, 13.40)
+	if len(cells) != 9 {
+		t.Fatalf("expected 9 distinct cells, got %d: %v", len(cells), cells)
+	}
+	if !slices.Contains(cells, "u33d") {
+		t.Errorf("expected the containing cell u33d, got %v", cells)
+	}
+
+	// A grid point just across a cell boundary must still be covered.
+	height, width := geohashCellSize(geohashPrecision)
+	near := geohashEncode(52.52+height*0.9, 13.40-width*0.9, geohashPrecision)
+	if !slices.Contains(cells, near) {
+		t.Errorf("expected neighbour %q in %v", near, cells)
+	}
+}
+
+func TestGeohashNeighborhood_Antimeridian(t *testing.T) {
+	cells := geohashNeighborhood(0, 179.9)
+	west := geohashEncode(0, -179.9, geohashPrecision)
+	if !slices.Contains(cells, west) {
+		t.Errorf("expected cell %q across the antimeridian in %v", west, cells)
+	}
+}
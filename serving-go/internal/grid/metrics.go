@@ -0,0 +1,109 @@
+package grid
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log/slog"
+	"sync/atomic"
+	"time"
+
+	"github.com/ClickHouse/clickhouse-go/v2"
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/kacper-wojtaszczyk/jackfruit/serving-go/internal/requestid"
+)
+
+// Metrics holds per-query Prometheus collectors, labelled by query name.
+type Metrics struct {
+	duration   *prometheus.HistogramVec
+	rowsRead   *prometheus.CounterVec
+	bytesRead  *prometheus.CounterVec
+	resultRows *prometheus.HistogramVec
+}
+
+func NewMetrics(reg prometheus.Registerer) *Metrics {
+	m := &Metrics{
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "clickhouse_query_duration_seconds",
+			Help:    "ClickHouse query latency, including result streaming.",
+			Buckets: []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10, 15},
+		}, []string{"query", "outcome"}),
+		rowsRead: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "clickhouse_query_rows_read_total",
+			Help: "Rows scanned by ClickHouse, from query progress packets.",
+		}, []string{"query"}),
+		bytesRead: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "clickhouse_query_bytes_read_total",
+			Help: "Uncompressed bytes scanned by ClickHouse, from query progress packets.",
+		}, []string{"query"}),
+		resultRows: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "clickhouse_query_result_rows",
+			Help:    "Rows returned to the client, from query profile info.",
+			Buckets: prometheus.ExponentialBuckets(1, 4, 10),
+		}, []string{"query"}),
+	}
+	reg.MustRegister(m.duration, m.rowsRead, m.bytesRead, m.resultRows)
+	return m
+}
+
+// queryStats accumulates driver callbacks, which may fire from the driver's reader goroutine.
+type queryStats struct {
+	rowsRead    atomic.Uint64
+	bytesRead   atomic.Uint64
+	resultRows  atomic.Uint64
+	resultBytes atomic.Uint64
+}
+
+func (s *queryStats) onProgress(p *clickhouse.Progress) {
+	s.rowsRead.Add(p.Rows)
+	s.bytesRead.Add(p.Bytes)
+}
+
+func (s *queryStats) onProfileInfo(p *clickhouse.ProfileInfo) {
+	s.resultRows.Add(p.Rows)
+	s.resultBytes.Add(p.Bytes)
+}
+
+// observe runs one query attempt, recording metrics and logging it if slower than the threshold,
+// or at DEBUG otherwise.
+func (c *Finder) observe(ctx context.Context, name string, query func(ctx context.Context) error) error {
+	if c.metrics == nil && c.slowQueryLogger == nil && c.queryLogger == nil {
+		return query(ctx)
+	}
+
+	var stats queryStats
+	ctx = clickhouse.Context(ctx, clickhouse.WithProgress(stats.onProgress), clickhouse.WithProfileInfo(stats.onProfileInfo))
+	start := time.Now()
+	err := query(ctx)
+	elapsed := time.Since(start)
+
+	outcome := "ok"
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		outcome = "error"
+	}
+	if c.metrics != nil {
+		c.metrics.duration.WithLabelValues(name, outcome).Observe(elapsed.Seconds())
+		c.metrics.rowsRead.WithLabelValues(name).Add(float64(stats.rowsRead.Load()))
+		c.metrics.bytesRead.WithLabelValues(name).Add(float64(stats.bytesRead.Load()))
+		c.metrics.resultRows.WithLabelValues(name).Observe(float64(stats.resultRows.Load()))
+	}
+	logger, level, msg := c.queryLogger, slog.LevelDebug, "clickhouse query"
+	if c.slowQueryLogger != nil && elapsed >= c.slowQueryThreshold {
+		logger, level, msg = c.slowQueryLogger, slog.LevelWarn, "slow clickhouse query"
+	}
+	if logger != nil {
+		logger.LogAttrs(ctx, level, msg,
+			slog.String("query", name),
+			slog.Duration("duration", elapsed),
+			slog.Uint64("rows_read", stats.rowsRead.Load()),
+			slog.Uint64("bytes_read", stats.bytesRead.Load()),
+			slog.Uint64("result_rows", stats.resultRows.Load()),
+			slog.Uint64("result_bytes", stats.resultBytes.Load()),
+			slog.String("outcome", outcome),
+			slog.String("request_id", requestid.FromContext(ctx)),
+		)
+	}
+
+	return err
+}
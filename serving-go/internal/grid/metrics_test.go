@@ -0,0 +1,96 @@
+package grid
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/ClickHouse/clickhouse-go/v2"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+
+	"github.com/kacper-wojtaszczyk/jackfruit/serving-go/internal/requestid"
+)
+
+func TestObserve_RecordsMetrics(t *testing.T) {
+	metrics := NewMetrics(prometheus.NewRegistry())
+	finder := NewFinder(nil, WithMetrics(metrics))
+
+	if err := finder.observe(t.Context(), "sample", func(ctx context.Context) error { return nil }); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := finder.observe(t.Context(), "sample", func(ctx context.Context) error { return sql.ErrNoRows }); !errors.Is(err, sql.ErrNoRows) {
+		t.Fatalf("expected sql.ErrNoRows to pass through, got: %v", err)
+	}
+	if err := finder.observe(t.Context(), "grid", func(ctx context.Context) error { return errors.New("boom") }); err == nil {
+		t.Fatal("expected error to pass through")
+	}
+
+	if got := testutil.CollectAndCount(metrics.duration); got != 2 {
+		t.Errorf("expected 2 duration series (sample/ok, grid/error), got %d", got)
+	}
+	if got := testutil.ToFloat64(metrics.rowsRead.WithLabelValues("sample")); got != 0 {
+		t.Errorf("expected no rows read without progress packets, got %v", got)
+	}
+}
+
+func TestQueryStats_AccumulatesDriverCallbacks(t *testing.T) {
+	var stats queryStats
+	stats.onProgress(&clickhouse.Progress{Rows: 100, Bytes: 4000})
+	stats.onProgress(&clickhouse.Progress{Rows: 50, Bytes: 2000})
+	stats.onProfileInfo(&clickhouse.ProfileInfo{Rows: 1, Bytes: 64})
+
+	if got := stats.rowsRead.Load(); got != 150 {
+		t.Errorf("rows read = %d, want 150", got)
+	}
+	if got := stats.bytesRead.Load(); got != 6000 {
+		t.Errorf("bytes read = %d, want 6000", got)
+	}
+	if got := stats.resultRows.Load(); got != 1 {
+		t.Errorf("result rows = %d, want 1", got)
+	}
+}
+
+func TestObserve_LogsSlowQueries(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, nil))
+	finder := NewFinder(nil, WithSlowQueryLog(logger, 5*time.Millisecond))
+	ctx := requestid.WithID(t.Context(), "req-123")
+
+	_ = finder.observe(ctx, "sample", func(ctx context.Context) error { return nil })
+	if buf.Len() != 0 {
+		t.Fatalf("expected fast query not to be logged, got: %s", buf.String())
+	}
+
+	_ = finder.observe(ctx, "series", func(ctx context.Context) error {
+		time.Sleep(10 * time.Millisecond)
+		return nil
+	})
+	var entry map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatalf("expected one JSON log line, got %q: %v", buf.String(), err)
+	}
+	if entry["query"] != "series" || entry["request_id"] != "req-123" {
+		t.Errorf("unexpected log entry: %v", entry)
+	}
+}
+
+func TestObserve_LogsQueriesAtDebug(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	finder := NewFinder(nil, WithQueryLog(logger), WithSlowQueryLog(logger, time.Hour))
+
+	_ = finder.observe(t.Context(), "sample", func(ctx context.Context) error { return nil })
+	var entry map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatalf("expected one JSON log line, got %q: %v", buf.String(), err)
+	}
+	if entry["level"] != "DEBUG" || entry["msg"] != "clickhouse query" || entry["query"] != "sample" {
+		t.Errorf("unexpected log entry: %v", entry)
+	}
+}
@@ -0,0 +1,358 @@
+package grid
+
+import (
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Named parameters shared by the query builder and clickhouse.Named calls,
+// so a renamed placeholder can't silently drift from its binding.
+const (
+	paramVariable   = "variable"
+	paramVariables  = "variables"
+	paramTimestamp  = "timestamp"
+	paramFrom       = "from"
+	paramTo         = "to"
+	paramLat        = "lat"
+	paramLon        = "lon"
+	paramMinLat     = "min_lat"
+	paramMaxLat     = "max_lat"
+	paramMinLon     = "min_lon"
+	paramMaxLon     = "max_lon"
+	paramStride     = "stride"
+	paramCells      = "cells"
+	paramCatalogIDs = "catalog_ids"
+	paramCatalogID  = "catalog_id"
+	paramEntryKeys  = "entry_keys"
+)
+
+const (
+	tableGridData   = "grid_data"
+	tableGridLatest = "grid_latest"
+)
+
+// sampleColumns is the column order scanSamples expects.
+var sampleColumns = []string{"value", "unit", "lat", "lon", "catalog_id", "timestamp"}
+
+func bind(param string) string {
+	return "@" + param
+}
+
+// selectQuery renders a single SELECT. from is a table name or a parenthesised subquery;
+// final applies FINAL, which grid_data (ReplacingMergeTree) needs for deduplicated reads.
+type selectQuery struct {
+	columns []string
+	from    string
+	final   bool
+	where   []string
+	groupBy []string
+	orderBy []string
+	limit   int
+}
+
+func (q selectQuery) String() string {
+	var b strings.Builder
+	b.WriteString("SELECT ")
+	b.WriteString(strings.Join(q.columns, ", "))
+	b.WriteString(" FROM ")
+	b.WriteString(q.from)
+	if q.final {
+		b.WriteString(" FINAL")
+	}
+	if len(q.where) > 0 {
+		b.WriteString(" WHERE ")
+		b.WriteString(strings.Join(q.where, " AND "))
+	}
+	if len(q.groupBy) > 0 {
+		b.WriteString(" GROUP BY ")
+		b.WriteString(strings.Join(q.groupBy, ", "))
+	}
+	if len(q.orderBy) > 0 {
+		b.WriteString(" ORDER BY ")
+		b.WriteString(strings.Join(q.orderBy, ", "))
+	}
+	if q.limit > 0 {
+		b.WriteString(" LIMIT ")
+		b.WriteString(strconv.Itoa(q.limit))
+	}
+	return b.String()
+}
+
+// subquery wraps q in parentheses for use as a FROM source or scalar expression.
+func subquery(q selectQuery) string {
+	return "(" + q.String() + ")"
+}
+
+func variableEquals() string {
+	return "variable = " + bind(paramVariable)
+}
+
+func variableIn() string {
+	return "has(" + bind(paramVariables) + ", variable)"
+}
+
+func timestampAtOrBefore() string {
+	return "timestamp <= " + bind(paramTimestamp)
+}
+
+func timestampBetween() string {
+	return "timestamp BETWEEN " + bind(paramFrom) + " AND " + bind(paramTo)
+}
+
+func timestampSince() string {
+	return "timestamp BETWEEN " + bind(paramFrom) + " AND " + bind(paramTimestamp)
+}
+
+func latBetween() string {
+	return "lat BETWEEN " + bind(paramMinLat) + " AND " + bind(paramMaxLat)
+}
+
+func lonBetween() string {
+	return "lon BETWEEN " + bind(paramMinLon) + " AND " + bind(paramMaxLon)
+}
+
+// geohashIn restricts rows to the @cells geohash neighbourhood (see migration 0003).
+func geohashIn() string {
+	return "has(" + bind(paramCells) + ", geohash)"
+}
+
+// latestTimestamp snaps to the newest timestamp of a single variable matching filters.
+func latestTimestamp(filters ...string) string {
+	return "timestamp = " + subquery(selectQuery{
+		columns: []string{"max(timestamp)"},
+		from:    tableGridData,
+		final:   true,
+		where:   append([]string{variableEquals()}, filters...),
+	})
+}
+
+// distance is the great-circle distance in metres to (@lat, @lon), used for nearest-cell ordering.
+// Unlike planar degrees it stays correct at high latitudes and across the antimeridian.
+func distance() string {
+	return "greatCircleDistance(lon, lat, " + bind(paramLon) + ", " + bind(paramLat) + ")"
+}
+
+// sample picks the nearest cell of one variable at its latest timestamp at or before @timestamp.
+// spatial filters only narrow the nearest-cell search, never the timestamp snapping.
+func sample(spatial ...string) string {
+	return selectQuery{
+		columns: sampleColumns,
+		from:    tableGridData,
+		final:   true,
+		where:   slices.Concat([]string{variableEquals(), latestTimestamp(timestampAtOrBefore())}, spatial),
+		orderBy: []string{distance()},
+		limit:   1,
+	}.String()
+}
+
+// samples is sample for several variables at once: each variable snaps to its own
+// latest timestamp, and argMin picks the nearest cell per variable.
+func samples(spatial ...string) string {
+	return selectQuery{
+		columns: []string{
+			"variable",
+			"argMin(value, distance)",
+			"argMin(unit, distance)",
+			"argMin(lat, distance)",
+			"argMin(lon, distance)",
+			"argMin(catalog_id, distance)",
+			"any(timestamp)",
+		},
+		from: subquery(selectQuery{
+			columns: slices.Concat([]string{"variable"}, sampleColumns, []string{distance() + " AS distance"}),
+			from:    tableGridData,
+			final:   true,
+			where: slices.Concat([]string{
+				variableIn(),
+				"(variable, timestamp) IN " + subquery(selectQuery{
+					columns: []string{"variable", "max(timestamp)"},
+					from:    tableGridData,
+					final:   true,
+					where:   []string{variableIn(), timestampAtOrBefore()},
+					groupBy: []string{"variable"},
+				}),
+			}, spatial),
+		}),
+		groupBy: []string{"variable"},
+	}.String()
+}
+
+// series returns one cell's samples in [@from, @to]; the cell is the nearest one
+// at the latest timestamp in range.
+func series(spatial ...string) string {
+	return selectQuery{
+		columns: sampleColumns,
+		from:    tableGridData,
+		final:   true,
+		where: []string{
+			variableEquals(),
+			timestampBetween(),
+			"(lat, lon) = " + subquery(selectQuery{
+				columns: []string{"lat", "lon"},
+				from:    tableGridData,
+				final:   true,
+				where:   slices.Concat([]string{variableEquals(), latestTimestamp(timestampBetween())}, spatial),
+				orderBy: []string{distance()},
+				limit:   1,
+			}),
+		},
+		orderBy: []string{"timestamp"},
+	}.String()
+}
+
+var (
+	sampleQuery  = sample()
+	samplesQuery = samples()
+	seriesQuery  = series()
+
+	// The nearby variants prefilter by geohash neighbourhood; callers fall back to the
+	// unfiltered query when they find nothing.
+	nearbySampleQuery  = sample(geohashIn())
+	nearbySamplesQuery = samples(geohashIn())
+	nearbySeriesQuery  = series(geohashIn())
+)
+
+// gridQuery returns every @stride-th distinct lat/lon inside the bounding box at the
+// latest timestamp at or before @timestamp that has data inside the box, so a box outside a
+// partially loaded newest timestamp still returns its cells.
+var gridQuery = selectQuery{
+	columns: sampleColumns,
+	from: subquery(selectQuery{
+		columns: slices.Concat(sampleColumns, []string{
+			"dense_rank() OVER (ORDER BY lat) - 1 AS lat_index",
+			"dense_rank() OVER (ORDER BY lon) - 1 AS lon_index",
+		}),
+		from:  tableGridData,
+		final: true,
+		where: []string{variableEquals(), latestTimestamp(timestampAtOrBefore(), latBetween(), lonBetween()), latBetween(), lonBetween()},
+	}),
+	where: []string{
+		"lat_index % " + bind(paramStride) + " = 0",
+		"lon_index % " + bind(paramStride) + " = 0",
+	},
+	orderBy: []string{"lat", "lon"},
+}.String()
+
+// coverage aggregates time range, extent and row count per variable. FINAL keeps row
+// counts free of not-yet-merged duplicates at the cost of a full scan.
+func coverage(filters ...string) string {
+	return selectQuery{
+		columns: []string{
+			"variable", "min(timestamp)", "max(timestamp)",
+			"min(lat)", "min(lon)", "max(lat)", "max(lon)", "count()",
+		},
+		from:    tableGridData,
+		final:   true,
+		where:   filters,
+		groupBy: []string{"variable"},
+		orderBy: []string{"variable"},
+	}.String()
+}
+
+var (
+	coverageQuery         = coverage()
+	variableCoverageQuery = coverage(variableIn())
+)
+
+// latestTimestampsQuery reads the newest timestamp per variable from grid_latest, which
+// holds one row per cell instead of every variable's history. max() needs no FINAL.
+var latestTimestampsQuery = selectQuery{
+	columns: []string{"variable", "max(timestamp)"},
+	from:    tableGridLatest,
+	groupBy: []string{"variable"},
+}.String()
+
+// catalogRowsQuery counts rows per catalog entry. catalog_id is not in the sorting key, so
+// the entries' (variable, timestamp) pairs restrict the read to their partitions and key
+// ranges before catalog_id picks the rows.
+var catalogRowsQuery = selectQuery{
+	columns: []string{"catalog_id", "count()"},
+	from:    tableGridData,
+	final:   true,
+	where: []string{
+		"(variable, timestamp) IN (" + bind(paramEntryKeys) + ")",
+		"has(" + bind(paramCatalogIDs) + ", catalog_id)",
+	},
+	groupBy: []string{"catalog_id"},
+}.String()
+
+// qualityQuery summarises one catalog entry's values, found through its (variable, timestamp)
+// key range like catalogRowsQuery. NaNs are counted, not aggregated,
+// and the distinct lat x lon product is the row count a complete rectangular grid would have.
+// Grid spacing is the extent divided by the number of gaps between distinct coordinates.
+var qualityQuery = selectQuery{
+	columns: []string{
+		"count()",
+		"uniqExact(lat) * uniqExact(lon)",
+		"countIf(isNaN(value))",
+		"countIf(value = 0)",
+		"minIf(value, NOT isNaN(value))",
+		"maxIf(value, NOT isNaN(value))",
+		"avgIf(value, NOT isNaN(value))",
+		"arraySort(groupUniqArray(unit))",
+		"if(uniqExact(lat) > 1, (max(lat) - min(lat)) / (uniqExact(lat) - 1), 0)",
+		"if(uniqExact(lon) > 1, (max(lon) - min(lon)) / (uniqExact(lon) - 1), 0)",
+	},
+	from:  tableGridData,
+	final: true,
+	where: []string{
+		"variable = " + bind(paramVariable),
+		"timestamp = " + bind(paramTimestamp),
+		"catalog_id = " + bind(paramCatalogID),
+	},
+}.String()
+
+// candidatesQuery is samplesQuery per (variable, catalog_id): each catalog entry's newest
+// timestamp in [@from, @timestamp] at its nearest cell, so the domain layer can choose
+// between datasets covering the same point.
+var candidatesQuery = selectQuery{
+	columns: []string{
+		"variable",
+		"argMin(value, distance)",
+		"argMin(unit, distance)",
+		"argMin(lat, distance)",
+		"argMin(lon, distance)",
+		"catalog_id",
+		"any(timestamp)",
+	},
+	from: subquery(selectQuery{
+		columns: slices.Concat([]string{"variable"}, sampleColumns, []string{distance() + " AS distance"}),
+		from:    tableGridData,
+		final:   true,
+		where: []string{
+			variableIn(),
+			"(variable, catalog_id, timestamp) IN " + subquery(selectQuery{
+				columns: []string{"variable", "catalog_id", "max(timestamp)"},
+				from:    tableGridData,
+				final:   true,
+				where:   []string{variableIn(), timestampSince()},
+				groupBy: []string{"variable", "catalog_id"},
+			}),
+		},
+	}),
+	groupBy: []string{"variable", "catalog_id"},
+}.String()
+
+// latestSamplesQuery picks the nearest cell per variable from grid_latest, i.e. each cell's
+// newest sample regardless of @timestamp, and the variable's newest timestamp across cells;
+// callers must check the returned timestamps.
+var latestSamplesQuery = selectQuery{
+	columns: []string{
+		"variable",
+		"argMin(value, distance)",
+		"argMin(unit, distance)",
+		"argMin(lat, distance)",
+		"argMin(lon, distance)",
+		"argMin(catalog_id, distance)",
+		"argMin(timestamp, distance)",
+		"max(timestamp)",
+	},
+	from: subquery(selectQuery{
+		columns: slices.Concat([]string{"variable"}, sampleColumns, []string{distance() + " AS distance"}),
+		from:    tableGridLatest,
+		final:   true,
+		where:   []string{variableIn()},
+	}),
+	groupBy: []string{"variable"},
+}.String()
@@ -0,0 +1,75 @@
+package grid
+
+import (
+	"maps"
+	"regexp"
+	"slices"
+	"testing"
+)
+
+func TestSelectQuery_String(t *testing.T) {
+	q := selectQuery{
+		columns: []string{"variable", "count()"},
+		from:    tableGridData,
+		final:   true,
+		where:   []string{variableIn(), timestampAtOrBefore()},
+		groupBy: []string{"variable"},
+		orderBy: []string{"variable"},
+		limit:   10,
+	}
+
+	want := "SELECT variable, count() FROM grid_data FINAL WHERE has(@variables, variable) AND timestamp <= @timestamp GROUP BY variable ORDER BY variable LIMIT 10"
+	if got := q.String(); got != want {
+		t.Errorf("String() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestSampleQuery(t *testing.T) {
+	want := "SELECT value, unit, lat, lon, catalog_id, timestamp FROM grid_data FINAL " +
+		"WHERE variable = @variable AND timestamp = (SELECT max(timestamp) FROM grid_data FINAL WHERE variable = @variable AND timestamp <= @timestamp) " +
+		"ORDER BY greatCircleDistance(lon, lat, @lon, @lat) LIMIT 1"
+	if sampleQuery != want {
+		t.Errorf("sampleQuery =\n%s\nwant\n%s", sampleQuery, want)
+	}
+}
+
+var placeholderPattern = regexp.MustCompile(`@(\w+)`)
+
+func TestQueries_UseExpectedParams(t *testing.T) {
+	tests := []struct {
+		name   string
+		query  string
+		params []string
+	}{
+		{name: "sample", query: sampleQuery, params: []string{paramVariable, paramTimestamp, paramLat, paramLon}},
+		{name: "samples", query: samplesQuery, params: []string{paramVariables, paramTimestamp, paramLat, paramLon}},
+		{name: "latest samples", query: latestSamplesQuery, params: []string{paramVariables, paramLat, paramLon}},
+		{name: "series", query: seriesQuery, params: []string{paramVariable, paramFrom, paramTo, paramLat, paramLon}},
+		{name: "grid", query: gridQuery, params: []string{
+			paramVariable, paramTimestamp, paramMinLat, paramMaxLat, paramMinLon, paramMaxLon, paramStride,
+		}},
+		{name: "nearby sample", query: nearbySampleQuery, params: []string{paramVariable, paramTimestamp, paramLat, paramLon, paramCells}},
+		{name: "nearby samples", query: nearbySamplesQuery, params: []string{paramVariables, paramTimestamp, paramLat, paramLon, paramCells}},
+		{name: "nearby series", query: nearbySeriesQuery, params: []string{paramVariable, paramFrom, paramTo, paramLat, paramLon, paramCells}},
+		{name: "coverage", query: coverageQuery},
+		{name: "variable coverage", query: variableCoverageQuery, params: []string{paramVariables}},
+		{name: "latest timestamps", query: latestTimestampsQuery},
+		{name: "catalog rows", query: catalogRowsQuery, params: []string{paramEntryKeys, paramCatalogIDs}},
+		{name: "quality", query: qualityQuery, params: []string{paramVariable, paramTimestamp, paramCatalogID}},
+		{name: "candidates", query: candidatesQuery, params: []string{paramVariables, paramFrom, paramTimestamp, paramLat, paramLon}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			used := make(map[string]bool)
+			for _, match := range placeholderPattern.FindAllStringSubmatch(tt.query, -1) {
+				used[match[1]] = true
+			}
+			got := slices.Sorted(maps.Keys(used))
+			want := slices.Sorted(slices.Values(tt.params))
+			if !slices.Equal(got, want) {
+				t.Errorf("placeholders %v, want %v", got, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,76 @@
+package grid
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"syscall"
+	"time"
+)
+
+// RetryPolicy controls how read queries are retried after connection-level failures.
+// The driver discards a connection that failed mid-query, so each retry acquires a
+// fresh one from the pool — which re-dials ClickHouse after a restart.
+type RetryPolicy struct {
+	MaxAttempts    int
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+}
+
+// DefaultRetryPolicy rides out a brief ClickHouse restart without noticeably delaying failures.
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts:    3,
+	InitialBackoff: 100 * time.Millisecond,
+	MaxBackoff:     time.Second,
+}
+
+// retry runs an idempotent read, retrying transient connection errors with exponential backoff.
+// Only use it for queries that can be safely re-executed.
+func (p RetryPolicy) retry(ctx context.Context, query func(ctx context.Context) error) error {
+	backoff := p.InitialBackoff
+	var err error
+	for attempt := 1; ; attempt++ {
+		err = query(ctx)
+		if err == nil || !isTransient(err) || attempt >= p.MaxAttempts || ctx.Err() != nil {
+			break
+		}
+
+		timer := time.NewTimer(backoff)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return fmt.Errorf("%w (retry aborted: %w)", err, ctx.Err())
+		case <-timer.C:
+		}
+		backoff = min(backoff*2, p.MaxBackoff)
+	}
+
+	return err
+}
+
+// isTransient reports whether err looks like a dropped or refused connection rather
+// than a query-level failure. Server exceptions, context errors and timeouts, including
+// read deadlines from CLICKHOUSE_READ_TIMEOUT, are not retried.
+func isTransient(err error) bool {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) ||
+		errors.Is(err, os.ErrDeadlineExceeded) {
+		return false
+	}
+	if netErr, ok := errors.AsType[net.Error](err); ok && netErr.Timeout() {
+		return false
+	}
+	if errors.Is(err, io.EOF) ||
+		errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, driver.ErrBadConn) ||
+		errors.Is(err, syscall.ECONNRESET) ||
+		errors.Is(err, syscall.ECONNREFUSED) ||
+		errors.Is(err, syscall.EPIPE) {
+		return true
+	}
+	var opErr *net.OpError
+	return errors.As(err, &opErr)
+}
@@ -0,0 +1,108 @@
+package grid
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/ClickHouse/clickhouse-go/v2"
+)
+
+var testRetryPolicy = RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond, MaxBackoff: time.Millisecond}
+
+func TestIsTransient(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{name: "eof", err: io.EOF, want: true},
+		{name: "wrapped connection reset", err: fmt.Errorf("read: %w", syscall.ECONNRESET), want: true},
+		{name: "dial error", err: &net.OpError{Op: "dial", Net: "tcp", Err: syscall.ECONNREFUSED}, want: true},
+		{name: "no rows", err: sql.ErrNoRows, want: false},
+		{name: "server exception", err: &clickhouse.Exception{Code: 60, Message: "table does not exist"}, want: false},
+		{name: "deadline exceeded", err: &net.OpError{Op: "read", Net: "tcp", Err: context.DeadlineExceeded}, want: false},
+		{name: "canceled", err: context.Canceled, want: false},
+		{name: "read timeout", err: &net.OpError{Op: "read", Net: "tcp", Err: os.ErrDeadlineExceeded}, want: false},
+		{name: "wrapped read timeout", err: fmt.Errorf("read: %w", &net.OpError{Op: "read", Net: "tcp", Err: os.ErrDeadlineExceeded}), want: false},
+		{name: "dns timeout", err: &net.OpError{Op: "dial", Net: "tcp", Err: &net.DNSError{Err: "i/o timeout", IsTimeout: true}}, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isTransient(tt.err); got != tt.want {
+				t.Errorf("isTransient(%v) = %v, want %v", tt.err, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRetry_RecoversFromTransientError(t *testing.T) {
+	calls := 0
+	err := testRetryPolicy.retry(t.Context(), func(ctx context.Context) error {
+		calls++
+		if calls < 3 {
+			return io.ErrUnexpectedEOF
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("expected success after retries, got: %v", err)
+	}
+	if calls != 3 {
+		t.Errorf("expected 3 calls, got %d", calls)
+	}
+}
+
+func TestRetry_GivesUpAfterMaxAttempts(t *testing.T) {
+	calls := 0
+	err := testRetryPolicy.retry(t.Context(), func(ctx context.Context) error {
+		calls++
+		return io.EOF
+	})
+	if !errors.Is(err, io.EOF) {
+		t.Errorf("expected last error to be returned, got: %v", err)
+	}
+	if calls != 3 {
+		t.Errorf("expected 3 calls, got %d", calls)
+	}
+}
+
+func TestRetry_DoesNotRetryQueryErrors(t *testing.T) {
+	calls := 0
+	err := testRetryPolicy.retry(t.Context(), func(ctx context.Context) error {
+		calls++
+		return sql.ErrNoRows
+	})
+	if !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("expected sql.ErrNoRows, got: %v", err)
+	}
+	if calls != 1 {
+		t.Errorf("expected 1 call, got %d", calls)
+	}
+}
+
+func TestRetry_StopsOnContextCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(t.Context())
+	policy := RetryPolicy{MaxAttempts: 5, InitialBackoff: time.Hour, MaxBackoff: time.Hour}
+
+	calls := 0
+	err := policy.retry(ctx, func(ctx context.Context) error {
+		calls++
+		cancel()
+		return io.EOF
+	})
+	if !errors.Is(err, io.EOF) {
+		t.Errorf("expected original error to be preserved, got: %v", err)
+	}
+	if calls != 1 {
+		t.Errorf("expected 1 call, got %d", calls)
+	}
+}
@@ -0,0 +1,167 @@
+package grid
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/ClickHouse/clickhouse-go/v2"
+)
+
+var ErrSchemaMismatch = errors.New("clickhouse schema mismatch")
+
+// column is a column name and its type as system.columns reports it.
+type column struct {
+	name, typ string
+}
+
+// tableSchema is what the finder's queries and scans rely on in one table. Extra columns
+// are allowed.
+type tableSchema struct {
+	name    string
+	engine  string
+	columns []column
+}
+
+var (
+	gridDataSchema = tableSchema{
+		name:   tableGridData,
+		engine: "ReplacingMergeTree",
+		columns: []column{
+			{"variable", "LowCardinality(String)"},
+			{"timestamp", "DateTime"},
+			{"lat", "Float32"},
+			{"lon", "Float32"},
+			{"value", "Float32"},
+			{"unit", "LowCardinality(String)"},
+			{"catalog_id", "UUID"},
+			{"inserted_at", "DateTime64(3)"},
+		},
+	}
+	geohashColumn    = column{"geohash", "String"}
+	gridLatestSchema = tableSchema{
+		name:   tableGridLatest,
+		engine: "ReplacingMergeTree",
+		columns: []column{
+			{"variable", "LowCardinality(String)"},
+			{"lat", "Float32"},
+			{"lon", "Float32"},
+			{"timestamp", "DateTime"},
+			{"value", "Float32"},
+			{"unit", "LowCardinality(String)"},
+			{"catalog_id", "UUID"},
+		},
+	}
+	auditLogSchema = tableSchema{
+		name:   tableAuditLog,
+		engine: "MergeTree",
+		columns: []column{
+			{"time", "DateTime64(3)"},
+			{"actor", "LowCardinality(String)"},
+			{"action", "LowCardinality(String)"},
+			{"target", "String"},
+			{"details", "Map(String, String)"},
+		},
+	}
+)
+
+// tableAuditLog is written and read by internal/audit; the finder only checks its schema.
+const tableAuditLog = "audit_log"
+
+const schemaQuery = `
+    SELECT t.name, t.engine, c.name, c.type
+    FROM system.tables AS t
+    LEFT JOIN system.columns AS c ON c.database = t.database AND c.table = t.name
+    WHERE t.database = currentDatabase() AND has(@tables, t.name)
+`
+
+// CheckSchema verifies that the tables the finder reads exist with the engine and the
+// column types it scans into, so an outdated schema fails at startup instead of on the
+// first query. grid_latest is always checked, since the status endpoint reads it even
+// without WithLatestFastPath; grid_data's geohash column only with WithGeohashPrefilter and
+// audit_log only with WithAuditLogSchema. All differences are reported in one error
+// wrapping ErrSchemaMismatch.
+func (c *Finder) CheckSchema(ctx context.Context) error {
+	schemas := c.schemas()
+	names := make([]string, len(schemas))
+	for i, schema := range schemas {
+		names[i] = schema.name
+	}
+
+	engines := make(map[string]string)
+	columns := make(map[string]map[string]string)
+	err := c.run(ctx, "schema", func(ctx context.Context) error {
+		clear(engines)
+		clear(columns)
+		rows, err := c.conn.Query(ctx, schemaQuery, clickhouse.Named("tables", names))
+		if err != nil {
+			return fmt.Errorf("query clickhouse: %w", err)
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			var table, engine, name, typ string
+			if err := rows.Scan(&table, &engine, &name, &typ); err != nil {
+				return fmt.Errorf("scan clickhouse row: %w", err)
+			}
+			engines[table] = engine
+			if columns[table] == nil {
+				columns[table] = make(map[string]string)
+			}
+			columns[table][name] = typ
+		}
+		if err := rows.Err(); err != nil {
+			return fmt.Errorf("iterate clickhouse rows: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	var problems []string
+	for _, schema := range schemas {
+		problems = append(problems, schema.diff(engines[schema.name], columns[schema.name])...)
+	}
+	if len(problems) > 0 {
+		return fmt.Errorf("%w: %s", ErrSchemaMismatch, strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+// schemas returns the tables the finder's options make the server query.
+func (c *Finder) schemas() []tableSchema {
+	gridData := gridDataSchema
+	if c.geohashPrefilter {
+		gridData.columns = append(gridData.columns[:len(gridData.columns):len(gridData.columns)], geohashColumn)
+	}
+	schemas := []tableSchema{gridData, gridLatestSchema}
+	if c.auditLogSchema {
+		schemas = append(schemas, auditLogSchema)
+	}
+	return schemas
+}
+
+// diff describes how a table with engine and columns (name to type) falls short of s. An
+// empty engine means the table does not exist. Engines ending in the expected one, e.g.
+// ReplicatedReplacingMergeTree or SharedReplacingMergeTree on clusters, match it.
+func (s tableSchema) diff(engine string, columns map[string]string) []string {
+	if engine == "" {
+		return []string{fmt.Sprintf("table %s does not exist", s.name)}
+	}
+	var problems []string
+	if !strings.HasSuffix(engine, s.engine) {
+		problems = append(problems, fmt.Sprintf("table %s has engine %s, want %s", s.name, engine, s.engine))
+	}
+	for _, want := range s.columns {
+		got, ok := columns[want.name]
+		switch {
+		case !ok:
+			problems = append(problems, fmt.Sprintf("column %s.%s does not exist", s.name, want.name))
+		case got != want.typ:
+			problems = append(problems, fmt.Sprintf("column %s.%s is %s, want %s", s.name, want.name, got, want.typ))
+		}
+	}
+	return problems
+}
@@ -0,0 +1,59 @@
+package grid
+
+import (
+	"slices"
+	"testing"
+)
+
+func schemaColumns(schema tableSchema) map[string]string {
+	columns := make(map[string]string)
+	for _, c := range schema.columns {
+		columns[c.name] = c.typ
+	}
+	return columns
+}
+
+func TestTableSchema_Diff(t *testing.T) {
+	matching := schemaColumns(gridDataSchema)
+	matching["geohash"] = "String"
+	if problems := gridDataSchema.diff("ReplacingMergeTree", matching); len(problems) != 0 {
+		t.Errorf("expected extra columns to be allowed, got %v", problems)
+	}
+	for _, engine := range []string{"ReplicatedReplacingMergeTree", "SharedReplacingMergeTree"} {
+		if problems := gridDataSchema.diff(engine, matching); len(problems) != 0 {
+			t.Errorf("expected engine %s to be allowed, got %v", engine, problems)
+		}
+	}
+
+	drifted := schemaColumns(gridDataSchema)
+	delete(drifted, "catalog_id")
+	drifted["value"] = "Float64"
+	want := []string{
+		"table grid_data has engine MergeTree, want ReplacingMergeTree",
+		"column grid_data.value is Float64, want Float32",
+		"column grid_data.catalog_id does not exist",
+	}
+	if problems := gridDataSchema.diff("MergeTree", drifted); !slices.Equal(problems, want) {
+		t.Errorf("expected %q, got %q", want, problems)
+	}
+
+	if problems := gridLatestSchema.diff("", nil); !slices.Equal(problems, []string{"table grid_latest does not exist"}) {
+		t.Errorf("expected missing table, got %q", problems)
+	}
+}
+
+func TestFinder_SchemasFollowOptions(t *testing.T) {
+	// The status endpoint reads grid_latest whether or not point lookups use it.
+	schemas := NewFinder(nil).schemas()
+	if len(schemas) != 2 || slices.Contains(schemas[0].columns, geohashColumn) || schemas[1].name != tableGridLatest {
+		t.Fatalf("expected grid_data without geohash and grid_latest by default, got %+v", schemas)
+	}
+
+	schemas = NewFinder(nil, WithGeohashPrefilter(), WithAuditLogSchema()).schemas()
+	if len(schemas) != 3 || !slices.Contains(schemas[0].columns, geohashColumn) || schemas[2].name != tableAuditLog {
+		t.Fatalf("expected grid_data with geohash, grid_latest and audit_log, got %+v", schemas)
+	}
+	if slices.Contains(gridDataSchema.columns, geohashColumn) {
+		t.Error("expected the shared grid_data schema to stay unchanged")
+	}
+}
@@ -0,0 +1,67 @@
+package grid
+
+import (
+	"context"
+	"time"
+
+	"github.com/ClickHouse/clickhouse-go/v2"
+)
+
+// QuerySettings overrides ClickHouse limits for individual calls. Zero fields keep the
+// finder default, and the connection-level max_execution_time when that is zero too.
+type QuerySettings struct {
+	// MaxExecutionTime bounds the whole call including retries. It is applied as a context
+	// deadline, from which the driver derives the server-side max_execution_time.
+	MaxExecutionTime time.Duration
+	MaxRowsToRead    uint64
+	MaxResultRows    uint64
+}
+
+// merge returns s with the non-zero fields of override applied.
+func (s QuerySettings) merge(override QuerySettings) QuerySettings {
+	if override.MaxExecutionTime > 0 {
+		s.MaxExecutionTime = override.MaxExecutionTime
+	}
+	if override.MaxRowsToRead > 0 {
+		s.MaxRowsToRead = override.MaxRowsToRead
+	}
+	if override.MaxResultRows > 0 {
+		s.MaxResultRows = override.MaxResultRows
+	}
+	return s
+}
+
+func (s QuerySettings) settings() clickhouse.Settings {
+	settings := clickhouse.Settings{}
+	if s.MaxRowsToRead > 0 {
+		settings["max_rows_to_read"] = s.MaxRowsToRead
+	}
+	if s.MaxResultRows > 0 {
+		settings["max_result_rows"] = s.MaxResultRows
+	}
+	return settings
+}
+
+func (s QuerySettings) apply(ctx context.Context) (context.Context, context.CancelFunc) {
+	cancel := context.CancelFunc(func() {})
+	if s.MaxExecutionTime > 0 {
+		ctx, cancel = context.WithTimeout(ctx, s.MaxExecutionTime)
+	}
+	if settings := s.settings(); len(settings) > 0 {
+		ctx = clickhouse.Context(ctx, clickhouse.WithSettings(settings))
+	}
+	return ctx, cancel
+}
+
+type settingsKey struct{}
+
+// ContextWithSettings overrides the finder's QuerySettings for calls made with ctx, e.g. a
+// longer limit for a bulk export.
+func ContextWithSettings(ctx context.Context, settings QuerySettings) context.Context {
+	return context.WithValue(ctx, settingsKey{}, settingsFromContext(ctx).merge(settings))
+}
+
+func settingsFromContext(ctx context.Context) QuerySettings {
+	settings, _ := ctx.Value(settingsKey{}).(QuerySettings)
+	return settings
+}
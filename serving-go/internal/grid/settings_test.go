@@ -0,0 +1,50 @@
+package grid
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestContextWithSettings_MergesOverrides(t *testing.T) {
+	ctx := ContextWithSettings(t.Context(), QuerySettings{MaxExecutionTime: time.Minute, MaxRowsToRead: 1_000})
+	ctx = ContextWithSettings(ctx, QuerySettings{MaxRowsToRead: 5_000})
+
+	defaults := QuerySettings{MaxExecutionTime: 2 * time.Second, MaxResultRows: 10}
+	got := defaults.merge(settingsFromContext(ctx))
+
+	want := QuerySettings{MaxExecutionTime: time.Minute, MaxRowsToRead: 5_000, MaxResultRows: 10}
+	if got != want {
+		t.Errorf("expected %+v, got %+v", want, got)
+	}
+}
+
+func TestQuerySettings_Settings(t *testing.T) {
+	if got := (QuerySettings{MaxExecutionTime: time.Second}).settings(); len(got) != 0 {
+		t.Errorf("expected MaxExecutionTime to be applied as a deadline only, got settings %v", got)
+	}
+
+	got := QuerySettings{MaxRowsToRead: 100, MaxResultRows: 1}.settings()
+	if got["max_rows_to_read"] != uint64(100) || got["max_result_rows"] != uint64(1) {
+		t.Errorf("unexpected settings %v", got)
+	}
+}
+
+func TestFinderRun_AppliesMaxExecutionTime(t *testing.T) {
+	finder := NewFinder(nil, WithDefaultSettings(QuerySettings{MaxExecutionTime: time.Hour}))
+	ctx := ContextWithSettings(t.Context(), QuerySettings{MaxExecutionTime: 50 * time.Millisecond})
+
+	err := finder.run(ctx, "sample", func(ctx context.Context) error {
+		deadline, ok := ctx.Deadline()
+		if !ok {
+			t.Fatal("expected a deadline")
+		}
+		if remaining := time.Until(deadline); remaining > 50*time.Millisecond {
+			t.Errorf("expected per-call override to win, deadline in %s", remaining)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
@@ -0,0 +1,124 @@
+package grid
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ClickHouse/clickhouse-go/v2"
+	"github.com/ClickHouse/clickhouse-go/v2/lib/driver"
+
+	"github.com/kacper-wojtaszczyk/jackfruit/serving-go/internal/domain"
+)
+
+// DefaultBatchSize keeps each insert block well within ClickHouse's recommended range.
+const DefaultBatchSize = 100_000
+
+type Writer struct {
+	conn      driver.Conn
+	batchSize int
+	retry     RetryPolicy
+	async     *AsyncInsert
+}
+
+// AsyncInsert enables server-side buffering of inserts (async_insert=1): ClickHouse
+// coalesces many small blocks into larger parts, so loaders don't need to tune batch sizes.
+type AsyncInsert struct {
+	// Wait makes each send block until the buffered data is flushed to the table.
+	// Without it, errors after buffering (e.g. a failed flush) are not reported.
+	Wait bool
+	// BusyTimeout is the maximum time data sits in the buffer before a flush. Zero keeps the server default.
+	BusyTimeout time.Duration
+	// MaxDataSize flushes the buffer once it holds this many bytes. Zero keeps the server default.
+	MaxDataSize int
+}
+
+func (a AsyncInsert) settings() clickhouse.Settings {
+	settings := clickhouse.Settings{
+		"async_insert":          1,
+		"wait_for_async_insert": 0,
+	}
+	if a.Wait {
+		settings["wait_for_async_insert"] = 1
+	}
+	if a.BusyTimeout > 0 {
+		settings["async_insert_busy_timeout_ms"] = a.BusyTimeout.Milliseconds()
+	}
+	if a.MaxDataSize > 0 {
+		settings["async_insert_max_data_size"] = a.MaxDataSize
+	}
+	return settings
+}
+
+type WriterOption func(*Writer)
+
+func WithBatchSize(size int) WriterOption {
+	return func(w *Writer) {
+		w.batchSize = size
+	}
+}
+
+// WithWriterRetryPolicy overrides DefaultRetryPolicy for batch sends.
+func WithWriterRetryPolicy(policy RetryPolicy) WriterOption {
+	return func(w *Writer) {
+		w.retry = policy
+	}
+}
+
+func WithAsyncInsert(async AsyncInsert) WriterOption {
+	return func(w *Writer) {
+		w.async = &async
+	}
+}
+
+func NewWriter(conn driver.Conn, opts ...WriterOption) *Writer {
+	w := &Writer{conn: conn, batchSize: DefaultBatchSize, retry: DefaultRetryPolicy}
+	for _, opt := range opts {
+		opt(w)
+	}
+	return w
+}
+
+// InsertGridValues writes values in batches of the configured size. A batch that fails
+// on a dropped connection is resent whole: grid_data is a ReplacingMergeTree keyed on
+// (variable, timestamp, lat, lon), so a partially delivered batch deduplicates on merge.
+// Batches sent before a failure stay committed.
+func (w *Writer) InsertGridValues(ctx context.Context, values []domain.GridValue) error {
+	if w.batchSize < 1 {
+		return fmt.Errorf("batch size must be at least 1, %d given", w.batchSize)
+	}
+
+	for start := 0; start < len(values); start += w.batchSize {
+		chunk := values[start:min(start+w.batchSize, len(values))]
+		err := w.retry.retry(ctx, func(ctx context.Context) error {
+			return w.sendBatch(ctx, chunk)
+		})
+		if err != nil {
+			return fmt.Errorf("insert rows %d-%d of %d: %w", start, start+len(chunk)-1, len(values), err)
+		}
+	}
+
+	return nil
+}
+
+func (w *Writer) sendBatch(ctx context.Context, values []domain.GridValue) error {
+	if w.async != nil {
+		ctx = clickhouse.Context(ctx, clickhouse.WithSettings(w.async.settings()))
+	}
+	batch, err := w.conn.PrepareBatch(ctx, "INSERT INTO grid_data (variable, timestamp, lat, lon, value, unit, catalog_id)")
+	if err != nil {
+		return fmt.Errorf("prepare batch: %w", err)
+	}
+	defer batch.Close()
+
+	for _, v := range values {
+		if err := batch.Append(v.Variable, v.Timestamp, v.Lat, v.Lon, v.Value, v.Unit, v.CatalogID); err != nil {
+			return fmt.Errorf("append row: %w", err)
+		}
+	}
+	if err := batch.Send(); err != nil {
+		return fmt.Errorf("send batch: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,121 @@
+package grid
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/ClickHouse/clickhouse-go/v2/lib/driver"
+	"github.com/google/uuid"
+
+	"github.com/kacper-wojtaszczyk/jackfruit/serving-go/internal/domain"
+)
+
+// fakeConn implements only PrepareBatch; any other driver.Conn call panics.
+type fakeConn struct {
+	driver.Conn
+	sendErrs []error
+	batches  []*fakeBatch
+}
+
+func (c *fakeConn) PrepareBatch(_ context.Context, _ string, _ ...driver.PrepareBatchOption) (driver.Batch, error) {
+	b := &fakeBatch{}
+	if len(c.sendErrs) > 0 {
+		b.sendErr, c.sendErrs = c.sendErrs[0], c.sendErrs[1:]
+	}
+	c.batches = append(c.batches, b)
+	return b, nil
+}
+
+type fakeBatch struct {
+	driver.Batch
+	rows    int
+	sendErr error
+}
+
+func (b *fakeBatch) Append(_ ...any) error { b.rows++; return nil }
+func (b *fakeBatch) Send() error           { return b.sendErr }
+func (b *fakeBatch) Close() error          { return nil }
+
+func gridValues(n int) []domain.GridValue {
+	values := make([]domain.GridValue, n)
+	for i := range values {
+		values[i] = domain.GridValue{Variable: "pm2p5", Timestamp: time.Unix(0, 0), Value: float32(i), Unit: "µg/m³", CatalogID: uuid.Nil}
+	}
+	return values
+}
+
+func TestInsertGridValues_Batches(t *testing.T) {
+	conn := &fakeConn{}
+
+	if err := NewWriter(conn, WithBatchSize(4)).InsertGridValues(t.Context(), gridValues(10)); err != nil {
+		t.Fatalf("InsertGridValues returned error: %v", err)
+	}
+
+	if len(conn.batches) != 3 {
+		t.Fatalf("expected 3 batches, got %d", len(conn.batches))
+	}
+	for i, want := range []int{4, 4, 2} {
+		if conn.batches[i].rows != want {
+			t.Errorf("batch %d: expected %d rows, got %d", i, want, conn.batches[i].rows)
+		}
+	}
+}
+
+func TestInsertGridValues_RetriesTransientSend(t *testing.T) {
+	conn := &fakeConn{sendErrs: []error{io.EOF}}
+	writer := NewWriter(conn, WithWriterRetryPolicy(testRetryPolicy))
+
+	if err := writer.InsertGridValues(t.Context(), gridValues(3)); err != nil {
+		t.Fatalf("InsertGridValues returned error: %v", err)
+	}
+	if len(conn.batches) != 2 {
+		t.Errorf("expected failed batch to be re-prepared once, got %d batches", len(conn.batches))
+	}
+}
+
+func TestInsertGridValues_FailsOnQueryError(t *testing.T) {
+	schemaErr := errors.New("no such column")
+	conn := &fakeConn{sendErrs: []error{schemaErr}}
+	writer := NewWriter(conn, WithWriterRetryPolicy(testRetryPolicy))
+
+	err := writer.InsertGridValues(t.Context(), gridValues(3))
+	if !errors.Is(err, schemaErr) {
+		t.Errorf("expected error wrapping send error, got: %v", err)
+	}
+	if len(conn.batches) != 1 {
+		t.Errorf("expected no retries for non-transient error, got %d batches", len(conn.batches))
+	}
+}
+
+func TestInsertGridValues_InvalidBatchSize(t *testing.T) {
+	if err := NewWriter(&fakeConn{}, WithBatchSize(0)).InsertGridValues(t.Context(), gridValues(1)); err == nil {
+		t.Error("expected error for batch size 0")
+	}
+}
+
+func TestAsyncInsertSettings(t *testing.T) {
+	settings := AsyncInsert{Wait: true, BusyTimeout: 2 * time.Second, MaxDataSize: 1 << 20}.settings()
+
+	want := map[string]any{
+		"async_insert":                 1,
+		"wait_for_async_insert":        1,
+		"async_insert_busy_timeout_ms": int64(2000),
+		"async_insert_max_data_size":   1 << 20,
+	}
+	for key, value := range want {
+		if settings[key] != value {
+			t.Errorf("setting %s: expected %v, got %v", key, value, settings[key])
+		}
+	}
+
+	defaults := AsyncInsert{}.settings()
+	if defaults["wait_for_async_insert"] != 0 {
+		t.Errorf("expected fire-and-forget by default, got wait_for_async_insert=%v", defaults["wait_for_async_insert"])
+	}
+	if _, ok := defaults["async_insert_busy_timeout_ms"]; ok {
+		t.Error("expected server default busy timeout when unset")
+	}
+}
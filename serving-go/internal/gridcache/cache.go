@@ -0,0 +1,241 @@
+// Package gridcache memoizes point lookups of any domain.GridRetriever, so every caller of
+// the domain service shares cached ClickHouse results regardless of transport.
+package gridcache
+
+import (
+	"container/list"
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/kacper-wojtaszczyk/jackfruit/serving-go/internal/domain"
+)
+
+// Policy sets how long samples stay cached. A TTL close to a variable's update cadence
+// bounds how long a newly loaded timestamp can be masked by an older cached sample.
+type Policy struct {
+	DefaultTTL time.Duration
+	// TTLs overrides DefaultTTL per variable; zero disables caching for that variable.
+	TTLs map[string]time.Duration
+	// MaxEntries bounds the cache; the least recently used entry is evicted to make room.
+	MaxEntries int
+}
+
+func (p Policy) ttl(variable string) time.Duration {
+	if ttl, ok := p.TTLs[variable]; ok {
+		return ttl
+	}
+	return p.DefaultTTL
+}
+
+type key struct {
+	variable  string
+	timestamp int64
+	lat       float32
+	lon       float32
+}
+
+type entry struct {
+	key       key
+	sample    domain.GridSample
+	expiresAt time.Time
+}
+
+// Cache wraps a GridRetriever. Only found samples are cached; misses and errors always
+// reach the underlying retriever.
+type Cache struct {
+	next    domain.GridRetriever
+	policy  Policy
+	metrics *Metrics
+	logger  *slog.Logger
+	now     func() time.Time
+
+	mu      sync.Mutex
+	entries map[key]*list.Element
+	// lru orders entries from most to least recently used.
+	lru *list.List
+}
+
+type Option func(*Cache)
+
+// WithMetrics records lookups, evictions and cache size.
+func WithMetrics(m *Metrics) Option {
+	return func(c *Cache) {
+		c.metrics = m
+	}
+}
+
+// WithLogger logs each point lookup's hits and misses at DEBUG.
+func WithLogger(logger *slog.Logger) Option {
+	return func(c *Cache) {
+		c.logger = logger
+	}
+}
+
+func New(next domain.GridRetriever, policy Policy, opts ...Option) *Cache {
+	c := &Cache{next: next, policy: policy, now: time.Now, entries: make(map[key]*list.Element), lru: list.New()}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+func (c *Cache) GetSample(
+	ctx context.Context,
+	variable string,
+	timestamp time.Time,
+	lat float32,
+	lon float32,
+) (*domain.GridSample, error) {
+	k := key{variable: variable, timestamp: timestamp.UnixNano(), lat: lat, lon: lon}
+	if sample, ok := c.get(k); ok {
+		c.logLookup(ctx, timestamp, []string{variable}, nil)
+		return sample, nil
+	}
+	c.logLookup(ctx, timestamp, nil, []string{variable})
+
+	sample, err := c.next.GetSample(ctx, variable, timestamp, lat, lon)
+	if err != nil {
+		return nil, err
+	}
+	c.set(k, sample)
+	return sample, nil
+}
+
+// GetSamples serves cached variables directly and fetches the rest in one underlying call.
+func (c *Cache) GetSamples(
+	ctx context.Context,
+	variables []string,
+	timestamp time.Time,
+	lat float32,
+	lon float32,
+) (map[string]*domain.GridSample, error) {
+	results := make(map[string]*domain.GridSample, len(variables))
+	var hits, missing []string
+	for _, variable := range variables {
+		if sample, ok := c.get(key{variable: variable, timestamp: timestamp.UnixNano(), lat: lat, lon: lon}); ok {
+			results[variable] = sample
+			hits = append(hits, variable)
+		} else {
+			missing = append(missing, variable)
+		}
+	}
+	c.logLookup(ctx, timestamp, hits, missing)
+	if len(missing) == 0 {
+		return results, nil
+	}
+
+	fetched, err := c.next.GetSamples(ctx, missing, timestamp, lat, lon)
+	if err != nil {
+		return nil, err
+	}
+	for variable, sample := range fetched {
+		c.set(key{variable: variable, timestamp: timestamp.UnixNano(), lat: lat, lon: lon}, sample)
+		results[variable] = sample
+	}
+	return results, nil
+}
+
+// GetCandidates is not cached: it only runs under source precedence, whose choice already
+// depends on per-request lineage lookups.
+func (c *Cache) GetCandidates(
+	ctx context.Context,
+	variables []string,
+	timestamp time.Time,
+	lat float32,
+	lon float32,
+	lookback time.Duration,
+) (map[string][]domain.GridSample, error) {
+	return c.next.GetCandidates(ctx, variables, timestamp, lat, lon, lookback)
+}
+
+// GetSeries is not cached: ranges rarely repeat exactly and can be large.
+func (c *Cache) GetSeries(
+	ctx context.Context,
+	variable string,
+	lat float32,
+	lon float32,
+	from time.Time,
+	to time.Time,
+) ([]domain.GridSample, error) {
+	return c.next.GetSeries(ctx, variable, lat, lon, from, to)
+}
+
+// GetGrid is not cached, for the same reason as GetSeries.
+func (c *Cache) GetGrid(
+	ctx context.Context,
+	variable string,
+	timestamp time.Time,
+	bbox domain.BoundingBox,
+	stride int,
+) ([]domain.GridSample, error) {
+	return c.next.GetGrid(ctx, variable, timestamp, bbox, stride)
+}
+
+func (c *Cache) logLookup(ctx context.Context, timestamp time.Time, hits, misses []string) {
+	if c.logger == nil {
+		return
+	}
+	c.logger.LogAttrs(ctx, slog.LevelDebug, "grid cache lookup",
+		slog.Time("timestamp", timestamp),
+		slog.Any("hits", hits),
+		slog.Any("misses", misses),
+	)
+}
+
+// get returns a copy so callers can't mutate the cached sample.
+func (c *Cache) get(k key) (*domain.GridSample, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	el, ok := c.entries[k]
+	if !ok {
+		c.metrics.lookup(k.variable, false)
+		return nil, false
+	}
+	e := el.Value.(*entry)
+	if !c.now().Before(e.expiresAt) {
+		c.remove(el, evictedExpired)
+		c.metrics.lookup(k.variable, false)
+		return nil, false
+	}
+	c.lru.MoveToFront(el)
+	c.metrics.lookup(k.variable, true)
+	sample := e.sample
+	return &sample, true
+}
+
+func (c *Cache) set(k key, sample *domain.GridSample) {
+	ttl := c.policy.ttl(k.variable)
+	if ttl <= 0 || sample == nil {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	expiresAt := c.now().Add(ttl)
+	if el, ok := c.entries[k]; ok {
+		e := el.Value.(*entry)
+		e.sample, e.expiresAt = *sample, expiresAt
+		c.lru.MoveToFront(el)
+		return
+	}
+	if c.policy.MaxEntries > 0 {
+		for c.lru.Len() >= c.policy.MaxEntries {
+			c.remove(c.lru.Back(), evictedCapacity)
+		}
+	}
+	c.entries[k] = c.lru.PushFront(&entry{key: k, sample: *sample, expiresAt: expiresAt})
+	c.metrics.setEntries(c.lru.Len())
+}
+
+func (c *Cache) remove(el *list.Element, reason string) {
+	c.lru.Remove(el)
+	delete(c.entries, el.Value.(*entry).key)
+	c.metrics.evict(reason)
+	c.metrics.setEntries(c.lru.Len())
+}
+
+var _ domain.GridRetriever = (*Cache)(nil)
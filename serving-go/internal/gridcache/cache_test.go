@@ -0,0 +1,249 @@
+package gridcache
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+
+	"github.com/kacper-wojtaszczyk/jackfruit/serving-go/internal/domain"
+)
+
+type countingRetriever struct {
+	samples map[string]*domain.GridSample
+	err     error
+	calls   [][]string
+}
+
+func (r *countingRetriever) GetSample(_ context.Context, variable string, _ time.Time, _, _ float32) (*domain.GridSample, error) {
+	r.calls = append(r.calls, []string{variable})
+	if r.err != nil {
+		return nil, r.err
+	}
+	if sample, ok := r.samples[variable]; ok {
+		return sample, nil
+	}
+	return nil, domain.ErrGridSampleNotFound
+}
+
+func (r *countingRetriever) GetSamples(_ context.Context, variables []string, _ time.Time, _, _ float32) (map[string]*domain.GridSample, error) {
+	r.calls = append(r.calls, slices.Clone(variables))
+	if r.err != nil {
+		return nil, r.err
+	}
+	results := make(map[string]*domain.GridSample)
+	for _, variable := range variables {
+		if sample, ok := r.samples[variable]; ok {
+			results[variable] = sample
+		}
+	}
+	return results, nil
+}
+
+func (r *countingRetriever) GetCandidates(_ context.Context, variables []string, _ time.Time, _, _ float32, _ time.Duration) (map[string][]domain.GridSample, error) {
+	r.calls = append(r.calls, slices.Clone(variables))
+	return nil, r.err
+}
+
+func (r *countingRetriever) GetSeries(_ context.Context, variable string, _, _ float32, _, _ time.Time) ([]domain.GridSample, error) {
+	r.calls = append(r.calls, []string{variable})
+	return nil, r.err
+}
+
+func (r *countingRetriever) GetGrid(_ context.Context, variable string, _ time.Time, _ domain.BoundingBox, _ int) ([]domain.GridSample, error) {
+	r.calls = append(r.calls, []string{variable})
+	return nil, r.err
+}
+
+var testTimestamp = time.Date(2025, 3, 11, 14, 0, 0, 0, time.UTC)
+
+func newTestCache(next domain.GridRetriever, policy Policy) (*Cache, *time.Time) {
+	now := testTimestamp
+	c := New(next, policy)
+	c.now = func() time.Time { return now }
+	return c, &now
+}
+
+func TestCache_GetSample_HitsWithinTTL(t *testing.T) {
+	next := &countingRetriever{samples: map[string]*domain.GridSample{"pm2p5": {Value: 12.5}}}
+	c, now := newTestCache(next, Policy{DefaultTTL: time.Hour})
+
+	for range 2 {
+		sample, err := c.GetSample(t.Context(), "pm2p5", testTimestamp, 52.5, 13.4)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if sample.Value != 12.5 {
+			t.Errorf("expected value 12.5, got %v", sample.Value)
+		}
+	}
+	if len(next.calls) != 1 {
+		t.Errorf("expected 1 underlying call, got %d", len(next.calls))
+	}
+
+	*now = now.Add(time.Hour)
+	if _, err := c.GetSample(t.Context(), "pm2p5", testTimestamp, 52.5, 13.4); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(next.calls) != 2 {
+		t.Errorf("expected expired entry to be refetched, got %d calls", len(next.calls))
+	}
+}
+
+func TestCache_GetSample_ReturnsCopy(t *testing.T) {
+	next := &countingRetriever{samples: map[string]*domain.GridSample{"pm2p5": {Value: 12.5}}}
+	c, _ := newTestCache(next, Policy{DefaultTTL: time.Hour})
+
+	sample, _ := c.GetSample(t.Context(), "pm2p5", testTimestamp, 52.5, 13.4)
+	sample.Value = 99
+
+	cached, _ := c.GetSample(t.Context(), "pm2p5", testTimestamp, 52.5, 13.4)
+	if cached.Value != 12.5 {
+		t.Errorf("cached sample was mutated through a returned pointer: %v", cached.Value)
+	}
+}
+
+func TestCache_DoesNotCacheMissesOrErrors(t *testing.T) {
+	next := &countingRetriever{err: errors.New("connection reset")}
+	c, _ := newTestCache(next, Policy{DefaultTTL: time.Hour})
+
+	for range 2 {
+		if _, err := c.GetSample(t.Context(), "pm2p5", testTimestamp, 52.5, 13.4); err == nil {
+			t.Fatal("expected error")
+		}
+	}
+	next.err = nil
+	for range 2 {
+		if _, err := c.GetSample(t.Context(), "pm10", testTimestamp, 52.5, 13.4); !errors.Is(err, domain.ErrGridSampleNotFound) {
+			t.Fatalf("expected ErrGridSampleNotFound, got: %v", err)
+		}
+	}
+	if len(next.calls) != 4 {
+		t.Errorf("expected every call to reach the retriever, got %d", len(next.calls))
+	}
+}
+
+func TestCache_GetSamples_FetchesOnlyMissing(t *testing.T) {
+	next := &countingRetriever{samples: map[string]*domain.GridSample{
+		"pm2p5": {Value: 12.5},
+		"pm10":  {Value: 20},
+	}}
+	c, _ := newTestCache(next, Policy{DefaultTTL: time.Hour})
+
+	if _, err := c.GetSample(t.Context(), "pm2p5", testTimestamp, 52.5, 13.4); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	samples, err := c.GetSamples(t.Context(), []string{"pm2p5", "pm10", "no2"}, testTimestamp, 52.5, 13.4)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(samples) != 2 || samples["pm2p5"].Value != 12.5 || samples["pm10"].Value != 20 {
+		t.Errorf("unexpected samples: %v", samples)
+	}
+	if got := next.calls[len(next.calls)-1]; !slices.Equal(got, []string{"pm10", "no2"}) {
+		t.Errorf("expected batch for uncached variables only, got %v", got)
+	}
+}
+
+func TestCache_LogsLookups(t *testing.T) {
+	var buf bytes.Buffer
+	next := &countingRetriever{samples: map[string]*domain.GridSample{"pm2p5": {Value: 12.5}}}
+	c := New(next, Policy{DefaultTTL: time.Hour}, WithLogger(slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))))
+
+	for range 2 {
+		if _, err := c.GetSamples(t.Context(), []string{"pm2p5", "no2"}, testTimestamp, 52.5, 13.4); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 || !strings.Contains(lines[0], "misses=\"[pm2p5 no2]\"") || !strings.Contains(lines[1], "hits=[pm2p5] misses=[no2]") {
+		t.Errorf("unexpected lookup log: %q", buf.String())
+	}
+}
+
+func TestCache_PerVariableTTL(t *testing.T) {
+	next := &countingRetriever{samples: map[string]*domain.GridSample{"pm2p5": {Value: 1}, "no2": {Value: 2}}}
+	c, now := newTestCache(next, Policy{
+		DefaultTTL: time.Hour,
+		TTLs:       map[string]time.Duration{"pm2p5": 3 * time.Hour, "no2": 0},
+	})
+
+	for _, variable := range []string{"pm2p5", "no2"} {
+		_, _ = c.GetSample(t.Context(), variable, testTimestamp, 52.5, 13.4)
+	}
+	*now = now.Add(2 * time.Hour)
+	for _, variable := range []string{"pm2p5", "no2"} {
+		_, _ = c.GetSample(t.Context(), variable, testTimestamp, 52.5, 13.4)
+	}
+
+	want := [][]string{{"pm2p5"}, {"no2"}, {"no2"}}
+	if !slices.EqualFunc(next.calls, want, slices.Equal) {
+		t.Errorf("expected calls %v, got %v", want, next.calls)
+	}
+}
+
+func TestCache_EvictsLeastRecentlyUsed(t *testing.T) {
+	next := &countingRetriever{samples: map[string]*domain.GridSample{"pm2p5": {Value: 1}}}
+	c, _ := newTestCache(next, Policy{DefaultTTL: time.Hour, MaxEntries: 2})
+
+	_, _ = c.GetSample(t.Context(), "pm2p5", testTimestamp, 52.5, 13.4)
+	_, _ = c.GetSample(t.Context(), "pm2p5", testTimestamp, 48.1, 11.6)
+	_, _ = c.GetSample(t.Context(), "pm2p5", testTimestamp, 52.5, 13.4)
+	_, _ = c.GetSample(t.Context(), "pm2p5", testTimestamp, 50.1, 8.7)
+
+	if len(c.entries) != 2 {
+		t.Fatalf("expected cache to stay at 2 entries, got %d", len(c.entries))
+	}
+	if _, ok := c.entries[key{variable: "pm2p5", timestamp: testTimestamp.UnixNano(), lat: 48.1, lon: 11.6}]; ok {
+		t.Error("expected least recently used entry to be evicted")
+	}
+	if _, ok := c.entries[key{variable: "pm2p5", timestamp: testTimestamp.UnixNano(), lat: 52.5, lon: 13.4}]; !ok {
+		t.Error("expected recently read entry to survive eviction")
+	}
+}
+
+func TestCache_RecordsMetrics(t *testing.T) {
+	next := &countingRetriever{samples: map[string]*domain.GridSample{"pm2p5": {Value: 1}}}
+	metrics := NewMetrics(prometheus.NewRegistry())
+	c := New(next, Policy{DefaultTTL: time.Hour, MaxEntries: 1}, WithMetrics(metrics))
+
+	_, _ = c.GetSample(t.Context(), "pm2p5", testTimestamp, 52.5, 13.4)
+	_, _ = c.GetSample(t.Context(), "pm2p5", testTimestamp, 52.5, 13.4)
+	_, _ = c.GetSample(t.Context(), "pm2p5", testTimestamp, 48.1, 11.6)
+
+	if got := testutil.ToFloat64(metrics.lookups.WithLabelValues("pm2p5", "hit")); got != 1 {
+		t.Errorf("expected 1 hit, got %v", got)
+	}
+	if got := testutil.ToFloat64(metrics.lookups.WithLabelValues("pm2p5", "miss")); got != 2 {
+		t.Errorf("expected 2 misses, got %v", got)
+	}
+	if got := testutil.ToFloat64(metrics.evictions.WithLabelValues(evictedCapacity)); got != 1 {
+		t.Errorf("expected 1 capacity eviction, got %v", got)
+	}
+	if got := testutil.ToFloat64(metrics.entries); got != 1 {
+		t.Errorf("expected 1 entry, got %v", got)
+	}
+}
+
+func TestCache_BucketsUnknownVariables(t *testing.T) {
+	metrics := NewMetrics(prometheus.NewRegistry())
+	c := New(&countingRetriever{}, Policy{DefaultTTL: time.Hour}, WithMetrics(metrics))
+
+	_, _ = c.GetSample(t.Context(), "made_up_1", testTimestamp, 52.5, 13.4)
+	_, _ = c.GetSample(t.Context(), "made_up_2", testTimestamp, 52.5, 13.4)
+
+	if got := testutil.CollectAndCount(metrics.lookups); got != 1 {
+		t.Errorf("expected one series for unknown variables, got %d", got)
+	}
+	if got := testutil.ToFloat64(metrics.lookups.WithLabelValues(domain.OtherVariable, "miss")); got != 2 {
+		t.Errorf("expected 2 misses labelled other, got %v", got)
+	}
+}
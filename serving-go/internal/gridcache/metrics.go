@@ -0,0 +1,63 @@
+package gridcache
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/kacper-wojtaszczyk/jackfruit/serving-go/internal/domain"
+)
+
+const (
+	evictedExpired  = "expired"
+	evictedCapacity = "capacity"
+)
+
+// Metrics holds the cache's Prometheus collectors. A nil *Metrics records nothing.
+type Metrics struct {
+	lookups   *prometheus.CounterVec
+	evictions *prometheus.CounterVec
+	entries   prometheus.Gauge
+}
+
+func NewMetrics(reg prometheus.Registerer) *Metrics {
+	m := &Metrics{
+		lookups: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "grid_cache_lookups_total",
+			Help: "Grid cache lookups by variable (unknown names as other) and result (hit or miss).",
+		}, []string{"variable", "result"}),
+		evictions: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "grid_cache_evictions_total",
+			Help: "Grid cache entries removed before reuse, by reason (expired or capacity).",
+		}, []string{"reason"}),
+		entries: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: "grid_cache_entries",
+			Help: "Samples currently held in the grid cache.",
+		}),
+	}
+	reg.MustRegister(m.lookups, m.evictions, m.entries)
+	return m
+}
+
+func (m *Metrics) lookup(variable string, hit bool) {
+	if m == nil {
+		return
+	}
+	result := "miss"
+	if hit {
+		result = "hit"
+	}
+	m.lookups.WithLabelValues(domain.MetricVariable(variable), result).Inc()
+}
+
+func (m *Metrics) evict(reason string) {
+	if m == nil {
+		return
+	}
+	m.evictions.WithLabelValues(reason).Inc()
+}
+
+func (m *Metrics) setEntries(n int) {
+	if m == nil {
+		return
+	}
+	m.entries.Set(float64(n))
+}
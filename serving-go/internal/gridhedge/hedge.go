@@ -0,0 +1,129 @@
+// Package gridhedge cuts tail latency of any domain.GridRetriever by issuing a duplicate call
+// when the first is slow and taking whichever answers first. It only pays off when the
+// duplicate can land elsewhere, e.g. on another ClickHouse replica.
+package gridhedge
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/kacper-wojtaszczyk/jackfruit/serving-go/internal/domain"
+)
+
+// Retriever wraps a GridRetriever. All GridRetriever calls are reads, so duplicating
+// them is safe; the losing call is canceled once a winner returns.
+type Retriever struct {
+	next  domain.GridRetriever
+	delay time.Duration
+}
+
+// New hedges calls slower than delay, typically the store's p95 latency. A delay of zero
+// or less disables hedging.
+func New(next domain.GridRetriever, delay time.Duration) *Retriever {
+	return &Retriever{next: next, delay: delay}
+}
+
+func (r *Retriever) GetSample(
+	ctx context.Context,
+	variable string,
+	timestamp time.Time,
+	lat float32,
+	lon float32,
+) (*domain.GridSample, error) {
+	return hedge(ctx, r.delay, func(ctx context.Context) (*domain.GridSample, error) {
+		return r.next.GetSample(ctx, variable, timestamp, lat, lon)
+	})
+}
+
+func (r *Retriever) GetSamples(
+	ctx context.Context,
+	variables []string,
+	timestamp time.Time,
+	lat float32,
+	lon float32,
+) (map[string]*domain.GridSample, error) {
+	return hedge(ctx, r.delay, func(ctx context.Context) (map[string]*domain.GridSample, error) {
+		return r.next.GetSamples(ctx, variables, timestamp, lat, lon)
+	})
+}
+
+func (r *Retriever) GetCandidates(
+	ctx context.Context,
+	variables []string,
+	timestamp time.Time,
+	lat float32,
+	lon float32,
+	lookback time.Duration,
+) (map[string][]domain.GridSample, error) {
+	return hedge(ctx, r.delay, func(ctx context.Context) (map[string][]domain.GridSample, error) {
+		return r.next.GetCandidates(ctx, variables, timestamp, lat, lon, lookback)
+	})
+}
+
+func (r *Retriever) GetSeries(
+	ctx context.Context,
+	variable string,
+	lat float32,
+	lon float32,
+	from time.Time,
+	to time.Time,
+) ([]domain.GridSample, error) {
+	return hedge(ctx, r.delay, func(ctx context.Context) ([]domain.GridSample, error) {
+		return r.next.GetSeries(ctx, variable, lat, lon, from, to)
+	})
+}
+
+func (r *Retriever) GetGrid(
+	ctx context.Context,
+	variable string,
+	timestamp time.Time,
+	bbox domain.BoundingBox,
+	stride int,
+) ([]domain.GridSample, error) {
+	return hedge(ctx, r.delay, func(ctx context.Context) ([]domain.GridSample, error) {
+		return r.next.GetGrid(ctx, variable, timestamp, bbox, stride)
+	})
+}
+
+type result[T any] struct {
+	value T
+	err   error
+}
+
+// hedge runs call, and again if it hasn't returned after delay. Once duplicated, the first
+// success wins; ErrGridSampleNotFound is an answer too and wins like one. If both calls fail,
+// the later error is returned. A call failing before delay is not duplicated: hedging is for
+// slowness, retrying errors is the store's job.
+func hedge[T any](ctx context.Context, delay time.Duration, call func(ctx context.Context) (T, error)) (T, error) {
+	if delay <= 0 {
+		return call(ctx)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	results := make(chan result[T], 2)
+	run := func() {
+		value, err := call(ctx)
+		results <- result[T]{value: value, err: err}
+	}
+	go run()
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case first := <-results:
+		return first.value, first.err
+	case <-timer.C:
+	}
+
+	go run()
+	first := <-results
+	if first.err == nil || errors.Is(first.err, domain.ErrGridSampleNotFound) {
+		return first.value, first.err
+	}
+	second := <-results
+	return second.value, second.err
+}
+
+var _ domain.GridRetriever = (*Retriever)(nil)
@@ -0,0 +1,122 @@
+package gridhedge
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/kacper-wojtaszczyk/jackfruit/serving-go/internal/domain"
+)
+
+// scriptedCall answers the n-th call (0-based) after delays[n] with errs[n], or with ctx's
+// error if canceled first.
+type scriptedCall struct {
+	delays   []time.Duration
+	errs     []error
+	calls    atomic.Int32
+	canceled atomic.Int32
+}
+
+func (s *scriptedCall) call(ctx context.Context) (int, error) {
+	n := int(s.calls.Add(1)) - 1
+	select {
+	case <-time.After(s.delays[n]):
+		return n, s.errs[n]
+	case <-ctx.Done():
+		s.canceled.Add(1)
+		return n, ctx.Err()
+	}
+}
+
+func TestHedge(t *testing.T) {
+	tests := []struct {
+		name      string
+		delays    []time.Duration
+		errs      []error
+		wantValue int
+		wantErr   error
+		wantCalls int32
+	}{
+		{
+			name:      "fast call is not duplicated",
+			delays:    []time.Duration{0},
+			errs:      []error{nil},
+			wantValue: 0,
+			wantCalls: 1,
+		},
+		{
+			name:      "slow call loses to hedge",
+			delays:    []time.Duration{time.Hour, 0},
+			errs:      []error{nil, nil},
+			wantValue: 1,
+			wantCalls: 2,
+		},
+		{
+			name:      "not found wins",
+			delays:    []time.Duration{time.Hour, 0},
+			errs:      []error{nil, domain.ErrGridSampleNotFound},
+			wantValue: 1,
+			wantErr:   domain.ErrGridSampleNotFound,
+			wantCalls: 2,
+		},
+		{
+			name:      "failed hedge waits for original",
+			delays:    []time.Duration{100 * time.Millisecond, 0},
+			errs:      []error{nil, errors.New("replica down")},
+			wantValue: 0,
+			wantCalls: 2,
+		},
+		{
+			name:      "early failure is not duplicated",
+			delays:    []time.Duration{0},
+			errs:      []error{errors.New("table does not exist")},
+			wantValue: 0,
+			wantErr:   errors.New("table does not exist"),
+			wantCalls: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			script := &scriptedCall{delays: tt.delays, errs: tt.errs}
+			value, err := hedge(t.Context(), 10*time.Millisecond, script.call)
+
+			if (err == nil) != (tt.wantErr == nil) || err != nil && err.Error() != tt.wantErr.Error() {
+				t.Errorf("expected error %v, got %v", tt.wantErr, err)
+			}
+			if value != tt.wantValue {
+				t.Errorf("expected result of call %d, got %d", tt.wantValue, value)
+			}
+			if got := script.calls.Load(); got != tt.wantCalls {
+				t.Errorf("expected %d calls, got %d", tt.wantCalls, got)
+			}
+		})
+	}
+}
+
+func TestHedge_CancelsLoser(t *testing.T) {
+	script := &scriptedCall{delays: []time.Duration{time.Hour, 0}, errs: []error{nil, nil}}
+	if _, err := hedge(t.Context(), time.Millisecond, script.call); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for script.canceled.Load() == 0 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	if script.canceled.Load() != 1 {
+		t.Error("expected the slow call to be canceled once the hedge won")
+	}
+}
+
+func TestHedge_Disabled(t *testing.T) {
+	script := &scriptedCall{delays: []time.Duration{20 * time.Millisecond}, errs: []error{nil}}
+	if _, err := hedge(t.Context(), 0, script.call); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := script.calls.Load(); got != 1 {
+		t.Errorf("expected a single call with hedging disabled, got %d", got)
+	}
+}
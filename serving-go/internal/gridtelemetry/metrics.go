@@ -0,0 +1,49 @@
+package gridtelemetry
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/kacper-wojtaszczyk/jackfruit/serving-go/internal/domain"
+)
+
+// Metrics holds the per-store Prometheus collectors. A nil *Metrics records nothing.
+type Metrics struct {
+	duration *prometheus.HistogramVec
+	samples  *prometheus.CounterVec
+}
+
+func NewMetrics(reg prometheus.Registerer) *Metrics {
+	m := &Metrics{
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "grid_store_call_duration_seconds",
+			Help:    "Grid store call latency by store, method and outcome.",
+			Buckets: []float64{.0005, .001, .005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10, 15},
+		}, []string{"store", "method", "outcome"}),
+		samples: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "grid_store_samples_total",
+			Help: "Point lookups per variable (unknown names as other) by store and result (found or missing).",
+		}, []string{"store", "variable", "result"}),
+	}
+	reg.MustRegister(m.duration, m.samples)
+	return m
+}
+
+func (m *Metrics) call(store, method, outcome string, elapsed time.Duration) {
+	if m == nil {
+		return
+	}
+	m.duration.WithLabelValues(store, method, outcome).Observe(elapsed.Seconds())
+}
+
+func (m *Metrics) sample(store, variable string, found bool) {
+	if m == nil {
+		return
+	}
+	result := "missing"
+	if found {
+		result = "found"
+	}
+	m.samples.WithLabelValues(store, domain.MetricVariable(variable), result).Inc()
+}
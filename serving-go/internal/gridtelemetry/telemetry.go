@@ -0,0 +1,212 @@
+// Package gridtelemetry instruments any domain.GridRetriever with Prometheus metrics and
+// OpenTelemetry spans, so the ClickHouse finder, the cache and future stores report alike.
+package gridtelemetry
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/kacper-wojtaszczyk/jackfruit/serving-go/internal/domain"
+)
+
+const tracerName = "github.com/kacper-wojtaszczyk/jackfruit/serving-go/internal/gridtelemetry"
+
+// Retriever wraps a GridRetriever; store names the wrapped implementation in metric
+// labels and span names, e.g. "clickhouse" or "cache".
+type Retriever struct {
+	next    domain.GridRetriever
+	store   string
+	metrics *Metrics
+	tracer  trace.Tracer
+}
+
+type Option func(*Retriever)
+
+// WithMetrics records call latency and per-variable sample outcomes.
+func WithMetrics(m *Metrics) Option {
+	return func(r *Retriever) {
+		r.metrics = m
+	}
+}
+
+// WithTracerProvider emits spans to tp instead of the global otel.GetTracerProvider().
+func WithTracerProvider(tp trace.TracerProvider) Option {
+	return func(r *Retriever) {
+		r.tracer = tp.Tracer(tracerName)
+	}
+}
+
+func New(store string, next domain.GridRetriever, opts ...Option) *Retriever {
+	r := &Retriever{next: next, store: store, tracer: otel.GetTracerProvider().Tracer(tracerName)}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
+}
+
+func (r *Retriever) GetSample(
+	ctx context.Context,
+	variable string,
+	timestamp time.Time,
+	lat float32,
+	lon float32,
+) (*domain.GridSample, error) {
+	ctx, done := r.start(ctx, "GetSample",
+		attribute.String("grid.variable", variable),
+		attribute.String("grid.timestamp", timestamp.UTC().Format(time.RFC3339)),
+		attribute.Float64("grid.lat", float64(lat)),
+		attribute.Float64("grid.lon", float64(lon)),
+	)
+	sample, err := r.next.GetSample(ctx, variable, timestamp, lat, lon)
+	if err == nil || errors.Is(err, domain.ErrGridSampleNotFound) {
+		r.metrics.sample(r.store, variable, err == nil)
+	}
+	done(err)
+	return sample, err
+}
+
+func (r *Retriever) GetSamples(
+	ctx context.Context,
+	variables []string,
+	timestamp time.Time,
+	lat float32,
+	lon float32,
+) (map[string]*domain.GridSample, error) {
+	ctx, done := r.start(ctx, "GetSamples",
+		attribute.StringSlice("grid.variables", variables),
+		attribute.String("grid.timestamp", timestamp.UTC().Format(time.RFC3339)),
+		attribute.Float64("grid.lat", float64(lat)),
+		attribute.Float64("grid.lon", float64(lon)),
+	)
+	samples, err := r.next.GetSamples(ctx, variables, timestamp, lat, lon)
+	if err == nil {
+		for _, variable := range variables {
+			_, found := samples[variable]
+			r.metrics.sample(r.store, variable, found)
+		}
+		trace.SpanFromContext(ctx).SetAttributes(attribute.Int("grid.samples_found", len(samples)))
+	}
+	done(err)
+	return samples, err
+}
+
+func (r *Retriever) GetCandidates(
+	ctx context.Context,
+	variables []string,
+	timestamp time.Time,
+	lat float32,
+	lon float32,
+	lookback time.Duration,
+) (map[string][]domain.GridSample, error) {
+	ctx, done := r.start(ctx, "GetCandidates",
+		attribute.StringSlice("grid.variables", variables),
+		attribute.String("grid.timestamp", timestamp.UTC().Format(time.RFC3339)),
+		attribute.Float64("grid.lat", float64(lat)),
+		attribute.Float64("grid.lon", float64(lon)),
+		attribute.String("grid.lookback", lookback.String()),
+	)
+	candidates, err := r.next.GetCandidates(ctx, variables, timestamp, lat, lon, lookback)
+	if err == nil {
+		for _, variable := range variables {
+			_, found := candidates[variable]
+			r.metrics.sample(r.store, variable, found)
+		}
+	}
+	done(err)
+	return candidates, err
+}
+
+func (r *Retriever) GetSeries(
+	ctx context.Context,
+	variable string,
+	lat float32,
+	lon float32,
+	from time.Time,
+	to time.Time,
+) ([]domain.GridSample, error) {
+	ctx, done := r.start(ctx, "GetSeries",
+		attribute.String("grid.variable", variable),
+		attribute.Float64("grid.lat", float64(lat)),
+		attribute.Float64("grid.lon", float64(lon)),
+		attribute.String("grid.from", from.UTC().Format(time.RFC3339)),
+		attribute.String("grid.to", to.UTC().Format(time.RFC3339)),
+	)
+	series, err := r.next.GetSeries(ctx, variable, lat, lon, from, to)
+	trace.SpanFromContext(ctx).SetAttributes(attribute.Int("grid.samples_found", len(series)))
+	done(err)
+	return series, err
+}
+
+func (r *Retriever) GetGrid(
+	ctx context.Context,
+	variable string,
+	timestamp time.Time,
+	bbox domain.BoundingBox,
+	stride int,
+) ([]domain.GridSample, error) {
+	ctx, done := r.start(ctx, "GetGrid",
+		attribute.String("grid.variable", variable),
+		attribute.String("grid.timestamp", timestamp.UTC().Format(time.RFC3339)),
+		attribute.Float64Slice("grid.bbox", []float64{
+			float64(bbox.MinLat), float64(bbox.MinLon), float64(bbox.MaxLat), float64(bbox.MaxLon),
+		}),
+		attribute.Int("grid.stride", stride),
+	)
+	cells, err := r.next.GetGrid(ctx, variable, timestamp, bbox, stride)
+	trace.SpanFromContext(ctx).SetAttributes(attribute.Int("grid.samples_found", len(cells)))
+	done(err)
+	return cells, err
+}
+
+// start opens a span for method; the returned func ends it and records the call's outcome.
+func (r *Retriever) start(ctx context.Context, method string, attrs ...attribute.KeyValue) (context.Context, func(error)) {
+	ctx, span := r.tracer.Start(ctx, r.store+"."+method,
+		trace.WithSpanKind(trace.SpanKindInternal),
+		trace.WithAttributes(append(attrs, attribute.String("grid.store", r.store))...),
+	)
+	start := time.Now()
+
+	return ctx, func(err error) {
+		class := errorClass(err)
+		r.metrics.call(r.store, method, class, time.Since(start))
+		span.SetAttributes(attribute.String("grid.outcome", class))
+		if class != outcomeOK && class != outcomeNotFound {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, class)
+		}
+		span.End()
+	}
+}
+
+const (
+	outcomeOK       = "ok"
+	outcomeNotFound = "not_found"
+	outcomeTimeout  = "timeout"
+	outcomeCanceled = "canceled"
+	outcomeError    = "error"
+)
+
+// errorClass buckets err into a low-cardinality outcome label. Not found is an answer,
+// not a failure, so it doesn't mark spans as errored.
+func errorClass(err error) string {
+	switch {
+	case err == nil:
+		return outcomeOK
+	case errors.Is(err, domain.ErrGridSampleNotFound):
+		return outcomeNotFound
+	case errors.Is(err, context.DeadlineExceeded):
+		return outcomeTimeout
+	case errors.Is(err, context.Canceled):
+		return outcomeCanceled
+	default:
+		return outcomeError
+	}
+}
+
+var _ domain.GridRetriever = (*Retriever)(nil)
@@ -0,0 +1,154 @@
+package gridtelemetry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+
+	"github.com/kacper-wojtaszczyk/jackfruit/serving-go/internal/domain"
+)
+
+type stubRetriever struct {
+	samples map[string]*domain.GridSample
+	err     error
+}
+
+func (r *stubRetriever) GetSample(_ context.Context, variable string, _ time.Time, _, _ float32) (*domain.GridSample, error) {
+	if r.err != nil {
+		return nil, r.err
+	}
+	if sample, ok := r.samples[variable]; ok {
+		return sample, nil
+	}
+	return nil, domain.ErrGridSampleNotFound
+}
+
+func (r *stubRetriever) GetSamples(_ context.Context, variables []string, _ time.Time, _, _ float32) (map[string]*domain.GridSample, error) {
+	if r.err != nil {
+		return nil, r.err
+	}
+	results := make(map[string]*domain.GridSample)
+	for _, variable := range variables {
+		if sample, ok := r.samples[variable]; ok {
+			results[variable] = sample
+		}
+	}
+	return results, nil
+}
+
+func (r *stubRetriever) GetCandidates(context.Context, []string, time.Time, float32, float32, time.Duration) (map[string][]domain.GridSample, error) {
+	return nil, r.err
+}
+
+func (r *stubRetriever) GetSeries(context.Context, string, float32, float32, time.Time, time.Time) ([]domain.GridSample, error) {
+	return nil, r.err
+}
+
+func (r *stubRetriever) GetGrid(context.Context, string, time.Time, domain.BoundingBox, int) ([]domain.GridSample, error) {
+	return nil, r.err
+}
+
+var testTimestamp = time.Date(2025, 3, 11, 14, 0, 0, 0, time.UTC)
+
+func TestErrorClass(t *testing.T) {
+	tests := []struct {
+		err  error
+		want string
+	}{
+		{err: nil, want: outcomeOK},
+		{err: fmt.Errorf("query: %w", domain.ErrGridSampleNotFound), want: outcomeNotFound},
+		{err: fmt.Errorf("query: %w", context.DeadlineExceeded), want: outcomeTimeout},
+		{err: context.Canceled, want: outcomeCanceled},
+		{err: errors.New("connection reset"), want: outcomeError},
+	}
+	for _, tt := range tests {
+		if got := errorClass(tt.err); got != tt.want {
+			t.Errorf("errorClass(%v) = %q, want %q", tt.err, got, tt.want)
+		}
+	}
+}
+
+func TestRetriever_RecordsMetrics(t *testing.T) {
+	next := &stubRetriever{samples: map[string]*domain.GridSample{"pm2p5": {Value: 12.5}}}
+	metrics := NewMetrics(prometheus.NewRegistry())
+	r := New("clickhouse", next, WithMetrics(metrics))
+
+	sample, err := r.GetSample(t.Context(), "pm2p5", testTimestamp, 52.5, 13.4)
+	if err != nil || sample.Value != 12.5 {
+		t.Fatalf("expected sample to pass through, got %v, %v", sample, err)
+	}
+	if _, err := r.GetSamples(t.Context(), []string{"pm2p5", "no2"}, testTimestamp, 52.5, 13.4); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := r.GetSample(t.Context(), "no2", testTimestamp, 52.5, 13.4); !errors.Is(err, domain.ErrGridSampleNotFound) {
+		t.Fatalf("expected ErrGridSampleNotFound to pass through, got %v", err)
+	}
+
+	if got := testutil.ToFloat64(metrics.samples.WithLabelValues("clickhouse", "pm2p5", "found")); got != 2 {
+		t.Errorf("expected 2 found pm2p5 lookups, got %v", got)
+	}
+	if got := testutil.ToFloat64(metrics.samples.WithLabelValues("clickhouse", domain.OtherVariable, "missing")); got != 2 {
+		t.Errorf("expected 2 missing no2 lookups labelled other, got %v", got)
+	}
+	if got := testutil.CollectAndCount(metrics.duration); got != 3 {
+		t.Errorf("expected 3 duration series (GetSample/ok, GetSamples/ok, GetSample/not_found), got %d", got)
+	}
+}
+
+func TestRetriever_ErrorsSkipSampleCounts(t *testing.T) {
+	metrics := NewMetrics(prometheus.NewRegistry())
+	r := New("cache", &stubRetriever{err: context.DeadlineExceeded}, WithMetrics(metrics))
+
+	if _, err := r.GetSamples(t.Context(), []string{"pm2p5"}, testTimestamp, 52.5, 13.4); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected error to pass through, got %v", err)
+	}
+	if got := testutil.CollectAndCount(metrics.samples); got != 0 {
+		t.Errorf("expected no sample counts for a failed call, got %d series", got)
+	}
+	if got := testutil.CollectAndCount(metrics.duration); got != 1 {
+		t.Errorf("expected 1 duration series, got %d", got)
+	}
+}
+
+// recordingProvider counts spans started by its tracers.
+type recordingProvider struct {
+	noop.TracerProvider
+	spans int
+}
+
+func (p *recordingProvider) Tracer(string, ...trace.TracerOption) trace.Tracer {
+	return recordingTracer{provider: p}
+}
+
+type recordingTracer struct {
+	noop.Tracer
+	provider *recordingProvider
+}
+
+func (t recordingTracer) Start(ctx context.Context, name string, opts ...trace.SpanStartOption) (context.Context, trace.Span) {
+	t.provider.spans++
+	return t.Tracer.Start(ctx, name, opts...)
+}
+
+func TestRetriever_DefaultsToGlobalTracerProvider(t *testing.T) {
+	previous := otel.GetTracerProvider()
+	t.Cleanup(func() { otel.SetTracerProvider(previous) })
+	provider := &recordingProvider{}
+	otel.SetTracerProvider(provider)
+
+	r := New("clickhouse", &stubRetriever{samples: map[string]*domain.GridSample{"pm2p5": {Value: 1}}})
+	if _, err := r.GetSample(t.Context(), "pm2p5", testTimestamp, 52.5, 13.4); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if provider.spans != 1 {
+		t.Errorf("expected 1 span on the global provider, got %d", provider.spans)
+	}
+}
@@ -0,0 +1,197 @@
+package lineage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/kacper-wojtaszczyk/jackfruit/serving-go/internal/domain"
+)
+
+const catalogColumns = `
+        SELECT cd.id, cd.variable, cd.unit, cd.timestamp, cd.created_at,
+               rf.id, rf.source, rf.dataset, rf.date, rf.s3_key, rf.created_at
+        FROM catalog.curated_data cd
+        JOIN catalog.raw_files rf ON rf.id = cd.raw_file_id`
+
+type scanner interface {
+	Scan(dest ...any) error
+}
+
+func scanCatalogEntry(row scanner) (domain.CatalogEntry, error) {
+	var entry domain.CatalogEntry
+	err := row.Scan(
+		&entry.ID, &entry.Variable, &entry.Unit, &entry.Timestamp, &entry.CreatedAt,
+		&entry.RawFile.ID, &entry.RawFile.Source, &entry.RawFile.Dataset, &entry.RawFile.Date,
+		&entry.RawFile.S3Key, &entry.RawFile.CreatedAt,
+	)
+	return entry, err
+}
+
+func (f *Finder) GetCatalogEntry(ctx context.Context, id uuid.UUID) (*domain.CatalogEntry, error) {
+	entry, err := scanCatalogEntry(f.db.QueryRowContext(ctx, catalogColumns+`
+        WHERE cd.id = $1`, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, domain.ErrCatalogEntryNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("catalog query for %s: %w", id, err)
+	}
+
+	return &entry, nil
+}
+
+func (f *Finder) ListCatalogEntries(ctx context.Context, filter domain.CatalogFilter) ([]domain.CatalogEntry, error) {
+	var where []string
+	var args []any
+	add := func(condition string, arg any) {
+		args = append(args, arg)
+		where = append(where, strings.ReplaceAll(condition, "?", "$"+strconv.Itoa(len(args))))
+	}
+	if filter.RawFileID != uuid.Nil {
+		add("cd.raw_file_id = ?", filter.RawFileID)
+	}
+	if filter.Variable != "" {
+		add("cd.variable = ?", filter.Variable)
+	}
+	if filter.Dataset != "" {
+		add("rf.dataset = ?", filter.Dataset)
+	}
+	if !filter.From.IsZero() {
+		add("cd.timestamp >= ?", filter.From)
+	}
+	if !filter.To.IsZero() {
+		add("cd.timestamp <= ?", filter.To)
+	}
+
+	query := catalogColumns
+	if len(where) > 0 {
+		query += `
+        WHERE ` + strings.Join(where, " AND ")
+	}
+	args = append(args, filter.Limit)
+	query += `
+        ORDER BY cd.timestamp DESC, cd.id DESC
+        LIMIT $` + strconv.Itoa(len(args))
+
+	rows, err := f.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("catalog list query: %w", err)
+	}
+	defer rows.Close()
+
+	entries := []domain.CatalogEntry{}
+	for rows.Next() {
+		entry, err := scanCatalogEntry(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan catalog row: %w", err)
+		}
+		entries = append(entries, entry)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate catalog rows: %w", err)
+	}
+
+	return entries, nil
+}
+
+func (f *Finder) GetRawFile(ctx context.Context, id uuid.UUID) (*domain.RawFile, error) {
+	const query = `
+        SELECT id, source, dataset, date, s3_key, created_at
+        FROM catalog.raw_files
+        WHERE id = $1
+    `
+	var rawFile domain.RawFile
+	err := f.db.QueryRowContext(ctx, query, id).Scan(
+		&rawFile.ID, &rawFile.Source, &rawFile.Dataset, &rawFile.Date, &rawFile.S3Key, &rawFile.CreatedAt,
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, domain.ErrRawFileNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("raw file query for %s: %w", id, err)
+	}
+
+	return &rawFile, nil
+}
+
+func (f *Finder) ListDeliveries(ctx context.Context, dataset string, from, to time.Time) ([]domain.Delivery, error) {
+	const query = `
+        SELECT rf.id, rf.source, rf.dataset, rf.date, rf.s3_key, rf.created_at, count(cd.id)
+        FROM catalog.raw_files rf
+        LEFT JOIN catalog.curated_data cd ON cd.raw_file_id = rf.id
+        WHERE rf.dataset = $1 AND rf.date BETWEEN $2 AND $3
+        GROUP BY rf.id
+        ORDER BY rf.date, rf.created_at
+    `
+	rows, err := f.db.QueryContext(ctx, query, dataset, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("delivery query for %q: %w", dataset, err)
+	}
+	defer rows.Close()
+
+	deliveries := []domain.Delivery{}
+	for rows.Next() {
+		var delivery domain.Delivery
+		rawFile := &delivery.RawFile
+		if err := rows.Scan(
+			&rawFile.ID, &rawFile.Source, &rawFile.Dataset, &rawFile.Date, &rawFile.S3Key, &rawFile.CreatedAt,
+			&delivery.Entries,
+		); err != nil {
+			return nil, fmt.Errorf("scan delivery row: %w", err)
+		}
+		deliveries = append(deliveries, delivery)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate delivery rows: %w", err)
+	}
+
+	return deliveries, nil
+}
+
+func (f *Finder) GetLatestVariableLoads(ctx context.Context) (map[string]time.Time, error) {
+	const query = `
+        SELECT variable, max(created_at)
+        FROM catalog.curated_data
+        GROUP BY variable
+    `
+	return f.latestLoads(ctx, "variable", query)
+}
+
+func (f *Finder) GetLatestDatasetLoads(ctx context.Context) (map[string]time.Time, error) {
+	const query = `
+        SELECT dataset, max(created_at)
+        FROM catalog.raw_files
+        GROUP BY dataset
+    `
+	return f.latestLoads(ctx, "dataset", query)
+}
+
+// latestLoads runs a query returning a name and its newest created_at per row.
+func (f *Finder) latestLoads(ctx context.Context, what, query string) (map[string]time.Time, error) {
+	rows, err := f.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("latest %s load query: %w", what, err)
+	}
+	defer rows.Close()
+
+	loads := make(map[string]time.Time)
+	for rows.Next() {
+		var name string
+		var createdAt time.Time
+		if err := rows.Scan(&name, &createdAt); err != nil {
+			return nil, fmt.Errorf("scan latest %s load row: %w", what, err)
+		}
+		loads[name] = createdAt
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate latest %s load rows: %w", what, err)
+	}
+
+	return loads, nil
+}
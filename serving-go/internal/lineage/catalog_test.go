@@ -0,0 +1,217 @@
+package lineage
+
+import (
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/kacper-wojtaszczyk/jackfruit/serving-go/internal/domain"
+)
+
+var catalogRowColumns = []string{
+	"id", "variable", "unit", "timestamp", "created_at",
+	"id", "source", "dataset", "date", "s3_key", "created_at",
+}
+
+func TestGetCatalogEntry_Found(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	catalogID, rawFileID := uuid.New(), uuid.New()
+	timestamp := time.Date(2025, 3, 11, 14, 0, 0, 0, time.UTC)
+	mock.ExpectQuery("FROM catalog\\.curated_data cd\\s+JOIN catalog\\.raw_files rf ON rf\\.id = cd\\.raw_file_id\\s+WHERE cd\\.id = \\$1").
+		WithArgs(catalogID).
+		WillReturnRows(sqlmock.NewRows(catalogRowColumns).AddRow(
+			catalogID, "pm2p5", "µg/m³", timestamp, timestamp,
+			rawFileID, "ads", "cams-europe-air-quality-forecast", timestamp, "ads/cams/2025-03-11/run.grib", timestamp,
+		))
+
+	entry, err := NewFinder(db).GetCatalogEntry(t.Context(), catalogID)
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if entry.Variable != "pm2p5" || entry.RawFile.ID != rawFileID || entry.RawFile.S3Key != "ads/cams/2025-03-11/run.grib" {
+		t.Errorf("unexpected entry %+v", entry)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled expectations: %v", err)
+	}
+}
+
+func TestGetCatalogEntry_NotFound(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	catalogID := uuid.New()
+	mock.ExpectQuery("FROM catalog\\.curated_data").WithArgs(catalogID).WillReturnError(sql.ErrNoRows)
+
+	_, err = NewFinder(db).GetCatalogEntry(t.Context(), catalogID)
+	if !errors.Is(err, domain.ErrCatalogEntryNotFound) {
+		t.Errorf("expected ErrCatalogEntryNotFound, got: %v", err)
+	}
+}
+
+func TestListCatalogEntries_Filters(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	from := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	mock.ExpectQuery("WHERE cd\\.variable = \\$1 AND rf\\.dataset = \\$2 AND cd\\.timestamp >= \\$3\\s+ORDER BY cd\\.timestamp DESC, cd\\.id DESC\\s+LIMIT \\$4").
+		WithArgs("pm2p5", "cams-europe-air-quality-forecast", from, 10).
+		WillReturnRows(sqlmock.NewRows(catalogRowColumns))
+
+	entries, err := NewFinder(db).ListCatalogEntries(t.Context(), domain.CatalogFilter{
+		Variable: "pm2p5",
+		Dataset:  "cams-europe-air-quality-forecast",
+		From:     from,
+		Limit:    10,
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if entries == nil || len(entries) != 0 {
+		t.Errorf("expected an empty, non-nil list, got %v", entries)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled expectations: %v", err)
+	}
+}
+
+func TestListCatalogEntries_DBError(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	dbErr := errors.New("connection refused")
+	mock.ExpectQuery("LIMIT \\$1").WithArgs(100).WillReturnError(dbErr)
+
+	_, err = NewFinder(db).ListCatalogEntries(t.Context(), domain.CatalogFilter{Limit: 100})
+	if !errors.Is(err, dbErr) {
+		t.Errorf("expected error wrapping original DB error, got: %v", err)
+	}
+}
+
+func TestListCatalogEntries_ByRawFile(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	rawFileID := uuid.New()
+	mock.ExpectQuery("WHERE cd\\.raw_file_id = \\$1\\s+ORDER BY").
+		WithArgs(rawFileID, 1000).
+		WillReturnRows(sqlmock.NewRows(catalogRowColumns))
+
+	if _, err := NewFinder(db).ListCatalogEntries(t.Context(), domain.CatalogFilter{RawFileID: rawFileID, Limit: 1000}); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled expectations: %v", err)
+	}
+}
+
+func TestGetRawFile(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	rawFileID := uuid.New()
+	date := time.Date(2025, 3, 11, 0, 0, 0, 0, time.UTC)
+	mock.ExpectQuery("FROM catalog\\.raw_files\\s+WHERE id = \\$1").
+		WithArgs(rawFileID).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "source", "dataset", "date", "s3_key", "created_at"}).
+			AddRow(rawFileID, "ads", "cams-europe-air-quality-forecast", date, "ads/cams/2025-03-11/run.grib", date))
+	mock.ExpectQuery("FROM catalog\\.raw_files").WithArgs(sqlmock.AnyArg()).WillReturnError(sql.ErrNoRows)
+
+	finder := NewFinder(db)
+	rawFile, err := finder.GetRawFile(t.Context(), rawFileID)
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if rawFile.S3Key != "ads/cams/2025-03-11/run.grib" || rawFile.Dataset != "cams-europe-air-quality-forecast" {
+		t.Errorf("unexpected raw file %+v", rawFile)
+	}
+	if _, err := finder.GetRawFile(t.Context(), uuid.New()); !errors.Is(err, domain.ErrRawFileNotFound) {
+		t.Errorf("expected ErrRawFileNotFound, got: %v", err)
+	}
+}
+
+func TestListDeliveries(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	rawFileID := uuid.New()
+	from, to := time.Date(2025, 3, 5, 0, 0, 0, 0, time.UTC), time.Date(2025, 3, 11, 0, 0, 0, 0, time.UTC)
+	mock.ExpectQuery("FROM catalog\\.raw_files rf\\s+LEFT JOIN catalog\\.curated_data cd .+WHERE rf\\.dataset = \\$1 AND rf\\.date BETWEEN \\$2 AND \\$3").
+		WithArgs("cams-europe-air-quality-forecast", from, to).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "source", "dataset", "date", "s3_key", "created_at", "count"}).
+			AddRow(rawFileID, "ads", "cams-europe-air-quality-forecast", to, "ads/cams/2025-03-11/run.grib", to, 96))
+
+	deliveries, err := NewFinder(db).ListDeliveries(t.Context(), "cams-europe-air-quality-forecast", from, to)
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if len(deliveries) != 1 || deliveries[0].RawFile.ID != rawFileID || deliveries[0].Entries != 96 {
+		t.Errorf("unexpected deliveries %+v", deliveries)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled expectations: %v", err)
+	}
+}
+
+func TestGetLatestLoads(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	loadedAt := time.Date(2025, 3, 11, 8, 30, 0, 0, time.UTC)
+	mock.ExpectQuery("SELECT variable, max\\(created_at\\)\\s+FROM catalog\\.curated_data\\s+GROUP BY variable").
+		WillReturnRows(sqlmock.NewRows([]string{"variable", "max"}).AddRow("pm2p5", loadedAt))
+	mock.ExpectQuery("SELECT dataset, max\\(created_at\\)\\s+FROM catalog\\.raw_files\\s+GROUP BY dataset").
+		WillReturnRows(sqlmock.NewRows([]string{"dataset", "max"}).AddRow("cams-europe-air-quality-forecast", loadedAt))
+
+	finder := NewFinder(db)
+	variables, err := finder.GetLatestVariableLoads(t.Context())
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if len(variables) != 1 || !variables["pm2p5"].Equal(loadedAt) {
+		t.Errorf("unexpected variable loads %v", variables)
+	}
+	datasets, err := finder.GetLatestDatasetLoads(t.Context())
+	if err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if len(datasets) != 1 || !datasets["cams-europe-air-quality-forecast"].Equal(loadedAt) {
+		t.Errorf("unexpected dataset loads %v", datasets)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled expectations: %v", err)
+	}
+}
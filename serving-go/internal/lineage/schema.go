@@ -0,0 +1,74 @@
+package lineage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var ErrSchemaMismatch = errors.New("catalog schema mismatch")
+
+// catalogSchema is what the finder's queries scan from each catalog table, as
+// information_schema.columns reports the types. Extra columns are allowed.
+var catalogSchema = []struct {
+	table, column, dataType string
+}{
+	{"raw_files", "id", "uuid"},
+	{"raw_files", "source", "text"},
+	{"raw_files", "dataset", "text"},
+	{"raw_files", "date", "date"},
+	{"raw_files", "s3_key", "text"},
+	{"raw_files", "created_at", "timestamp with time zone"},
+	{"curated_data", "id", "uuid"},
+	{"curated_data", "raw_file_id", "uuid"},
+	{"curated_data", "variable", "text"},
+	{"curated_data", "unit", "text"},
+	{"curated_data", "timestamp", "timestamp with time zone"},
+	{"curated_data", "created_at", "timestamp with time zone"},
+}
+
+// CheckSchema verifies that catalog.raw_files and catalog.curated_data have the columns
+// and types the finder scans, so a missing or outdated catalog schema fails at startup
+// instead of on the first lineage lookup. All differences are reported in one error
+// wrapping ErrSchemaMismatch.
+func (f *Finder) CheckSchema(ctx context.Context) error {
+	const query = `
+        SELECT table_name, column_name, data_type
+        FROM information_schema.columns
+        WHERE table_schema = 'catalog' AND table_name IN ('raw_files', 'curated_data')
+    `
+	rows, err := f.db.QueryContext(ctx, query)
+	if err != nil {
+		return fmt.Errorf("catalog schema query: %w", err)
+	}
+	defer rows.Close()
+
+	types := make(map[string]string)
+	for rows.Next() {
+		var table, column, dataType string
+		if err := rows.Scan(&table, &column, &dataType); err != nil {
+			return fmt.Errorf("scan catalog schema: %w", err)
+		}
+		types[table+"."+column] = dataType
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("iterate catalog schema: %w", err)
+	}
+
+	var problems []string
+	for _, want := range catalogSchema {
+		name := want.table + "." + want.column
+		got, ok := types[name]
+		switch {
+		case !ok:
+			problems = append(problems, fmt.Sprintf("column catalog.%s does not exist", name))
+		case got != want.dataType:
+			problems = append(problems, fmt.Sprintf("column catalog.%s is %s, want %s", name, got, want.dataType))
+		}
+	}
+	if len(problems) > 0 {
+		return fmt.Errorf("%w: %s", ErrSchemaMismatch, strings.Join(problems, "; "))
+	}
+	return nil
+}
@@ -0,0 +1,68 @@
+package lineage
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+func catalogSchemaRows() *sqlmock.Rows {
+	rows := sqlmock.NewRows([]string{"table_name", "column_name", "data_type"})
+	for _, c := range catalogSchema {
+		rows.AddRow(c.table, c.column, c.dataType)
+	}
+	return rows
+}
+
+func TestCheckSchema_Matches(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	mock.ExpectQuery("FROM information_schema\\.columns").
+		WillReturnRows(catalogSchemaRows().AddRow("curated_data", "checksum", "text"))
+
+	if err := NewFinder(db).CheckSchema(t.Context()); err != nil {
+		t.Errorf("expected no error, got: %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled expectations: %v", err)
+	}
+}
+
+func TestCheckSchema_Mismatch(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	rows := sqlmock.NewRows([]string{"table_name", "column_name", "data_type"})
+	for _, c := range catalogSchema {
+		switch {
+		case c.table == "raw_files" && c.column == "s3_key":
+		case c.table == "curated_data" && c.column == "timestamp":
+			rows.AddRow(c.table, c.column, "timestamp without time zone")
+		default:
+			rows.AddRow(c.table, c.column, c.dataType)
+		}
+	}
+	mock.ExpectQuery("FROM information_schema\\.columns").WillReturnRows(rows)
+
+	err = NewFinder(db).CheckSchema(t.Context())
+	if !errors.Is(err, ErrSchemaMismatch) {
+		t.Fatalf("expected ErrSchemaMismatch, got: %v", err)
+	}
+	for _, want := range []string{
+		"column catalog.raw_files.s3_key does not exist",
+		"column catalog.curated_data.timestamp is timestamp without time zone, want timestamp with time zone",
+	} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("expected %q in %q", want, err)
+		}
+	}
+}
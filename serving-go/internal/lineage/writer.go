@@ -0,0 +1,58 @@
+package lineage
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/kacper-wojtaszczyk/jackfruit/serving-go/internal/domain"
+)
+
+// Writer records catalog rows the way pipeline-python does, for tools that load grid
+// data themselves (e.g. the synthetic generator).
+type Writer struct {
+	db *sql.DB
+}
+
+func NewWriter(db *sql.DB) *Writer {
+	return &Writer{db: db}
+}
+
+// InsertRun records a raw file and its catalog entries in one transaction. The raw file
+// is left untouched if it already exists; existing entries are updated.
+func (w *Writer) InsertRun(ctx context.Context, rawFile domain.RawFile, entries []domain.CatalogEntry) error {
+	tx, err := w.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	_, err = tx.ExecContext(ctx, `
+        INSERT INTO catalog.raw_files (id, source, dataset, date, s3_key, created_at)
+        VALUES ($1, $2, $3, $4, $5, NOW())
+        ON CONFLICT (id) DO NOTHING
+    `, rawFile.ID, rawFile.Source, rawFile.Dataset, rawFile.Date.Format("2006-01-02"), rawFile.S3Key)
+	if err != nil {
+		return fmt.Errorf("insert raw file %s: %w", rawFile.ID, err)
+	}
+
+	for _, entry := range entries {
+		_, err = tx.ExecContext(ctx, `
+            INSERT INTO catalog.curated_data (id, raw_file_id, variable, unit, timestamp)
+            VALUES ($1, $2, $3, $4, $5)
+            ON CONFLICT (id) DO UPDATE SET
+                raw_file_id = EXCLUDED.raw_file_id,
+                variable = EXCLUDED.variable,
+                unit = EXCLUDED.unit,
+                timestamp = EXCLUDED.timestamp
+        `, entry.ID, rawFile.ID, entry.Variable, entry.Unit, entry.Timestamp)
+		if err != nil {
+			return fmt.Errorf("insert catalog entry %s: %w", entry.ID, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,66 @@
+package lineage
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/kacper-wojtaszczyk/jackfruit/serving-go/internal/domain"
+)
+
+func TestInsertRun(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	rawFile := domain.RawFile{
+		ID:      uuid.New(),
+		Source:  "synthetic",
+		Dataset: "synthetic-grid",
+		Date:    time.Date(2025, 3, 11, 0, 0, 0, 0, time.UTC),
+		S3Key:   "synthetic/synthetic-grid/2025-03-11/run.grib",
+	}
+	entry := domain.CatalogEntry{ID: uuid.New(), Variable: "pm2p5", Unit: "µg/m³", Timestamp: rawFile.Date}
+
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT INTO catalog\\.raw_files").
+		WithArgs(rawFile.ID, "synthetic", "synthetic-grid", "2025-03-11", rawFile.S3Key).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("INSERT INTO catalog\\.curated_data").
+		WithArgs(entry.ID, rawFile.ID, "pm2p5", "µg/m³", entry.Timestamp).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	if err := NewWriter(db).InsertRun(t.Context(), rawFile, []domain.CatalogEntry{entry}); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled expectations: %v", err)
+	}
+}
+
+func TestInsertRun_RollsBackOnError(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	failure := errors.New("duplicate key")
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT INTO catalog\\.raw_files").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec("INSERT INTO catalog\\.curated_data").WillReturnError(failure)
+	mock.ExpectRollback()
+
+	err = NewWriter(db).InsertRun(t.Context(), domain.RawFile{ID: uuid.New()}, []domain.CatalogEntry{{ID: uuid.New()}})
+	if !errors.Is(err, failure) {
+		t.Errorf("expected insert error, got: %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unfulfilled expectations: %v", err)
+	}
+}
@@ -0,0 +1,332 @@
+// Package loadtest replays weighted query mixes against the serving API at a fixed rate
+// and summarises latency per scenario.
+package loadtest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"math/rand/v2"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kacper-wojtaszczyk/jackfruit/serving-go/internal/api"
+	"github.com/kacper-wojtaszczyk/jackfruit/serving-go/internal/apiclient"
+	"github.com/kacper-wojtaszczyk/jackfruit/serving-go/internal/domain"
+)
+
+// Scenario is one kind of request in the mix, chosen with probability proportional to Weight.
+// Next builds the next call; it only runs on the scheduling goroutine, so it may use rng.
+type Scenario struct {
+	Name   string
+	Weight int
+	Next   func(rng *rand.Rand) func(ctx context.Context) error
+}
+
+// Target is the data the generated requests aim at. Points and timestamps are drawn
+// uniformly from Extent and [From, To].
+type Target struct {
+	Extent    domain.BoundingBox
+	From      time.Time
+	To        time.Time
+	Variables []string
+}
+
+func (t Target) request(rng *rand.Rand, variables []string) api.EnvironmentalRequest {
+	span := t.To.Sub(t.From)
+	ts := t.From
+	if span > 0 {
+		ts = ts.Add(time.Duration(rng.Int64N(int64(span)))).Truncate(time.Second)
+	}
+	return api.EnvironmentalRequest{
+		Lat:       t.Extent.MinLat + rng.Float32()*(t.Extent.MaxLat-t.Extent.MinLat),
+		Lon:       t.Extent.MinLon + rng.Float32()*(t.Extent.MaxLon-t.Extent.MinLon),
+		Timestamp: ts,
+		Variables: variables,
+		Partial:   true,
+	}
+}
+
+// PointScenario requests a single random variable at a random point.
+func PointScenario(client *apiclient.Client, target Target, weight int) Scenario {
+	return Scenario{
+		Name:   "point",
+		Weight: weight,
+		Next: func(rng *rand.Rand) func(ctx context.Context) error {
+			req := target.request(rng, []string{target.Variables[rng.IntN(len(target.Variables))]})
+			return func(ctx context.Context) error {
+				_, err := client.Environmental(ctx, req)
+				return err
+			}
+		},
+	}
+}
+
+// BatchScenario requests every target variable at a random point.
+func BatchScenario(client *apiclient.Client, target Target, weight int) Scenario {
+	return Scenario{
+		Name:   "batch",
+		Weight: weight,
+		Next: func(rng *rand.Rand) func(ctx context.Context) error {
+			req := target.request(rng, target.Variables)
+			return func(ctx context.Context) error {
+				_, err := client.Environmental(ctx, req)
+				return err
+			}
+		},
+	}
+}
+
+// SeriesScenario requests a random variable's whole [From, To] series at a random point,
+// downsampled to maxPoints (zero for every sample).
+func SeriesScenario(client *apiclient.Client, target Target, maxPoints, weight int) Scenario {
+	return Scenario{
+		Name:   "series",
+		Weight: weight,
+		Next: func(rng *rand.Rand) func(ctx context.Context) error {
+			point := target.request(rng, nil)
+			req := api.SeriesRequest{
+				Lat:       point.Lat,
+				Lon:       point.Lon,
+				Variable:  target.Variables[rng.IntN(len(target.Variables))],
+				From:      target.From,
+				To:        target.To,
+				MaxPoints: maxPoints,
+			}
+			return func(ctx context.Context) error {
+				_, err := client.Series(ctx, req)
+				return err
+			}
+		},
+	}
+}
+
+// ParseMix parses comma-separated name=weight pairs, e.g. "point=8,batch=2".
+func ParseMix(mix string) (map[string]int, error) {
+	weights := make(map[string]int)
+	for item := range strings.SplitSeq(mix, ",") {
+		if item = strings.TrimSpace(item); item == "" {
+			continue
+		}
+		name, value, ok := strings.Cut(item, "=")
+		if !ok || strings.TrimSpace(name) == "" {
+			return nil, fmt.Errorf("mix: expected name=weight, %q given", item)
+		}
+		weight, err := strconv.Atoi(strings.TrimSpace(value))
+		if err != nil || weight < 0 {
+			return nil, fmt.Errorf("mix: %s: weight must be a non-negative integer, %q given", name, value)
+		}
+		weights[strings.TrimSpace(name)] = weight
+	}
+	return weights, nil
+}
+
+type Config struct {
+	// RPS is the target request rate. Requests are started on schedule whether or not
+	// earlier ones finished, so slow responses don't lower the offered load.
+	RPS      float64
+	Duration time.Duration
+	// MaxInFlight caps concurrent requests; scheduled requests beyond it are dropped and counted.
+	MaxInFlight int
+	// Timeout bounds each request.
+	Timeout   time.Duration
+	Scenarios []Scenario
+	Seed      uint64
+}
+
+func (c Config) validate() error {
+	if c.RPS <= 0 {
+		return fmt.Errorf("rps must be positive, %v given", c.RPS)
+	}
+	if c.Duration <= 0 {
+		return fmt.Errorf("duration must be positive, %s given", c.Duration)
+	}
+	if c.Timeout <= 0 {
+		return fmt.Errorf("timeout must be positive, %s given", c.Timeout)
+	}
+	if c.MaxInFlight < 1 {
+		return fmt.Errorf("max in flight must be at least 1, %d given", c.MaxInFlight)
+	}
+	total := 0
+	for _, s := range c.Scenarios {
+		total += s.Weight
+	}
+	if total == 0 {
+		return errors.New("at least one scenario needs a positive weight")
+	}
+	return nil
+}
+
+// Outcome classifies one request: ok, timeout, error (transport) or the HTTP status code.
+func Outcome(err error) string {
+	if err == nil {
+		return "ok"
+	}
+	if apiErr, ok := errors.AsType[*apiclient.Error](err); ok {
+		return strconv.Itoa(apiErr.Status)
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return "timeout"
+	}
+	return "error"
+}
+
+type sample struct {
+	scenario int
+	latency  time.Duration
+	outcome  string
+}
+
+// Run offers load until Duration elapses or ctx is done, then waits for in-flight requests.
+func Run(ctx context.Context, cfg Config) (*Report, error) {
+	if err := cfg.validate(); err != nil {
+		return nil, err
+	}
+
+	rng := rand.New(rand.NewPCG(cfg.Seed, cfg.Seed^0x9e3779b97f4a7c15))
+	var weights []int
+	for _, s := range cfg.Scenarios {
+		weights = append(weights, s.Weight)
+	}
+
+	var (
+		mu      sync.Mutex
+		samples []sample
+		wg      sync.WaitGroup
+		dropped int
+	)
+	slots := make(chan struct{}, cfg.MaxInFlight)
+	ticker := time.NewTicker(time.Duration(float64(time.Second) / cfg.RPS))
+	defer ticker.Stop()
+	deadline := time.NewTimer(cfg.Duration)
+	defer deadline.Stop()
+
+	start := time.Now()
+schedule:
+	for {
+		select {
+		case <-ctx.Done():
+			break schedule
+		case <-deadline.C:
+			break schedule
+		case <-ticker.C:
+		}
+
+		i := pick(rng, weights)
+		call := cfg.Scenarios[i].Next(rng)
+		select {
+		case slots <- struct{}{}:
+		default:
+			dropped++
+			continue
+		}
+		wg.Go(func() {
+			defer func() { <-slots }()
+			reqCtx, cancel := context.WithTimeout(context.Background(), cfg.Timeout)
+			defer cancel()
+			began := time.Now()
+			err := call(reqCtx)
+			s := sample{scenario: i, latency: time.Since(began), outcome: Outcome(err)}
+			mu.Lock()
+			samples = append(samples, s)
+			mu.Unlock()
+		})
+	}
+	offered := time.Since(start)
+	wg.Wait()
+
+	return newReport(cfg.Scenarios, samples, offered, dropped), nil
+}
+
+func pick(rng *rand.Rand, weights []int) int {
+	total := 0
+	for _, w := range weights {
+		total += w
+	}
+	n := rng.IntN(total)
+	for i, w := range weights {
+		if n < w {
+			return i
+		}
+		n -= w
+	}
+	return len(weights) - 1
+}
+
+type ScenarioReport struct {
+	Name     string
+	Requests int
+	Outcomes map[string]int
+	P50      time.Duration
+	P90      time.Duration
+	P99      time.Duration
+	Max      time.Duration
+}
+
+type Report struct {
+	// Elapsed is how long load was offered, excluding the wait for the last responses.
+	Elapsed   time.Duration
+	Dropped   int
+	Scenarios []ScenarioReport
+}
+
+func newReport(scenarios []Scenario, samples []sample, elapsed time.Duration, dropped int) *Report {
+	report := &Report{Elapsed: elapsed, Dropped: dropped}
+	for i, scenario := range scenarios {
+		if scenario.Weight == 0 {
+			continue
+		}
+		sr := ScenarioReport{Name: scenario.Name, Outcomes: make(map[string]int)}
+		var latencies []time.Duration
+		for _, s := range samples {
+			if s.scenario != i {
+				continue
+			}
+			sr.Outcomes[s.outcome]++
+			latencies = append(latencies, s.latency)
+		}
+		slices.Sort(latencies)
+		sr.Requests = len(latencies)
+		sr.P50 = percentile(latencies, 50)
+		sr.P90 = percentile(latencies, 90)
+		sr.P99 = percentile(latencies, 99)
+		if len(latencies) > 0 {
+			sr.Max = latencies[len(latencies)-1]
+		}
+		report.Scenarios = append(report.Scenarios, sr)
+	}
+	return report
+}
+
+// percentile returns the nearest-rank percentile of sorted latencies.
+func percentile(sorted []time.Duration, p int) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+	rank := (p*len(sorted) + 99) / 100
+	return sorted[max(rank, 1)-1]
+}
+
+func (r *Report) Write(w io.Writer) {
+	total := r.Dropped
+	for _, s := range r.Scenarios {
+		total += s.Requests
+	}
+	fmt.Fprintf(w, "offered %d requests in %s (%.1f rps), %d dropped at the in-flight cap\n",
+		total, r.Elapsed.Round(time.Millisecond), float64(total)/r.Elapsed.Seconds(), r.Dropped)
+	fmt.Fprintf(w, "%-10s %8s %10s %10s %10s %10s  %s\n", "scenario", "requests", "p50", "p90", "p99", "max", "outcomes")
+	for _, s := range r.Scenarios {
+		var outcomes []string
+		for _, outcome := range slices.Sorted(maps.Keys(s.Outcomes)) {
+			outcomes = append(outcomes, fmt.Sprintf("%s=%d", outcome, s.Outcomes[outcome]))
+		}
+		fmt.Fprintf(w, "%-10s %8d %10s %10s %10s %10s  %s\n", s.Name, s.Requests,
+			s.P50.Round(time.Microsecond), s.P90.Round(time.Microsecond), s.P99.Round(time.Microsecond),
+			s.Max.Round(time.Microsecond), strings.Join(outcomes, " "))
+	}
+}
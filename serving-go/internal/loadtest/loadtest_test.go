@@ -0,0 +1,121 @@
+package loadtest
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"maps"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/kacper-wojtaszczyk/jackfruit/serving-go/internal/api"
+	"github.com/kacper-wojtaszczyk/jackfruit/serving-go/internal/apiclient"
+	"github.com/kacper-wojtaszczyk/jackfruit/serving-go/internal/domain"
+)
+
+func TestRun(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(api.EnvironmentalResponse{})
+	}))
+	defer server.Close()
+
+	client := apiclient.New(server.URL)
+	target := Target{
+		Extent:    domain.BoundingBox{MinLat: 45, MinLon: 5, MaxLat: 55, MaxLon: 15},
+		From:      time.Date(2025, 3, 11, 0, 0, 0, 0, time.UTC),
+		To:        time.Date(2025, 3, 12, 0, 0, 0, 0, time.UTC),
+		Variables: []string{"pm2p5", "pm10"},
+	}
+	report, err := Run(t.Context(), Config{
+		RPS:         200,
+		Duration:    100 * time.Millisecond,
+		MaxInFlight: 8,
+		Timeout:     time.Second,
+		Scenarios:   []Scenario{PointScenario(client, target, 1), BatchScenario(client, target, 1), SeriesScenario(client, target, 100, 1)},
+	})
+	if err != nil {
+		t.Fatalf("Run returned error: %v", err)
+	}
+
+	if len(report.Scenarios) != 3 {
+		t.Fatalf("expected three scenario reports, got %+v", report.Scenarios)
+	}
+	total := 0
+	for _, s := range report.Scenarios {
+		total += s.Requests
+		if s.Outcomes["ok"] != s.Requests {
+			t.Errorf("expected only ok outcomes for %s, got %v", s.Name, s.Outcomes)
+		}
+		if s.Requests > 0 && (s.P50 <= 0 || s.P50 > s.Max) {
+			t.Errorf("expected 0 < p50 <= max for %s, got %s and %s", s.Name, s.P50, s.Max)
+		}
+	}
+	if total == 0 {
+		t.Error("expected some requests to be sent")
+	}
+}
+
+func TestRun_RejectsInvalidConfig(t *testing.T) {
+	_, err := Run(t.Context(), Config{RPS: 1, Duration: time.Second, MaxInFlight: 1, Timeout: time.Second,
+		Scenarios: []Scenario{{Name: "point"}}})
+	if err == nil {
+		t.Error("expected an error for a mix without positive weights")
+	}
+}
+
+func TestPercentile(t *testing.T) {
+	latencies := make([]time.Duration, 100)
+	for i := range latencies {
+		latencies[i] = time.Duration(i+1) * time.Millisecond
+	}
+	tests := []struct {
+		p    int
+		want time.Duration
+	}{
+		{50, 50 * time.Millisecond},
+		{99, 99 * time.Millisecond},
+		{100, 100 * time.Millisecond},
+	}
+	for _, tt := range tests {
+		if got := percentile(latencies, tt.p); got != tt.want {
+			t.Errorf("p%d = %s, want %s", tt.p, got, tt.want)
+		}
+	}
+	if got := percentile([]time.Duration{5 * time.Millisecond}, 1); got != 5*time.Millisecond {
+		t.Errorf("expected the only sample, got %s", got)
+	}
+}
+
+func TestParseMix(t *testing.T) {
+	weights, err := ParseMix("point=8, batch=2")
+	if err != nil {
+		t.Fatalf("ParseMix returned error: %v", err)
+	}
+	if want := map[string]int{"point": 8, "batch": 2}; !maps.Equal(weights, want) {
+		t.Errorf("expected %v, got %v", want, weights)
+	}
+	for _, mix := range []string{"point", "=3", "batch=-1", "point=many"} {
+		if _, err := ParseMix(mix); err == nil {
+			t.Errorf("expected error for %q", mix)
+		}
+	}
+}
+
+func TestOutcome(t *testing.T) {
+	tests := []struct {
+		err  error
+		want string
+	}{
+		{nil, "ok"},
+		{&apiclient.Error{Status: 404}, "404"},
+		{context.DeadlineExceeded, "timeout"},
+		{errors.New("connection refused"), "error"},
+	}
+	for _, tt := range tests {
+		if got := Outcome(tt.err); got != tt.want {
+			t.Errorf("Outcome(%v) = %q, want %q", tt.err, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,131 @@
+// Package logging gives each serving component its own log level over one shared handler.
+// Levels come from the environment at startup and can be changed at runtime; changes live in
+// memory and are lost on restart.
+package logging
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"sync"
+)
+
+var ErrUnknownComponent = errors.New("unknown log component")
+
+// Components with their own level. server covers process lifecycle logs without a component.
+const (
+	ComponentAPI        = "api"
+	ComponentCache      = "cache"
+	ComponentClickHouse = "clickhouse"
+	ComponentDomain     = "domain"
+	ComponentServer     = "server"
+)
+
+var components = []string{ComponentAPI, ComponentCache, ComponentClickHouse, ComponentDomain, ComponentServer}
+
+// State is one component's effective level and the one configured at startup.
+type State struct {
+	Component string
+	Level     slog.Level
+	Default   slog.Level
+}
+
+// Levels holds the level of every component, safe for concurrent use.
+type Levels struct {
+	base     slog.Handler
+	levels   map[string]*slog.LevelVar
+	mu       sync.Mutex
+	defaults map[string]slog.Level
+}
+
+// New starts every component at fallback unless overrides names it. base must accept
+// records of every level. Unknown components fail with ErrUnknownComponent.
+func New(base slog.Handler, fallback slog.Level, overrides map[string]slog.Level) (*Levels, error) {
+	l := &Levels{base: base, levels: make(map[string]*slog.LevelVar), defaults: make(map[string]slog.Level)}
+	for _, component := range components {
+		l.levels[component] = new(slog.LevelVar)
+		l.levels[component].Set(fallback)
+		l.defaults[component] = fallback
+	}
+	for component, level := range overrides {
+		if _, ok := l.levels[component]; !ok {
+			return nil, fmt.Errorf("%w %q", ErrUnknownComponent, component)
+		}
+		l.levels[component].Set(level)
+		l.defaults[component] = level
+	}
+	return l, nil
+}
+
+// Logger returns a logger tagged with component whose records are filtered by its level.
+// The server component's logger carries no component attribute.
+func (l *Levels) Logger(component string) *slog.Logger {
+	level, ok := l.levels[component]
+	if !ok {
+		panic(fmt.Sprintf("logging: %v %q", ErrUnknownComponent, component))
+	}
+	logger := slog.New(&levelHandler{Handler: l.base, level: level})
+	if component == ComponentServer {
+		return logger
+	}
+	return logger.With("component", component)
+}
+
+// Set changes component's level until Reset or restart.
+func (l *Levels) Set(component string, level slog.Level) (State, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	v, ok := l.levels[component]
+	if !ok {
+		return State{}, fmt.Errorf("%w %q", ErrUnknownComponent, component)
+	}
+	v.Set(level)
+	return l.state(component), nil
+}
+
+// Reset restores component's startup level.
+func (l *Levels) Reset(component string) (State, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	v, ok := l.levels[component]
+	if !ok {
+		return State{}, fmt.Errorf("%w %q", ErrUnknownComponent, component)
+	}
+	v.Set(l.defaults[component])
+	return l.state(component), nil
+}
+
+// List returns every component, sorted by name.
+func (l *Levels) List() []State {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	states := make([]State, 0, len(components))
+	for _, component := range slices.Sorted(slices.Values(components)) {
+		states = append(states, l.state(component))
+	}
+	return states
+}
+
+func (l *Levels) state(component string) State {
+	return State{Component: component, Level: l.levels[component].Level(), Default: l.defaults[component]}
+}
+
+// levelHandler drops records below its level before they reach the shared handler.
+type levelHandler struct {
+	slog.Handler
+	level *slog.LevelVar
+}
+
+func (h *levelHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return level >= h.level.Level() && h.Handler.Enabled(ctx, level)
+}
+
+func (h *levelHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &levelHandler{Handler: h.Handler.WithAttrs(attrs), level: h.level}
+}
+
+func (h *levelHandler) WithGroup(name string) slog.Handler {
+	return &levelHandler{Handler: h.Handler.WithGroup(name), level: h.level}
+}
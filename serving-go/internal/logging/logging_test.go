@@ -0,0 +1,79 @@
+package logging
+
+import (
+	"bytes"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestLevels_FilterPerComponent(t *testing.T) {
+	var buf bytes.Buffer
+	base := slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug})
+	levels, err := New(base, slog.LevelInfo, map[string]slog.Level{ComponentClickHouse: slog.LevelDebug})
+	if err != nil {
+		t.Fatalf("New returned error: %v", err)
+	}
+
+	levels.Logger(ComponentClickHouse).Debug("query details")
+	levels.Logger(ComponentAPI).Debug("hidden")
+	levels.Logger(ComponentAPI).With("request_id", "abc").Info("served")
+	out := buf.String()
+	if !strings.Contains(out, "component=clickhouse") || !strings.Contains(out, "query details") {
+		t.Errorf("expected clickhouse debug record, got %q", out)
+	}
+	if strings.Contains(out, "hidden") {
+		t.Errorf("expected api debug record to be dropped, got %q", out)
+	}
+	if !strings.Contains(out, "component=api request_id=abc") {
+		t.Errorf("expected api info record with attributes, got %q", out)
+	}
+}
+
+func TestLevels_SetAndReset(t *testing.T) {
+	var buf bytes.Buffer
+	levels, err := New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}), slog.LevelInfo, nil)
+	if err != nil {
+		t.Fatalf("New returned error: %v", err)
+	}
+	logger := levels.Logger(ComponentCache)
+
+	state, err := levels.Set(ComponentCache, slog.LevelDebug)
+	if err != nil {
+		t.Fatalf("Set returned error: %v", err)
+	}
+	if want := (State{Component: ComponentCache, Level: slog.LevelDebug, Default: slog.LevelInfo}); state != want {
+		t.Errorf("expected %+v, got %+v", want, state)
+	}
+	logger.Debug("after set")
+	if !strings.Contains(buf.String(), "after set") {
+		t.Error("expected existing loggers to follow the new level")
+	}
+
+	if state, err = levels.Reset(ComponentCache); err != nil || state.Level != slog.LevelInfo {
+		t.Errorf("expected Reset to restore info, got %+v, %v", state, err)
+	}
+	buf.Reset()
+	logger.Debug("after reset")
+	if buf.Len() != 0 {
+		t.Errorf("expected debug to be dropped after reset, got %q", buf.String())
+	}
+}
+
+func TestLevels_RejectsUnknownComponents(t *testing.T) {
+	base := slog.NewTextHandler(&bytes.Buffer{}, nil)
+	if _, err := New(base, slog.LevelInfo, map[string]slog.Level{"postgres": slog.LevelDebug}); !errors.Is(err, ErrUnknownComponent) {
+		t.Errorf("expected ErrUnknownComponent from New, got %v", err)
+	}
+	levels, err := New(base, slog.LevelInfo, nil)
+	if err != nil {
+		t.Fatalf("New returned error: %v", err)
+	}
+	if _, err := levels.Set("postgres", slog.LevelDebug); !errors.Is(err, ErrUnknownComponent) {
+		t.Errorf("expected ErrUnknownComponent from Set, got %v", err)
+	}
+	if got := len(levels.List()); got != len(components) {
+		t.Errorf("expected %d components, got %d", len(components), got)
+	}
+}
@@ -0,0 +1,22 @@
+-- Grid data storage for environmental variables.
+-- Each row is a single (variable, timestamp, lat, lon) measurement.
+-- catalog_id references catalog.curated_data.id in Postgres (lineage/metadata).
+--
+-- ReplacingMergeTree(inserted_at) deduplicates rows with the same sorting key
+-- during background merges, keeping the row with the highest inserted_at.
+-- Queries must use FINAL to see deduplicated results.
+--
+-- IF NOT EXISTS keeps this a no-op on environments initialised from
+-- pipeline-python/migrations/clickhouse/init.sql.
+CREATE TABLE IF NOT EXISTS grid_data (
+    variable     LowCardinality(String),
+    timestamp    DateTime,
+    lat          Float32,
+    lon          Float32,
+    value        Float32,
+    unit         LowCardinality(String),
+    catalog_id   UUID,
+    inserted_at  DateTime64(3) DEFAULT now64(3)
+) ENGINE = ReplacingMergeTree(inserted_at)
+PARTITION BY toYYYYMMDD(timestamp)
+ORDER BY (variable, timestamp, lat, lon);
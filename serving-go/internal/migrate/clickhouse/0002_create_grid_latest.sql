@@ -0,0 +1,25 @@
+-- Latest value per (variable, cell), maintained by a materialized view on grid_data.
+-- Serves "current conditions" lookups without the max(timestamp) subquery over all of
+-- a variable's history. ReplacingMergeTree(timestamp) keeps the newest timestamp per cell;
+-- queries must use FINAL.
+--
+-- Deletes and mutations on grid_data do not propagate here.
+CREATE TABLE IF NOT EXISTS grid_latest (
+    variable     LowCardinality(String),
+    lat          Float32,
+    lon          Float32,
+    timestamp    DateTime,
+    value        Float32,
+    unit         LowCardinality(String),
+    catalog_id   UUID
+) ENGINE = ReplacingMergeTree(timestamp)
+ORDER BY (variable, lat, lon);
+
+CREATE MATERIALIZED VIEW IF NOT EXISTS grid_latest_mv TO grid_latest AS
+SELECT variable, lat, lon, timestamp, value, unit, catalog_id
+FROM grid_data;
+
+-- Backfill rows loaded before the view existed.
+INSERT INTO grid_latest
+SELECT variable, lat, lon, timestamp, value, unit, catalog_id
+FROM grid_data FINAL;
@@ -0,0 +1,12 @@
+-- Append-only record of security-relevant operations (admin changes, purges), written by
+-- internal/audit. Nothing in the codebase updates or deletes rows; restrict ALTER on this
+-- table to administrators to keep it that way.
+CREATE TABLE IF NOT EXISTS audit_log (
+    time     DateTime64(3),
+    actor    LowCardinality(String),
+    action   LowCardinality(String),
+    target   String,
+    details  Map(String, String)
+) ENGINE = MergeTree
+PARTITION BY toYYYYMM(time)
+ORDER BY (time, action);
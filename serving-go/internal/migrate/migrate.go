@@ -0,0 +1,190 @@
+package migrate
+
+import (
+	"cmp"
+	"context"
+	"embed"
+	"fmt"
+	"io/fs"
+	"path"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/ClickHouse/clickhouse-go/v2"
+	"github.com/ClickHouse/clickhouse-go/v2/lib/driver"
+)
+
+//go:embed clickhouse/*.sql
+var clickHouseMigrations embed.FS
+
+var fileNamePattern = regexp.MustCompile(`^(\d+)_([a-z0-9_]+)\.sql$`)
+
+type Migration struct {
+	Version    uint32
+	Name       string
+	Statements []string
+}
+
+// Status describes one migration and whether it has been applied.
+type Status struct {
+	Migration
+	Applied bool
+}
+
+// Migrator applies versioned ClickHouse migrations and records them in schema_migrations.
+// Migrations run in version order; each file may hold several statements separated by
+// semicolons and should be idempotent, since a version is only recorded after all its
+// statements succeed.
+type Migrator struct {
+	conn       driver.Conn
+	migrations []Migration
+}
+
+func NewMigrator(conn driver.Conn) (*Migrator, error) {
+	return newMigrator(conn, clickHouseMigrations, "clickhouse")
+}
+
+func newMigrator(conn driver.Conn, fsys fs.FS, dir string) (*Migrator, error) {
+	migrations, err := load(fsys, dir)
+	if err != nil {
+		return nil, err
+	}
+	return &Migrator{conn: conn, migrations: migrations}, nil
+}
+
+// Up applies all pending migrations and returns the ones it applied.
+func (m *Migrator) Up(ctx context.Context) ([]Migration, error) {
+	statuses, err := m.Status(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var applied []Migration
+	for _, status := range statuses {
+		if status.Applied {
+			continue
+		}
+		for i, statement := range status.Statements {
+			if err := m.conn.Exec(ctx, statement); err != nil {
+				return applied, fmt.Errorf("migration %04d_%s statement %d: %w", status.Version, status.Name, i+1, err)
+			}
+		}
+		err := m.conn.Exec(ctx,
+			"INSERT INTO schema_migrations (version, name) VALUES (@version, @name)",
+			clickhouse.Named("version", status.Version),
+			clickhouse.Named("name", status.Name),
+		)
+		if err != nil {
+			return applied, fmt.Errorf("record migration %04d_%s: %w", status.Version, status.Name, err)
+		}
+		applied = append(applied, status.Migration)
+	}
+
+	return applied, nil
+}
+
+// Status lists every embedded migration with its applied state, creating the
+// schema_migrations table if needed.
+func (m *Migrator) Status(ctx context.Context) ([]Status, error) {
+	err := m.conn.Exec(ctx, `
+        CREATE TABLE IF NOT EXISTS schema_migrations (
+            version    UInt32,
+            name       String,
+            applied_at DateTime DEFAULT now()
+        ) ENGINE = MergeTree
+        ORDER BY version
+    `)
+	if err != nil {
+		return nil, fmt.Errorf("create schema_migrations: %w", err)
+	}
+
+	rows, err := m.conn.Query(ctx, "SELECT DISTINCT version FROM schema_migrations")
+	if err != nil {
+		return nil, fmt.Errorf("query schema_migrations: %w", err)
+	}
+	defer rows.Close()
+
+	applied := make(map[uint32]bool)
+	for rows.Next() {
+		var version uint32
+		if err := rows.Scan(&version); err != nil {
+			return nil, fmt.Errorf("scan schema_migrations: %w", err)
+		}
+		applied[version] = true
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate schema_migrations: %w", err)
+	}
+
+	statuses := make([]Status, len(m.migrations))
+	for i, migration := range m.migrations {
+		statuses[i] = Status{Migration: migration, Applied: applied[migration.Version]}
+	}
+	return statuses, nil
+}
+
+func load(fsys fs.FS, dir string) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		return nil, fmt.Errorf("read migrations: %w", err)
+	}
+
+	var migrations []Migration
+	seen := make(map[uint32]string)
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		match := fileNamePattern.FindStringSubmatch(entry.Name())
+		if match == nil {
+			return nil, fmt.Errorf("migration file %q: expected NNNN_name.sql", entry.Name())
+		}
+		version, err := strconv.ParseUint(match[1], 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("migration file %q: %w", entry.Name(), err)
+		}
+		if other, ok := seen[uint32(version)]; ok {
+			return nil, fmt.Errorf("migration files %q and %q share version %d", other, entry.Name(), version)
+		}
+		seen[uint32(version)] = entry.Name()
+
+		content, err := fs.ReadFile(fsys, path.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("read migration %q: %w", entry.Name(), err)
+		}
+		statements := splitStatements(string(content))
+		if len(statements) == 0 {
+			return nil, fmt.Errorf("migration file %q has no statements", entry.Name())
+		}
+		migrations = append(migrations, Migration{Version: uint32(version), Name: match[2], Statements: statements})
+	}
+
+	slices.SortFunc(migrations, func(a, b Migration) int { return cmp.Compare(a.Version, b.Version) })
+	return migrations, nil
+}
+
+// splitStatements drops full-line "--" comments and splits on semicolons that end a line.
+// Migrations must not put semicolons at line ends inside string literals.
+func splitStatements(sql string) []string {
+	var statements []string
+	var current strings.Builder
+	for line := range strings.Lines(sql) {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "--") {
+			continue
+		}
+		current.WriteString(line)
+		if strings.HasSuffix(trimmed, ";") {
+			if statement := strings.TrimSuffix(strings.TrimSpace(current.String()), ";"); statement != "" {
+				statements = append(statements, statement)
+			}
+			current.Reset()
+		}
+	}
+	if statement := strings.TrimSpace(current.String()); statement != "" {
+		statements = append(statements, statement)
+	}
+	return statements
+}
@@ -0,0 +1,41 @@
+package migrate_test
+
+import (
+	"testing"
+
+	"github.com/kacper-wojtaszczyk/jackfruit/serving-go/internal/migrate"
+	"github.com/kacper-wojtaszczyk/jackfruit/serving-go/internal/testutil"
+)
+
+func TestMigrator_Up(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test, requires ClickHouse")
+	}
+
+	migrator, err := migrate.NewMigrator(testutil.NewRawConn(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := migrator.Up(t.Context()); err != nil {
+		t.Fatalf("Up returned error: %v", err)
+	}
+
+	applied, err := migrator.Up(t.Context())
+	if err != nil {
+		t.Fatalf("second Up returned error: %v", err)
+	}
+	if len(applied) != 0 {
+		t.Errorf("expected second Up to be a no-op, applied %d migrations", len(applied))
+	}
+
+	statuses, err := migrator.Status(t.Context())
+	if err != nil {
+		t.Fatalf("Status returned error: %v", err)
+	}
+	for _, s := range statuses {
+		if !s.Applied {
+			t.Errorf("expected %04d_%s to be applied", s.Version, s.Name)
+		}
+	}
+}
@@ -0,0 +1,82 @@
+package migrate
+
+import (
+	"slices"
+	"testing"
+	"testing/fstest"
+)
+
+func TestEmbeddedMigrationsLoad(t *testing.T) {
+	migrator, err := NewMigrator(nil)
+	if err != nil {
+		t.Fatalf("NewMigrator returned error: %v", err)
+	}
+	if len(migrator.migrations) == 0 {
+		t.Fatal("expected embedded migrations")
+	}
+	if first := migrator.migrations[0]; first.Version != 1 || first.Name != "create_grid_data" {
+		t.Errorf("expected first migration 0001_create_grid_data, got %04d_%s", first.Version, first.Name)
+	}
+}
+
+func TestLoad_SortsByVersion(t *testing.T) {
+	fsys := fstest.MapFS{
+		"m/0010_second.sql": {Data: []byte("SELECT 2;")},
+		"m/0002_first.sql":  {Data: []byte("SELECT 1;")},
+	}
+
+	migrations, err := load(fsys, "m")
+	if err != nil {
+		t.Fatalf("load returned error: %v", err)
+	}
+	var versions []uint32
+	for _, m := range migrations {
+		versions = append(versions, m.Version)
+	}
+	if !slices.Equal(versions, []uint32{2, 10}) {
+		t.Errorf("expected versions [2 10], got %v", versions)
+	}
+}
+
+func TestLoad_Invalid(t *testing.T) {
+	tests := []struct {
+		name string
+		fsys fstest.MapFS
+	}{
+		{name: "bad file name", fsys: fstest.MapFS{"m/create_table.sql": {Data: []byte("SELECT 1;")}}},
+		{name: "duplicate version", fsys: fstest.MapFS{
+			"m/0001_a.sql": {Data: []byte("SELECT 1;")},
+			"m/001_b.sql":  {Data: []byte("SELECT 1;")},
+		}},
+		{name: "only comments", fsys: fstest.MapFS{"m/0001_empty.sql": {Data: []byte("-- nothing here\n")}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := load(tt.fsys, "m"); err == nil {
+				t.Error("expected error")
+			}
+		})
+	}
+}
+
+func TestSplitStatements(t *testing.T) {
+	sql := `-- leading comment
+CREATE TABLE a (
+    x UInt8
+) ENGINE = Memory;
+
+-- second
+ALTER TABLE a ADD COLUMN y UInt8;
+SELECT 'trailing without semicolon'
+`
+	got := splitStatements(sql)
+	want := []string{
+		"CREATE TABLE a (\n    x UInt8\n) ENGINE = Memory",
+		"ALTER TABLE a ADD COLUMN y UInt8",
+		"SELECT 'trailing without semicolon'",
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("splitStatements() =\n%q\nwant\n%q", got, want)
+	}
+}
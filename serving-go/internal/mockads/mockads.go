@@ -0,0 +1,118 @@
+// Package mockads stands in for the Copernicus ADS retrieve API in end-to-end tests. It
+// speaks the legacy cdsapi protocol, which the client uses for UID:KEY style keys: a
+// request to /resources/{dataset} completes at once, and its result downloads a fixture
+// GRIB whatever was asked for.
+package mockads
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"slices"
+	"sync/atomic"
+	"time"
+)
+
+// Server serves one fixture GRIB for every retrieve request of its datasets.
+type Server struct {
+	prefix   string
+	grib     []byte
+	datasets []string
+	logger   *slog.Logger
+	requests atomic.Uint64
+}
+
+// New serves grib for requests to any of datasets. prefix is the path of the client's URL,
+// e.g. /api for ADS_BASE_URL=http://mockads:8090/api.
+func New(prefix string, grib []byte, datasets []string, logger *slog.Logger) *Server {
+	return &Server{prefix: prefix, grib: grib, datasets: datasets, logger: logger}
+}
+
+// reply is a legacy cdsapi task state. Completed replies carry the download location.
+type reply struct {
+	State         string `json:"state"`
+	RequestID     string `json:"request_id"`
+	Location      string `json:"location,omitempty"`
+	ContentLength int    `json:"content_length,omitempty"`
+	ContentType   string `json:"content_type,omitempty"`
+}
+
+// RegisterRoutes serves the API under the server's prefix.
+func (s *Server) RegisterRoutes(mux *http.ServeMux) {
+	mux.HandleFunc("GET "+s.prefix+"/status.json", s.handleStatus)
+	mux.HandleFunc("POST "+s.prefix+"/resources/{dataset}", s.requireKey(s.handleRetrieve))
+	mux.HandleFunc("GET "+s.prefix+"/tasks/{id}", s.requireKey(s.handleTask))
+	mux.HandleFunc("DELETE "+s.prefix+"/tasks/{id}", s.requireKey(s.handleDeleteTask))
+	mux.HandleFunc("GET "+s.prefix+"/downloads/{file}", s.handleDownload)
+}
+
+// requireKey rejects requests without the basic auth the client derives from its UID:KEY.
+func (s *Server) requireKey(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if _, _, ok := r.BasicAuth(); !ok {
+			writeError(w, http.StatusUnauthorized, "authentication required", "set ADS_API_KEY to UID:KEY")
+			return
+		}
+		next(w, r)
+	}
+}
+
+func (s *Server) handleStatus(w http.ResponseWriter, _ *http.Request) {
+	writeJSON(w, http.StatusOK, map[string][]string{})
+}
+
+func (s *Server) handleRetrieve(w http.ResponseWriter, r *http.Request) {
+	dataset := r.PathValue("dataset")
+	if !slices.Contains(s.datasets, dataset) {
+		writeError(w, http.StatusNotFound, "resource not found", fmt.Sprintf("dataset %q is not served", dataset))
+		return
+	}
+	var request map[string]any
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 1<<16)).Decode(&request); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request", err.Error())
+		return
+	}
+
+	id := fmt.Sprintf("mock-%d", s.requests.Add(1))
+	s.logger.Info("retrieve request", "dataset", dataset, "request_id", id, "request", request)
+	writeJSON(w, http.StatusOK, s.completed(r, id))
+}
+
+// handleTask reports every task as completed, since retrieve requests complete at once.
+func (s *Server) handleTask(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, s.completed(r, r.PathValue("id")))
+}
+
+func (s *Server) handleDeleteTask(w http.ResponseWriter, _ *http.Request) {
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) handleDownload(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/x-grib")
+	http.ServeContent(w, r, r.PathValue("file"), time.Time{}, bytes.NewReader(s.grib))
+}
+
+// completed is the reply for a finished task, located on the host the client called so the
+// download resolves inside a compose network too.
+func (s *Server) completed(r *http.Request, id string) reply {
+	return reply{
+		State:         "completed",
+		RequestID:     id,
+		Location:      fmt.Sprintf("http://%s%s/downloads/%s.grib", r.Host, s.prefix, id),
+		ContentLength: len(s.grib),
+		ContentType:   "application/x-grib",
+	}
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+// writeError replies in the shape the client reads error messages from.
+func writeError(w http.ResponseWriter, status int, message, reason string) {
+	writeJSON(w, status, map[string]string{"message": message, "reason": reason})
+}
@@ -0,0 +1,103 @@
+package mockads
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func newServer(t *testing.T, grib []byte) *httptest.Server {
+	t.Helper()
+	mux := http.NewServeMux()
+	New("/api", grib, []string{"cams-europe-air-quality-forecasts"}, slog.New(slog.DiscardHandler)).RegisterRoutes(mux)
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server
+}
+
+func retrieve(t *testing.T, url, dataset string, auth bool) *http.Response {
+	t.Helper()
+	req, err := http.NewRequestWithContext(t.Context(), "POST", url+"/api/resources/"+dataset,
+		strings.NewReader(`{"variable": ["particulate_matter_2.5um"], "date": "2026-02-21/2026-02-21"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if auth {
+		req.SetBasicAuth("e2e", "e2e")
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = resp.Body.Close() })
+	return resp
+}
+
+func TestServer_RetrieveAndDownload(t *testing.T) {
+	grib := []byte("GRIB fixture 7777")
+	server := newServer(t, grib)
+
+	resp := retrieve(t, server.URL, "cams-europe-air-quality-forecasts", true)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", resp.StatusCode)
+	}
+	var got reply
+	if err := json.NewDecoder(resp.Body).Decode(&got); err != nil {
+		t.Fatalf("decode reply: %v", err)
+	}
+	if got.State != "completed" || got.RequestID == "" || got.ContentLength != len(grib) ||
+		got.Location != server.URL+"/api/downloads/"+got.RequestID+".grib" {
+		t.Fatalf("unexpected reply %+v", got)
+	}
+
+	download, err := http.Get(got.Location)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer download.Body.Close()
+	body, err := io.ReadAll(download.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if download.StatusCode != http.StatusOK || !bytes.Equal(body, grib) {
+		t.Errorf("expected the fixture, got %d: %q", download.StatusCode, body)
+	}
+
+	req, err := http.NewRequestWithContext(t.Context(), "GET", server.URL+"/api/tasks/"+got.RequestID, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.SetBasicAuth("e2e", "e2e")
+	task, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer task.Body.Close()
+	var polled reply
+	if err := json.NewDecoder(task.Body).Decode(&polled); err != nil {
+		t.Fatalf("decode task: %v", err)
+	}
+	if polled != got {
+		t.Errorf("expected polling to repeat %+v, got %+v", got, polled)
+	}
+}
+
+func TestServer_Errors(t *testing.T) {
+	server := newServer(t, []byte("GRIB"))
+	if resp := retrieve(t, server.URL, "cams-europe-air-quality-forecasts", false); resp.StatusCode != http.StatusUnauthorized {
+		t.Errorf("expected 401 without a key, got %d", resp.StatusCode)
+	}
+	resp := retrieve(t, server.URL, "cams-global-reanalysis-eac4", true)
+	var body map[string]string
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		t.Fatalf("decode error: %v", err)
+	}
+	if resp.StatusCode != http.StatusNotFound || body["message"] == "" {
+		t.Errorf("expected 404 with a message for another dataset, got %d: %v", resp.StatusCode, body)
+	}
+}
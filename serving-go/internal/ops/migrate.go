@@ -0,0 +1,66 @@
+package ops
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/kacper-wojtaszczyk/jackfruit/serving-go/internal/config"
+	"github.com/kacper-wojtaszczyk/jackfruit/serving-go/internal/migrate"
+)
+
+// MigrateTimeout bounds one migrate command.
+const MigrateTimeout = 5 * time.Minute
+
+// Migrate applies pending migrations ("up") or lists every migration and whether it is
+// applied ("status"), printing to w.
+func Migrate(ctx context.Context, command string, w io.Writer) error {
+	if command != "up" && command != "status" {
+		return fmt.Errorf("unknown command %q (expected up or status)", command)
+	}
+	ctx, cancel := context.WithTimeout(ctx, MigrateTimeout)
+	defer cancel()
+
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("load config: %w", err)
+	}
+	conn, err := openClickHouse(cfg)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	migrator, err := migrate.NewMigrator(conn)
+	if err != nil {
+		return fmt.Errorf("load migrations: %w", err)
+	}
+
+	if command == "up" {
+		applied, err := migrator.Up(ctx)
+		for _, m := range applied {
+			fmt.Fprintf(w, "applied %04d_%s\n", m.Version, m.Name)
+		}
+		if err != nil {
+			return err
+		}
+		if len(applied) == 0 {
+			fmt.Fprintln(w, "schema up to date")
+		}
+		return nil
+	}
+
+	statuses, err := migrator.Status(ctx)
+	if err != nil {
+		return err
+	}
+	for _, s := range statuses {
+		state := "pending"
+		if s.Applied {
+			state = "applied"
+		}
+		fmt.Fprintf(w, "%04d_%s\t%s\n", s.Version, s.Name, state)
+	}
+	return nil
+}
@@ -0,0 +1,28 @@
+// Package ops runs the operator commands that work directly against ClickHouse rather than
+// through the serving API: schema migrations and retention. cmd/migrate, cmd/retention and
+// jackfruitctl share it, and it connects from the same CLICKHOUSE_* configuration as the
+// server.
+package ops
+
+import (
+	"fmt"
+	"log/slog"
+
+	"github.com/ClickHouse/clickhouse-go/v2"
+	"github.com/ClickHouse/clickhouse-go/v2/lib/driver"
+
+	"github.com/kacper-wojtaszczyk/jackfruit/serving-go/internal/config"
+	"github.com/kacper-wojtaszczyk/jackfruit/serving-go/internal/grid"
+)
+
+func openClickHouse(cfg *config.Config) (driver.Conn, error) {
+	options, err := grid.Options(cfg, slog.New(slog.DiscardHandler))
+	if err != nil {
+		return nil, fmt.Errorf("clickhouse options: %w", err)
+	}
+	conn, err := clickhouse.Open(options)
+	if err != nil {
+		return nil, fmt.Errorf("open clickhouse: %w", err)
+	}
+	return conn, nil
+}
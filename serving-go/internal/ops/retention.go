@@ -0,0 +1,114 @@
+package ops
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/ClickHouse/clickhouse-go/v2/lib/driver"
+
+	"github.com/kacper-wojtaszczyk/jackfruit/serving-go/internal/audit"
+	"github.com/kacper-wojtaszczyk/jackfruit/serving-go/internal/config"
+	"github.com/kacper-wojtaszczyk/jackfruit/serving-go/internal/retention"
+)
+
+// RetentionTimeout bounds one retention command; OPTIMIZE FINAL on large partitions is slow.
+const RetentionTimeout = time.Hour
+
+// auditTimeout bounds recording one applied action. The record outlives the command's
+// context, so a timeout or SIGINT after a drop still leaves it audited.
+const auditTimeout = 10 * time.Second
+
+// Retention prints the actions the RETENTION_* policy calls for ("plan"), or applies them
+// and records each one as an audit event with $USER as actor as soon as it completes
+// ("run"). Actions go to w and audit failures, which don't stop the run, to errw.
+func Retention(ctx context.Context, command string, w, errw io.Writer) error {
+	if command != "plan" && command != "run" {
+		return fmt.Errorf("unknown command %q (expected plan or run)", command)
+	}
+	ctx, cancel := context.WithTimeout(ctx, RetentionTimeout)
+	defer cancel()
+
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("load config: %w", err)
+	}
+	// OPTIMIZE FINAL on a large partition can outlast the read-oriented default.
+	cfg.ClickHouseMaxExecutionTime = 0
+	conn, err := openClickHouse(cfg)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	job := retention.NewJob(conn, retention.Policy{
+		DefaultMaxAge: cfg.Retention.DefaultMaxAge,
+		MaxAges:       cfg.Retention.MaxAges,
+		CompactWindow: cfg.Retention.CompactWindow,
+	})
+	actions, err := job.Plan(ctx)
+	if err != nil {
+		return err
+	}
+	if len(actions) == 0 {
+		fmt.Fprintln(w, "nothing to do")
+		return nil
+	}
+	if command == "plan" {
+		for _, action := range actions {
+			fmt.Fprintln(w, action)
+		}
+		return nil
+	}
+
+	recorder, err := auditRecorder(cfg, conn)
+	if err != nil {
+		return err
+	}
+	actor := os.Getenv("USER")
+	if actor == "" {
+		actor = "unknown"
+	}
+	return job.Apply(ctx, actions, func(action retention.Action) {
+		fmt.Fprintln(w, "done:", action)
+		recordCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), auditTimeout)
+		defer cancel()
+		if err := recorder.Record(recordCtx, auditEvent(actor, action)); err != nil {
+			fmt.Fprintln(errw, "retention: audit:", err)
+		}
+	})
+}
+
+func auditEvent(actor string, action retention.Action) audit.Event {
+	details := map[string]string{}
+	if action.Partition != "" {
+		details["partition"] = action.Partition
+	}
+	if action.Variable != "" {
+		details["variable"] = action.Variable
+	}
+	if len(action.Except) > 0 {
+		details["except"] = strings.Join(action.Except, ",")
+	}
+	if !action.Before.IsZero() {
+		details["before"] = action.Before.Format(time.RFC3339)
+	}
+	return audit.Event{
+		Time:    time.Now().UTC(),
+		Actor:   actor,
+		Action:  "retention." + string(action.Kind),
+		Target:  action.Table,
+		Details: details,
+	}
+}
+
+func auditRecorder(cfg *config.Config, conn driver.Conn) (audit.Recorder, error) {
+	if cfg.AuditLogFile == "" {
+		return audit.NewStore(conn), nil
+	}
+	// The command returns right after the run; the process exiting closes the file.
+	return audit.OpenFile(cfg.AuditLogFile)
+}
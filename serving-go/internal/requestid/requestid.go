@@ -0,0 +1,17 @@
+// Package requestid carries the per-request correlation id through contexts, so
+// layers below the HTTP handler can tag logs without depending on the api package.
+package requestid
+
+import "context"
+
+type contextKey struct{}
+
+func WithID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, contextKey{}, id)
+}
+
+// FromContext returns the request id, or "" outside a request.
+func FromContext(ctx context.Context) string {
+	id, _ := ctx.Value(contextKey{}).(string)
+	return id
+}
@@ -0,0 +1,260 @@
+package retention
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/ClickHouse/clickhouse-go/v2"
+	"github.com/ClickHouse/clickhouse-go/v2/lib/driver"
+
+	"github.com/kacper-wojtaszczyk/jackfruit/serving-go/internal/domain"
+)
+
+const (
+	tableGridData   = "grid_data"
+	tableGridLatest = "grid_latest"
+)
+
+// grid_data is partitioned by toYYYYMMDD(timestamp).
+const partitionLayout = "20060102"
+
+var partitionIDPattern = regexp.MustCompile(`^\d{8}$`)
+
+// Policy controls how grid data ages out. A zero max age keeps data forever.
+type Policy struct {
+	DefaultMaxAge time.Duration
+	// MaxAges overrides DefaultMaxAge per variable.
+	MaxAges map[string]time.Duration
+	// CompactWindow optimizes partitions of the last CompactWindow that hold more than one
+	// active part, so FINAL reads over fresh data stay cheap. Zero disables compaction.
+	CompactWindow time.Duration
+}
+
+// horizon is the longest max age, after which whole partitions can be dropped. ok is false
+// when some variable is kept forever, so no partition is guaranteed to be fully expired.
+func (p Policy) horizon() (time.Duration, bool) {
+	if p.DefaultMaxAge == 0 {
+		return 0, false
+	}
+	horizon := p.DefaultMaxAge
+	for _, age := range p.MaxAges {
+		if age == 0 {
+			return 0, false
+		}
+		horizon = max(horizon, age)
+	}
+	return horizon, true
+}
+
+type ActionKind string
+
+const (
+	DropPartition ActionKind = "drop_partition"
+	Delete        ActionKind = "delete"
+	Optimize      ActionKind = "optimize"
+)
+
+// Action is one maintenance statement. Deletes remove rows older than Before, of Variable
+// or, when Variable is empty, of every variable not in Except.
+type Action struct {
+	Kind      ActionKind
+	Table     string
+	Partition string
+	Variable  string
+	Except    []string
+	Before    time.Time
+}
+
+func (a Action) String() string {
+	switch a.Kind {
+	case DropPartition:
+		return fmt.Sprintf("drop partition %s of %s", a.Partition, a.Table)
+	case Optimize:
+		return fmt.Sprintf("optimize partition %s of %s", a.Partition, a.Table)
+	}
+	variables := "all variables"
+	if a.Variable != "" {
+		variables = a.Variable
+	} else if len(a.Except) > 0 {
+		variables = "variables other than " + strings.Join(a.Except, ", ")
+	}
+	return fmt.Sprintf("delete %s before %s from %s", variables, a.Before.Format(time.RFC3339), a.Table)
+}
+
+func (a Action) where() (string, []any) {
+	filters := []string{"timestamp < @before"}
+	args := []any{clickhouse.Named("before", a.Before)}
+	if a.Variable != "" {
+		filters = append(filters, "variable = @variable")
+		args = append(args, clickhouse.Named("variable", a.Variable))
+	} else if len(a.Except) > 0 {
+		filters = append(filters, "NOT has(@except, variable)")
+		args = append(args, clickhouse.Named("except", a.Except))
+	}
+	return strings.Join(filters, " AND "), args
+}
+
+func (a Action) statement() (string, []any) {
+	switch a.Kind {
+	case DropPartition:
+		return fmt.Sprintf("ALTER TABLE %s DROP PARTITION ID '%s'", a.Table, a.Partition), nil
+	case Optimize:
+		return fmt.Sprintf("OPTIMIZE TABLE %s PARTITION ID '%s' FINAL", a.Table, a.Partition), nil
+	}
+	where, args := a.where()
+	return fmt.Sprintf("ALTER TABLE %s DELETE WHERE %s", a.Table, where), args
+}
+
+// Partition is one grid_data partition, i.e. one UTC day of timestamps.
+type Partition struct {
+	ID    string
+	Day   time.Time
+	Parts uint64
+}
+
+// plan lists the actions policy calls for at now: partitions past the horizon are dropped,
+// shorter-lived variables are deleted row by row, and recent fragmented partitions are
+// optimized. grid_latest has no partitions and doesn't follow grid_data deletes, so every
+// age is applied to it as a delete.
+func (p Policy) plan(now time.Time, partitions []Partition) []Action {
+	var actions []Action
+	horizon, droppable := p.horizon()
+	dropped := make(map[string]bool)
+	if droppable {
+		before := now.Add(-horizon)
+		for _, partition := range partitions {
+			if !partition.Day.AddDate(0, 0, 1).After(before) {
+				actions = append(actions, Action{Kind: DropPartition, Table: tableGridData, Partition: partition.ID})
+				dropped[partition.ID] = true
+			}
+		}
+	}
+
+	listed := slices.Sorted(maps.Keys(p.MaxAges))
+	deletes := func(action Action, age time.Duration) {
+		action.Kind = Delete
+		action.Before = now.Add(-age)
+		if !droppable || age < horizon {
+			action.Table = tableGridData
+			actions = append(actions, action)
+		}
+		action.Table = tableGridLatest
+		actions = append(actions, action)
+	}
+	for _, variable := range listed {
+		if age := p.MaxAges[variable]; age > 0 {
+			deletes(Action{Variable: variable}, age)
+		}
+	}
+	if p.DefaultMaxAge > 0 {
+		deletes(Action{Except: listed}, p.DefaultMaxAge)
+	}
+
+	if p.CompactWindow > 0 {
+		since := now.Add(-p.CompactWindow)
+		for _, partition := range partitions {
+			if partition.Parts > 1 && !dropped[partition.ID] && partition.Day.AddDate(0, 0, 1).After(since) {
+				actions = append(actions, Action{Kind: Optimize, Table: tableGridData, Partition: partition.ID})
+			}
+		}
+	}
+
+	return actions
+}
+
+// Job applies a retention Policy to grid_data and grid_latest.
+type Job struct {
+	conn   driver.Conn
+	policy Policy
+	now    func() time.Time
+}
+
+// NewJob returns a Job for policy. Variable names in policy.MaxAges are canonicalized.
+func NewJob(conn driver.Conn, policy Policy) *Job {
+	maxAges := make(map[string]time.Duration, len(policy.MaxAges))
+	for variable, age := range policy.MaxAges {
+		maxAges[domain.CanonicalVariable(variable)] = age
+	}
+	policy.MaxAges = maxAges
+	return &Job{conn: conn, policy: policy, now: time.Now}
+}
+
+// Plan returns the actions a run would take now. Deletes that match no rows are left out,
+// so repeated runs don't queue empty mutations.
+func (j *Job) Plan(ctx context.Context) ([]Action, error) {
+	partitions, err := j.partitions(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var actions []Action
+	for _, action := range j.policy.plan(j.now().UTC(), partitions) {
+		if action.Kind == Delete {
+			where, args := action.where()
+			var rows uint64
+			query := fmt.Sprintf("SELECT count() FROM %s WHERE %s", action.Table, where)
+			if err := j.conn.QueryRow(ctx, query, args...).Scan(&rows); err != nil {
+				return nil, fmt.Errorf("count rows to %s: %w", action, err)
+			}
+			if rows == 0 {
+				continue
+			}
+		}
+		actions = append(actions, action)
+	}
+
+	return actions, nil
+}
+
+// Apply executes actions in order, calling applied after each one completes so callers can
+// record it before the next starts; a failed action stops the rest. Deletes are
+// asynchronous mutations: they are queued when applied is called, not necessarily finished.
+func (j *Job) Apply(ctx context.Context, actions []Action, applied func(Action)) error {
+	for _, action := range actions {
+		statement, args := action.statement()
+		if err := j.conn.Exec(ctx, statement, args...); err != nil {
+			return fmt.Errorf("%s: %w", action, err)
+		}
+		applied(action)
+	}
+	return nil
+}
+
+func (j *Job) partitions(ctx context.Context) ([]Partition, error) {
+	rows, err := j.conn.Query(ctx, `
+        SELECT partition_id, count()
+        FROM system.parts
+        WHERE database = currentDatabase() AND table = @table AND active
+        GROUP BY partition_id
+        ORDER BY partition_id
+    `, clickhouse.Named("table", tableGridData))
+	if err != nil {
+		return nil, fmt.Errorf("list %s partitions: %w", tableGridData, err)
+	}
+	defer rows.Close()
+
+	var partitions []Partition
+	for rows.Next() {
+		var partition Partition
+		if err := rows.Scan(&partition.ID, &partition.Parts); err != nil {
+			return nil, fmt.Errorf("scan partition: %w", err)
+		}
+		if !partitionIDPattern.MatchString(partition.ID) {
+			return nil, fmt.Errorf("unexpected %s partition id %q", tableGridData, partition.ID)
+		}
+		if partition.Day, err = time.Parse(partitionLayout, partition.ID); err != nil {
+			return nil, fmt.Errorf("parse partition id %q: %w", partition.ID, err)
+		}
+		partitions = append(partitions, partition)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate partitions: %w", err)
+	}
+
+	return partitions, nil
+}
@@ -0,0 +1,91 @@
+package retention
+
+import (
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+var now = time.Date(2025, 3, 31, 12, 0, 0, 0, time.UTC)
+
+func day(id string) Partition {
+	d, err := time.Parse(partitionLayout, id)
+	if err != nil {
+		panic(err)
+	}
+	return Partition{ID: id, Day: d, Parts: 1}
+}
+
+func actionStrings(actions []Action) []string {
+	s := make([]string, len(actions))
+	for i, a := range actions {
+		s[i] = a.String()
+	}
+	return s
+}
+
+func TestPlan_DropsPartitionsPastHorizon(t *testing.T) {
+	policy := Policy{DefaultMaxAge: 10 * 24 * time.Hour, MaxAges: map[string]time.Duration{"pm10": 3 * 24 * time.Hour}}
+	partitions := []Partition{day("20250320"), day("20250321"), day("20250322")}
+
+	got := actionStrings(policy.plan(now, partitions))
+	want := []string{
+		"drop partition 20250320 of grid_data",
+		"delete pm10 before 2025-03-28T12:00:00Z from grid_data",
+		"delete pm10 before 2025-03-28T12:00:00Z from grid_latest",
+		"delete variables other than pm10 before 2025-03-21T12:00:00Z from grid_latest",
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("plan mismatch\n got: %s\nwant: %s", strings.Join(got, "; "), strings.Join(want, "; "))
+	}
+}
+
+func TestPlan_KeepForeverDisablesDrops(t *testing.T) {
+	policy := Policy{DefaultMaxAge: 24 * time.Hour, MaxAges: map[string]time.Duration{"pm2p5": 0}}
+
+	got := actionStrings(policy.plan(now, []Partition{day("20250101")}))
+	want := []string{
+		"delete variables other than pm2p5 before 2025-03-30T12:00:00Z from grid_data",
+		"delete variables other than pm2p5 before 2025-03-30T12:00:00Z from grid_latest",
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("plan mismatch\n got: %s\nwant: %s", strings.Join(got, "; "), strings.Join(want, "; "))
+	}
+}
+
+func TestPlan_OptimizesRecentFragmentedPartitions(t *testing.T) {
+	fragmented, merged, old := day("20250331"), day("20250330"), day("20250325")
+	fragmented.Parts, old.Parts = 4, 3
+	policy := Policy{CompactWindow: 48 * time.Hour}
+
+	got := actionStrings(policy.plan(now, []Partition{old, merged, fragmented}))
+	want := []string{"optimize partition 20250331 of grid_data"}
+	if !slices.Equal(got, want) {
+		t.Errorf("plan mismatch\n got: %s\nwant: %s", strings.Join(got, "; "), strings.Join(want, "; "))
+	}
+}
+
+func TestPlan_ZeroPolicyDoesNothing(t *testing.T) {
+	if actions := (Policy{}).plan(now, []Partition{day("20200101")}); len(actions) != 0 {
+		t.Errorf("expected no actions, got %v", actionStrings(actions))
+	}
+}
+
+func TestAction_Statement(t *testing.T) {
+	tests := []struct {
+		action Action
+		want   string
+	}{
+		{Action{Kind: DropPartition, Table: "grid_data", Partition: "20250320"}, "ALTER TABLE grid_data DROP PARTITION ID '20250320'"},
+		{Action{Kind: Optimize, Table: "grid_data", Partition: "20250331"}, "OPTIMIZE TABLE grid_data PARTITION ID '20250331' FINAL"},
+		{Action{Kind: Delete, Table: "grid_latest", Variable: "pm10"}, "ALTER TABLE grid_latest DELETE WHERE timestamp < @before AND variable = @variable"},
+		{Action{Kind: Delete, Table: "grid_data", Except: []string{"pm10"}}, "ALTER TABLE grid_data DELETE WHERE timestamp < @before AND NOT has(@except, variable)"},
+		{Action{Kind: Delete, Table: "grid_data"}, "ALTER TABLE grid_data DELETE WHERE timestamp < @before"},
+	}
+	for _, tt := range tests {
+		if got, _ := tt.action.statement(); got != tt.want {
+			t.Errorf("statement() = %q, want %q", got, tt.want)
+		}
+	}
+}
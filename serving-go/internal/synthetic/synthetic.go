@@ -0,0 +1,164 @@
+// Package synthetic generates plausible grid data for local development and load tests,
+// so a stack can be exercised without ADS downloads.
+package synthetic
+
+import (
+	"errors"
+	"fmt"
+	"iter"
+	"math"
+	"math/rand/v2"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/kacper-wojtaszczyk/jackfruit/serving-go/internal/domain"
+)
+
+// Field describes how one variable's values are generated: Base at the extent's centre,
+// plus per-degree gradients, a diurnal cycle and Gaussian noise.
+type Field struct {
+	Variable string
+	Unit     string
+	Base     float64
+	// LatGradient and LonGradient are added per degree north and east of the centre.
+	LatGradient float64
+	LonGradient float64
+	// DiurnalAmplitude scales a 24h sine wave peaking at 15:00 UTC.
+	DiurnalAmplitude float64
+	// Noise is the standard deviation of the noise added to each value.
+	Noise float64
+	// NonNegative clamps values at zero, as for concentrations.
+	NonNegative bool
+}
+
+var presets = map[string]Field{
+	"pm2p5":       {Unit: "µg/m³", Base: 12, LatGradient: -0.2, DiurnalAmplitude: 4, Noise: 2, NonNegative: true},
+	"pm10":        {Unit: "µg/m³", Base: 20, LatGradient: -0.3, DiurnalAmplitude: 6, Noise: 3, NonNegative: true},
+	"no2":         {Unit: "µg/m³", Base: 25, LonGradient: 0.1, DiurnalAmplitude: -8, Noise: 4, NonNegative: true},
+	"o3":          {Unit: "µg/m³", Base: 60, LatGradient: -0.5, DiurnalAmplitude: 20, Noise: 5, NonNegative: true},
+	"temperature": {Unit: "°C", Base: 12, LatGradient: -0.7, DiurnalAmplitude: 5, Noise: 0.5},
+}
+
+// Preset returns a plausible Field for a known variable (aliases resolve) and a generic
+// non-negative one otherwise.
+func Preset(variable string) Field {
+	variable = domain.CanonicalVariable(variable)
+	field, ok := presets[variable]
+	if !ok {
+		field = Field{Unit: "1", Base: 10, DiurnalAmplitude: 2, Noise: 1, NonNegative: true}
+	}
+	field.Variable = variable
+	return field
+}
+
+// Spec is a regular lat/lon grid sampled at Steps timestamps Step apart, starting at From.
+type Spec struct {
+	Extent     domain.BoundingBox
+	Resolution float32
+	From       time.Time
+	Step       time.Duration
+	Steps      int
+	Fields     []Field
+	// Seed makes output reproducible; equal specs generate equal values.
+	Seed uint64
+}
+
+func (s Spec) Validate() error {
+	if s.Extent.MinLat > s.Extent.MaxLat || s.Extent.MinLon > s.Extent.MaxLon {
+		return errors.New("extent minimum must not exceed its maximum")
+	}
+	if s.Extent.MinLat < -90 || s.Extent.MaxLat > 90 || s.Extent.MinLon < -180 || s.Extent.MaxLon > 180 {
+		return errors.New("extent must lie within lat -90..90 and lon -180..180")
+	}
+	if s.Resolution <= 0 {
+		return fmt.Errorf("resolution must be positive, %v given", s.Resolution)
+	}
+	if s.Steps < 1 {
+		return fmt.Errorf("steps must be at least 1, %d given", s.Steps)
+	}
+	if s.Steps > 1 && s.Step <= 0 {
+		return fmt.Errorf("step must be positive, %s given", s.Step)
+	}
+	if len(s.Fields) == 0 {
+		return errors.New("at least one field is required")
+	}
+	return nil
+}
+
+// axis returns the grid coordinates from lo to hi inclusive, computed by index so long
+// axes don't accumulate rounding drift.
+func axis(lo, hi, resolution float32) []float32 {
+	n := int(math.Floor(float64(hi-lo)/float64(resolution)+1e-6)) + 1
+	coords := make([]float32, n)
+	for i := range coords {
+		coords[i] = float32(math.Round((float64(lo)+float64(i)*float64(resolution))*1e6) / 1e6)
+	}
+	return coords
+}
+
+// Cells is the number of grid cells per variable and timestamp.
+func (s Spec) Cells() int {
+	return len(axis(s.Extent.MinLat, s.Extent.MaxLat, s.Resolution)) * len(axis(s.Extent.MinLon, s.Extent.MaxLon, s.Resolution))
+}
+
+// Slice is one variable at one timestamp, which the pipeline records as one catalog entry.
+type Slice struct {
+	Variable  string
+	Unit      string
+	Timestamp time.Time
+	CatalogID uuid.UUID
+	Values    []domain.GridValue
+}
+
+// Slices generates the grid one variable and timestamp at a time, each with a fresh
+// UUIDv7 catalog id. The spec must be valid.
+func (s Spec) Slices() iter.Seq[Slice] {
+	return func(yield func(Slice) bool) {
+		rng := rand.New(rand.NewPCG(s.Seed, s.Seed^0x9e3779b97f4a7c15))
+		lats := axis(s.Extent.MinLat, s.Extent.MaxLat, s.Resolution)
+		lons := axis(s.Extent.MinLon, s.Extent.MaxLon, s.Resolution)
+		centreLat := float64(s.Extent.MinLat+s.Extent.MaxLat) / 2
+		centreLon := float64(s.Extent.MinLon+s.Extent.MaxLon) / 2
+
+		for step := range s.Steps {
+			ts := s.From.UTC().Add(time.Duration(step) * s.Step)
+			hours := float64(ts.Hour()) + float64(ts.Minute())/60
+			diurnal := math.Sin((hours - 9) / 24 * 2 * math.Pi)
+
+			for _, field := range s.Fields {
+				slice := Slice{
+					Variable:  field.Variable,
+					Unit:      field.Unit,
+					Timestamp: ts,
+					CatalogID: uuid.Must(uuid.NewV7()),
+					Values:    make([]domain.GridValue, 0, len(lats)*len(lons)),
+				}
+				for _, lat := range lats {
+					for _, lon := range lons {
+						value := field.Base +
+							field.LatGradient*(float64(lat)-centreLat) +
+							field.LonGradient*(float64(lon)-centreLon) +
+							field.DiurnalAmplitude*diurnal +
+							field.Noise*rng.NormFloat64()
+						if field.NonNegative {
+							value = max(value, 0)
+						}
+						slice.Values = append(slice.Values, domain.GridValue{
+							Variable:  field.Variable,
+							Timestamp: ts,
+							Lat:       lat,
+							Lon:       lon,
+							Value:     float32(value),
+							Unit:      field.Unit,
+							CatalogID: slice.CatalogID,
+						})
+					}
+				}
+				if !yield(slice) {
+					return
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,91 @@
+package synthetic
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/kacper-wojtaszczyk/jackfruit/serving-go/internal/domain"
+)
+
+var testSpec = Spec{
+	Extent:     domain.BoundingBox{MinLat: 50, MinLon: 10, MaxLat: 51, MaxLon: 12},
+	Resolution: 0.5,
+	From:       time.Date(2025, 3, 11, 0, 0, 0, 0, time.UTC),
+	Step:       time.Hour,
+	Steps:      3,
+	Fields:     []Field{Preset("PM2.5"), {Variable: "flat", Unit: "1", Base: 7}},
+	Seed:       1,
+}
+
+func TestSpec_Slices(t *testing.T) {
+	if err := testSpec.Validate(); err != nil {
+		t.Fatalf("Validate returned error: %v", err)
+	}
+	if got := testSpec.Cells(); got != 15 {
+		t.Errorf("expected 3 x 5 cells, got %d", got)
+	}
+
+	got := slices.Collect(testSpec.Slices())
+	if len(got) != 6 {
+		t.Fatalf("expected 3 timestamps x 2 fields, got %d slices", len(got))
+	}
+	first, last := got[0], got[5]
+	if first.Variable != "pm2p5" || !first.Timestamp.Equal(testSpec.From) || len(first.Values) != 15 {
+		t.Errorf("unexpected first slice %s %s with %d values", first.Variable, first.Timestamp, len(first.Values))
+	}
+	if v := first.Values[len(first.Values)-1]; v.Lat != 51 || v.Lon != 12 || v.CatalogID != first.CatalogID {
+		t.Errorf("expected last cell at (51, 12) with the slice's catalog id, got %+v", v)
+	}
+	if last.Variable != "flat" || !last.Timestamp.Equal(testSpec.From.Add(2*time.Hour)) {
+		t.Errorf("unexpected last slice %s %s", last.Variable, last.Timestamp)
+	}
+	for _, v := range last.Values {
+		if v.Value != 7 {
+			t.Fatalf("expected a field without gradients or noise to be constant, got %v", v.Value)
+		}
+	}
+	for _, v := range first.Values {
+		if v.Value < 0 {
+			t.Fatalf("expected non-negative pm2p5, got %v", v.Value)
+		}
+	}
+}
+
+func TestSpec_SlicesAreReproducible(t *testing.T) {
+	values := func() []float32 {
+		var out []float32
+		for slice := range testSpec.Slices() {
+			for _, v := range slice.Values {
+				out = append(out, v.Value)
+			}
+		}
+		return out
+	}
+	if !slices.Equal(values(), values()) {
+		t.Error("expected equal specs to generate equal values")
+	}
+}
+
+func TestSpec_Validate(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(*Spec)
+	}{
+		{name: "inverted extent", modify: func(s *Spec) { s.Extent.MinLat = 60 }},
+		{name: "out of range extent", modify: func(s *Spec) { s.Extent.MaxLon = 190 }},
+		{name: "zero resolution", modify: func(s *Spec) { s.Resolution = 0 }},
+		{name: "no steps", modify: func(s *Spec) { s.Steps = 0 }},
+		{name: "zero step", modify: func(s *Spec) { s.Step = 0 }},
+		{name: "no fields", modify: func(s *Spec) { s.Fields = nil }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			spec := testSpec
+			tt.modify(&spec)
+			if err := spec.Validate(); err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+}
@@ -2,7 +2,7 @@ package testutil
 
 import (
 	"context"
-	"fmt"
+	"log/slog"
 	"testing"
 	"time"
 
@@ -11,20 +11,22 @@ import (
 	"github.com/google/uuid"
 
 	"github.com/kacper-wojtaszczyk/jackfruit/serving-go/internal/config"
+	"github.com/kacper-wojtaszczyk/jackfruit/serving-go/internal/domain"
+	"github.com/kacper-wojtaszczyk/jackfruit/serving-go/internal/grid"
 )
 
 func NewRawConn(t *testing.T) chdriver.Conn {
 	t.Helper()
 
-	cfg := config.Load()
-	conn, err := clickhouseraw.Open(&clickhouseraw.Options{
-		Addr: []string{fmt.Sprintf("%s:%s", cfg.ClickHouseHost, cfg.ClickHousePort)},
-		Auth: clickhouseraw.Auth{
-			Database: cfg.ClickHouseDatabase,
-			Username: cfg.ClickHouseUser,
-			Password: cfg.ClickHousePassword,
-		},
-	})
+	cfg, err := config.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	options, err := grid.Options(cfg, slog.New(slog.DiscardHandler))
+	if err != nil {
+		t.Fatal(err)
+	}
+	conn, err := clickhouseraw.Open(options)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -45,18 +47,15 @@ func InsertGridRow(t *testing.T, conn chdriver.Conn, variable string, value floa
 		t.Fatal(err)
 	}
 
-	err = conn.Exec(t.Context(), `
-		INSERT INTO grid_data (variable, timestamp, lat, lon, value, unit, catalog_id)
-		VALUES (@variable, @timestamp, @lat, @lon, @value, @unit, @catalog_id)
-	`,
-		clickhouseraw.Named("variable", variable),
-		clickhouseraw.Named("timestamp", timestamp),
-		clickhouseraw.Named("lat", lat),
-		clickhouseraw.Named("lon", lon),
-		clickhouseraw.Named("value", value),
-		clickhouseraw.Named("unit", unit),
-		clickhouseraw.Named("catalog_id", catalogID),
-	)
+	err = grid.NewWriter(conn).InsertGridValues(t.Context(), []domain.GridValue{{
+		Variable:  variable,
+		Timestamp: timestamp,
+		Lat:       lat,
+		Lon:       lon,
+		Value:     value,
+		Unit:      unit,
+		CatalogID: catalogID,
+	}})
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -66,6 +65,12 @@ func InsertGridRow(t *testing.T, conn chdriver.Conn, variable string, value floa
 			"ALTER TABLE grid_data DELETE WHERE catalog_id = @catalog_id",
 			clickhouseraw.Named("catalog_id", catalogID),
 		)
+		// Deletes don't propagate through the grid_latest materialized view; the table
+		// may not exist if migrations haven't run, so the error is ignored.
+		_ = conn.Exec(context.Background(),
+			"ALTER TABLE grid_latest DELETE WHERE catalog_id = @catalog_id",
+			clickhouseraw.Named("catalog_id", catalogID),
+		)
 	})
 
 	return catalogID